@@ -0,0 +1,399 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import "fmt"
+
+// Channel medium types per section 6.5
+const (
+	ChannelMediumIPMB     = 0x01
+	ChannelMediumICMB10   = 0x02
+	ChannelMediumICMB09   = 0x03
+	ChannelMediumLAN      = 0x04 // 802.3 LAN
+	ChannelMediumSerial   = 0x05 // asynchronous serial/modem
+	ChannelMediumOtherLAN = 0x06
+	ChannelMediumPCISMBus = 0x07
+	ChannelMediumSMBus11  = 0x08
+	ChannelMediumSMBus20  = 0x09
+	ChannelMediumUSB1     = 0x0a
+	ChannelMediumUSB2     = 0x0b
+	ChannelMediumSystem   = 0x0c // system interface: KCS, SMIC or BT
+	ChannelMediumOEMFirst = 0x60
+	ChannelMediumOEMLast  = 0x7f
+)
+
+// Channel protocol types per section 6.4
+const (
+	ChannelProtocolIPMB     = 0x01
+	ChannelProtocolICMB     = 0x02
+	ChannelProtocolSMBus    = 0x04
+	ChannelProtocolKCS      = 0x05
+	ChannelProtocolSMIC     = 0x06
+	ChannelProtocolBT10     = 0x07
+	ChannelProtocolBT15     = 0x08
+	ChannelProtocolTMode    = 0x09
+	ChannelProtocolOEMFirst = 0x1c
+	ChannelProtocolOEMLast  = 0x1f
+)
+
+// Channel session support per section 22.24
+const (
+	ChannelSessionless   = 0x0
+	ChannelSingleSession = 0x1
+	ChannelMultiSession  = 0x2
+	ChannelSessionBased  = 0x3
+)
+
+// Channel numbers per section 6.3
+const (
+	ChannelPrimaryIPMB = 0x00
+	ChannelSystem      = 0x0f // the system interface
+	channelNumberMax   = 0x0b // 0x0c and 0x0d are reserved, 0x0e is ChannelCurrent
+)
+
+// Get and Set Channel Access selectors per section 22.22
+const (
+	ChannelAccessNonVolatile = 0x40
+	ChannelAccessVolatile    = 0x80 // the present volatile setting
+)
+
+// Channel access modes per section 6.6
+const (
+	ChannelAccessDisabled = 0x0
+	ChannelAccessPreBoot  = 0x1 // pre-boot only
+	ChannelAccessAlways   = 0x2 // always available
+	ChannelAccessShared   = 0x3
+)
+
+// Auth capabilities status bits per section 22.13
+const (
+	AuthStatusAnonymousLogin = 0x01 // null user name and null password
+	AuthStatusNullUsers      = 0x02 // null user names enabled
+	AuthStatusNonNullUsers   = 0x04
+	AuthStatusUserLevelOff   = 0x08 // user level authentication disabled
+	AuthStatusPerMessageOff  = 0x10 // per-message authentication disabled
+	AuthStatusKG             = 0x20 // KG is set to a non-zero value
+)
+
+var channelMediumNames = map[uint8]string{
+	ChannelMediumIPMB:     "IPMB (I2C)",
+	ChannelMediumICMB10:   "ICMB v1.0",
+	ChannelMediumICMB09:   "ICMB v0.9",
+	ChannelMediumLAN:      "802.3 LAN",
+	ChannelMediumSerial:   "Serial/Modem (RS-232)",
+	ChannelMediumOtherLAN: "Other LAN",
+	ChannelMediumPCISMBus: "PCI SMBus",
+	ChannelMediumSMBus11:  "SMBus v1.0/v1.1",
+	ChannelMediumSMBus20:  "SMBus v2.0",
+	ChannelMediumUSB1:     "USB 1.x",
+	ChannelMediumUSB2:     "USB 2.x",
+	ChannelMediumSystem:   "System Interface (KCS, SMIC, or BT)",
+}
+
+var channelProtocolNames = map[uint8]string{
+	ChannelProtocolIPMB:  "IPMB-1.0",
+	ChannelProtocolICMB:  "ICMB-1.0",
+	ChannelProtocolSMBus: "IPMI-SMBus",
+	ChannelProtocolKCS:   "KCS",
+	ChannelProtocolSMIC:  "SMIC",
+	ChannelProtocolBT10:  "BT-10",
+	ChannelProtocolBT15:  "BT-15",
+	ChannelProtocolTMode: "TMode",
+}
+
+var channelSessionNames = []string{"session-less", "single-session", "multi-session", "session-based"}
+
+// ChannelMediumString describes a channel medium type
+func ChannelMediumString(medium uint8) string {
+	if s, ok := channelMediumNames[medium]; ok {
+		return s
+	}
+	if medium >= ChannelMediumOEMFirst && medium <= ChannelMediumOEMLast {
+		return "OEM"
+	}
+	return fmt.Sprintf("reserved (0x%02x)", medium)
+}
+
+// ChannelProtocolString describes a channel protocol type
+func ChannelProtocolString(protocol uint8) string {
+	if s, ok := channelProtocolNames[protocol]; ok {
+		return s
+	}
+	if protocol >= ChannelProtocolOEMFirst && protocol <= ChannelProtocolOEMLast {
+		return "OEM"
+	}
+	return fmt.Sprintf("reserved (0x%02x)", protocol)
+}
+
+// MarshalBinary implementation to handle the 3 byte OEM ID
+func (r *AuthCapabilitiesResponse) MarshalBinary() ([]byte, error) {
+	return []byte{
+		byte(r.CompletionCode),
+		r.ChannelNumber,
+		r.AuthTypeSupport,
+		r.Status,
+		r.ExtCapabilities,
+		byte(r.OEMID),
+		byte(r.OEMID >> 8),
+		byte(r.OEMID>>16) & 0x0f,
+		r.OEMAux,
+	}, nil
+}
+
+// UnmarshalBinary implementation to handle the 3 byte OEM ID
+func (r *AuthCapabilitiesResponse) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 9 {
+		return ErrShortPacket
+	}
+	r.CompletionCode = CompletionCode(buf[0])
+	r.ChannelNumber = buf[1]
+	r.AuthTypeSupport = buf[2]
+	r.Status = buf[3]
+	r.ExtCapabilities = buf[4]
+	r.OEMID = OemID(uint32(buf[5]) | uint32(buf[6])<<8 | uint32(buf[7]&0x0f)<<16)
+	r.OEMAux = buf[8]
+	return nil
+}
+
+// SupportsAuthType tells whether the auth type is supported at the requested privilege level
+func (r *AuthCapabilitiesResponse) SupportsAuthType(authType uint8) bool {
+	return r.AuthTypeSupport&0x3f&(1<<authType) != 0
+}
+
+// AllowsAnonymous tells whether the null user can log in
+func (r *AuthCapabilitiesResponse) AllowsAnonymous() bool {
+	return r.Status&(AuthStatusAnonymousLogin|AuthStatusNullUsers) != 0
+}
+
+// SupportsIPMI15 tells whether the channel supports IPMI v1.5 sessions
+func (r *AuthCapabilitiesResponse) SupportsIPMI15() bool {
+	// without the v2.0 extended data only v1.5 is supported
+	return r.AuthTypeSupport&0x80 == 0 || r.ExtCapabilities&0x01 != 0
+}
+
+// SupportsIPMI20 tells whether the channel supports IPMI v2.0 RMCP+ sessions
+func (r *AuthCapabilitiesResponse) SupportsIPMI20() bool {
+	return r.AuthTypeSupport&0x80 != 0 && r.ExtCapabilities&0x02 != 0
+}
+
+// ChannelInfoRequest per section 22.24
+type ChannelInfoRequest struct {
+	Channel uint8
+}
+
+// ChannelInfoResponse per section 22.24
+type ChannelInfoResponse struct {
+	CompletionCode
+	Channel        uint8
+	MediumType     uint8
+	ProtocolType   uint8
+	SessionSupport uint8 // [7:6] session support, [5:0] active session count
+	VendorID       [3]uint8
+	AuxInfo        uint16
+}
+
+// Medium is the channel medium type
+func (r *ChannelInfoResponse) Medium() uint8 {
+	return r.MediumType & 0x7f
+}
+
+// Protocol is the channel protocol type
+func (r *ChannelInfoResponse) Protocol() uint8 {
+	return r.ProtocolType & 0x1f
+}
+
+// Sessions is the session support, one of the Channel* session values
+func (r *ChannelInfoResponse) Sessions() uint8 {
+	return r.SessionSupport >> 6
+}
+
+// SessionsString describes the session support
+func (r *ChannelInfoResponse) SessionsString() string {
+	return channelSessionNames[r.Sessions()]
+}
+
+// ActiveSessions is the count of active sessions on the channel
+func (r *ChannelInfoResponse) ActiveSessions() uint8 {
+	return r.SessionSupport & 0x3f
+}
+
+// Vendor is the IANA enterprise number of the protocol owner
+func (r *ChannelInfoResponse) Vendor() OemID {
+	return OemID(uint32(r.VendorID[0]) | uint32(r.VendorID[1])<<8 | uint32(r.VendorID[2]&0x0f)<<16)
+}
+
+// ChannelAccess is the access configuration of a channel
+type ChannelAccess struct {
+	Mode           uint8 // one of the ChannelAccess* modes
+	UserLevelAuth  bool
+	PerMessageAuth bool
+	PEFAlerting    bool
+	PrivilegeLimit uint8
+}
+
+// ChannelAccessRequest per section 22.23
+type ChannelAccessRequest struct {
+	Channel uint8
+	Type    uint8 // [7:6] 01b non-volatile, 10b present volatile setting
+}
+
+// ChannelAccessResponse per section 22.23
+type ChannelAccessResponse struct {
+	CompletionCode
+	Access    uint8 // [5] PEF alerting disabled, [4] per-message auth disabled, [3] user level auth disabled, [2:0] mode
+	Privilege uint8
+}
+
+// ChannelAccess decodes the access configuration
+func (r *ChannelAccessResponse) ChannelAccess() *ChannelAccess {
+	return &ChannelAccess{
+		Mode:           r.Access & 0x07,
+		UserLevelAuth:  r.Access&0x08 == 0,
+		PerMessageAuth: r.Access&0x10 == 0,
+		PEFAlerting:    r.Access&0x20 == 0,
+		PrivilegeLimit: r.Privilege & 0x0f,
+	}
+}
+
+func (a *ChannelAccess) bits() uint8 {
+	b := a.Mode & 0x07
+	if !a.UserLevelAuth {
+		b |= 0x08
+	}
+	if !a.PerMessageAuth {
+		b |= 0x10
+	}
+	if !a.PEFAlerting {
+		b |= 0x20
+	}
+	return b
+}
+
+// SetChannelAccessRequest per section 22.22
+type SetChannelAccessRequest struct {
+	Channel   uint8
+	Access    uint8 // [7:6] 01b set non-volatile, 10b set volatile, then as in ChannelAccessResponse
+	Privilege uint8 // [7:6] 01b set non-volatile, 10b set volatile, [3:0] privilege limit
+}
+
+// SetChannelAccessResponse per section 22.22
+type SetChannelAccessResponse struct {
+	CompletionCode
+}
+
+// Cipher suite algorithms per section 13.28
+const (
+	CipherAuthNone       = 0x00
+	CipherAuthHMACSHA1   = 0x01
+	CipherAuthHMACMD5    = 0x02
+	CipherAuthHMACSHA256 = 0x03
+
+	CipherIntegrityNone           = 0x00
+	CipherIntegrityHMACSHA1_96    = 0x01
+	CipherIntegrityHMACMD5_128    = 0x02
+	CipherIntegrityMD5_128        = 0x03
+	CipherIntegrityHMACSHA256_128 = 0x04
+
+	CipherConfidentialityNone      = 0x00
+	CipherConfidentialityAESCBC128 = 0x01
+	CipherConfidentialityXRC4_128  = 0x02
+	CipherConfidentialityXRC4_40   = 0x03
+
+	cipherSuiteRecord    = 0xc0
+	cipherSuiteRecordOEM = 0xc1
+	cipherSuitePageSize  = 16
+)
+
+// CipherSuite is a cipher suite record per section 22.15.2
+type CipherSuite struct {
+	ID              uint8
+	OEM             OemID // for OEM cipher suites
+	Auth            uint8
+	Integrity       []uint8
+	Confidentiality []uint8
+}
+
+// ChannelCipherSuitesRequest per section 22.15
+type ChannelCipherSuitesRequest struct {
+	Channel     uint8
+	PayloadType uint8
+	Index       uint8 // [7] list by cipher suite, [5:0] page of 16 bytes
+}
+
+// ChannelCipherSuitesResponse per section 22.15
+type ChannelCipherSuitesResponse struct {
+	CompletionCode
+	Channel uint8
+	Data    []uint8 // up to 16 bytes of cipher suite records
+}
+
+// MarshalBinary implementation to handle variable length Data
+func (r *ChannelCipherSuitesResponse) MarshalBinary() ([]byte, error) {
+	return append([]byte{byte(r.CompletionCode), r.Channel}, r.Data...), nil
+}
+
+// UnmarshalBinary implementation to handle variable length Data
+func (r *ChannelCipherSuitesResponse) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 2 {
+		return ErrShortPacket
+	}
+	r.CompletionCode = CompletionCode(buf[0])
+	r.Channel = buf[1]
+	r.Data = buf[2:]
+	return nil
+}
+
+// parseCipherSuites decodes the cipher suite records listed by cipher suite
+func parseCipherSuites(data []byte) ([]*CipherSuite, error) {
+	var suites []*CipherSuite
+	for len(data) > 0 {
+		suite := &CipherSuite{}
+		switch data[0] {
+		case cipherSuiteRecord:
+			if len(data) < 2 {
+				return nil, ErrShortPacket
+			}
+			suite.ID = data[1]
+			data = data[2:]
+		case cipherSuiteRecordOEM:
+			if len(data) < 5 {
+				return nil, ErrShortPacket
+			}
+			suite.ID = data[1]
+			suite.OEM = OemID(uint32(data[2]) | uint32(data[3])<<8 | uint32(data[4]&0x0f)<<16)
+			data = data[5:]
+		default:
+			return nil, ErrInvalidPacket
+		}
+
+		// algorithm bytes up to the next record, tagged by their top 2 bits
+		for len(data) > 0 && data[0]&0xc0 != 0xc0 {
+			alg := data[0] & 0x3f
+			switch data[0] >> 6 {
+			case 0:
+				suite.Auth = alg
+			case 1:
+				suite.Integrity = append(suite.Integrity, alg)
+			case 2:
+				suite.Confidentiality = append(suite.Confidentiality, alg)
+			}
+			data = data[1:]
+		}
+		suites = append(suites, suite)
+	}
+	return suites, nil
+}
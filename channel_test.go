@@ -0,0 +1,96 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthCapabilitiesParse(t *testing.T) {
+	res := &AuthCapabilitiesResponse{}
+	err := responseFromString("01 95 16 03 b9 1b 00 00", res)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(1), res.ChannelNumber)
+	assert.True(t, res.SupportsAuthType(AuthTypeMD5))
+	assert.True(t, res.SupportsAuthType(AuthTypePassword))
+	assert.False(t, res.SupportsAuthType(AuthTypeMD2))
+	assert.True(t, res.AllowsAnonymous())
+	assert.True(t, res.Status&AuthStatusPerMessageOff != 0)
+	assert.True(t, res.SupportsIPMI15())
+	assert.True(t, res.SupportsIPMI20())
+	assert.Equal(t, OemID(7097), res.OEMID)
+
+	// v1.5 BMC, no extended data
+	err = responseFromString("01 15 04 00 00 00 00 00", res)
+	assert.NoError(t, err)
+	assert.False(t, res.AllowsAnonymous())
+	assert.True(t, res.SupportsIPMI15())
+	assert.False(t, res.SupportsIPMI20())
+}
+
+func TestChannelInfoParse(t *testing.T) {
+	res := &ChannelInfoResponse{}
+	err := responseFromString("01 04 01 82 f2 1b 00 00 00", res)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(ChannelMediumLAN), res.Medium())
+	assert.Equal(t, "802.3 LAN", ChannelMediumString(res.Medium()))
+	assert.Equal(t, "IPMB-1.0", ChannelProtocolString(res.Protocol()))
+	assert.Equal(t, uint8(ChannelMultiSession), res.Sessions())
+	assert.Equal(t, "multi-session", res.SessionsString())
+	assert.Equal(t, uint8(2), res.ActiveSessions())
+	assert.Equal(t, OemID(7154), res.Vendor())
+
+	assert.Equal(t, "OEM", ChannelMediumString(0x61))
+	assert.Equal(t, "reserved (0x0d)", ChannelMediumString(0x0d))
+}
+
+func TestChannelAccessParse(t *testing.T) {
+	res := &ChannelAccessResponse{}
+	err := responseFromString("32 04", res)
+	assert.NoError(t, err)
+	access := res.ChannelAccess()
+	assert.Equal(t, &ChannelAccess{
+		Mode:           ChannelAccessAlways,
+		UserLevelAuth:  true,
+		PerMessageAuth: false,
+		PEFAlerting:    false,
+		PrivilegeLimit: PrivLevelAdmin,
+	}, access)
+	assert.Equal(t, uint8(0x32), access.bits())
+}
+
+func TestParseCipherSuites(t *testing.T) {
+	suites, err := parseCipherSuites([]byte{
+		0xc0, 0x03, 0x01, 0x41, 0x81,
+		0xc1, 0x80, 0x57, 0x01, 0x00, 0x02, 0x42, 0x43, 0x80,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*CipherSuite{
+		{ID: 3, Auth: CipherAuthHMACSHA1, Integrity: []uint8{CipherIntegrityHMACSHA1_96},
+			Confidentiality: []uint8{CipherConfidentialityAESCBC128}},
+		{ID: 0x80, OEM: OemIntel, Auth: CipherAuthHMACMD5,
+			Integrity:       []uint8{CipherIntegrityHMACMD5_128, CipherIntegrityMD5_128},
+			Confidentiality: []uint8{CipherConfidentialityNone}},
+	}, suites)
+
+	_, err = parseCipherSuites([]byte{0x01})
+	assert.Equal(t, ErrInvalidPacket, err)
+	_, err = parseCipherSuites([]byte{0xc1, 0x80, 0x57})
+	assert.Equal(t, ErrShortPacket, err)
+}
@@ -16,6 +16,8 @@ limitations under the License.
 
 package ipmi
 
+import "encoding/binary"
+
 type ChassisControl uint8
 type BootDevice uint8
 
@@ -80,6 +82,35 @@ const (
 	BootParamBootFlags     = 0x5
 	BootParamInitInfo      = 0x6
 	BootParamInitMbox      = 0x7
+
+	BiosVerbosityDefault = 0x0
+	BiosVerbosityQuiet   = 0x1
+	BiosVerbosityVerbose = 0x2
+
+	ConsoleRedirectionDefault  = 0x0
+	ConsoleRedirectionSuppress = 0x1
+	ConsoleRedirectionEnable   = 0x2
+
+	BiosMuxRecommended = 0x0
+	BiosMuxForceBMC    = 0x1
+	BiosMuxForceSystem = 0x2
+
+	IdentifyOff        = 0x0
+	IdentifyTemporary  = 0x1
+	IdentifyIndefinite = 0x2
+
+	RestartCauseUnknown        = 0x0
+	RestartCauseChassisControl = 0x1
+	RestartCauseResetButton    = 0x2
+	RestartCausePowerButton    = 0x3
+	RestartCauseWatchdog       = 0x4
+	RestartCauseOEM            = 0x5
+	RestartCauseAlwaysOn       = 0x6
+	RestartCausePrevious       = 0x7
+	RestartCausePEFReset       = 0x8
+	RestartCausePEFPowerCycle  = 0x9
+	RestartCauseSoftReset      = 0xa
+	RestartCauseRTCWakeup      = 0xb
 )
 
 // ChassisStatusRequest per section 28.2
@@ -104,6 +135,68 @@ type ChassisControlResponse struct {
 	CompletionCode
 }
 
+// ChassisIdentifyRequest per section 28.5
+type ChassisIdentifyRequest struct {
+	Interval uint8 // seconds, 0 turns identify off
+	Force    uint8 // 1 turns identify on indefinitely
+}
+
+// ChassisIdentifyResponse per section 28.5
+type ChassisIdentifyResponse struct {
+	CompletionCode
+}
+
+// SetPowerRestorePolicyRequest per section 28.8
+type SetPowerRestorePolicyRequest struct {
+	Policy uint8 // PowerRestorePolicyUnknown only gets the supported policies
+}
+
+// SetPowerRestorePolicyResponse per section 28.8
+type SetPowerRestorePolicyResponse struct {
+	CompletionCode
+	Supported uint8
+}
+
+// SystemRestartCauseRequest per section 28.11
+type SystemRestartCauseRequest struct{}
+
+// SystemRestartCauseResponse per section 28.11
+type SystemRestartCauseResponse struct {
+	CompletionCode
+	Cause   uint8
+	Channel uint8
+}
+
+// SetFrontPanelEnablesRequest per section 28.6, the buttons set are disabled
+type SetFrontPanelEnablesRequest struct {
+	Disable uint8
+}
+
+// SetFrontPanelEnablesResponse per section 28.6
+type SetFrontPanelEnablesResponse struct {
+	CompletionCode
+}
+
+// SetPowerCycleIntervalRequest per section 28.9
+type SetPowerCycleIntervalRequest struct {
+	Interval uint8 // seconds
+}
+
+// SetPowerCycleIntervalResponse per section 28.9
+type SetPowerCycleIntervalResponse struct {
+	CompletionCode
+}
+
+// POHCounterRequest per section 28.14
+type POHCounterRequest struct{}
+
+// POHCounterResponse per section 28.14
+type POHCounterResponse struct {
+	CompletionCode
+	MinutesPerCount uint8
+	Counter         uint32
+}
+
 // SetSystemBootOptionsRequest per section 28.12
 type SetSystemBootOptionsRequest struct {
 	Param uint8
@@ -138,9 +231,14 @@ func (r *SetSystemBootOptionsRequest) MarshalBinary() ([]byte, error) {
 	return buf, nil
 }
 
+// bootMailboxBlockSize is the size of a boot initiator mailbox block
+const bootMailboxBlockSize = 16
+
 var validSetBootOptionsDataLength = map[uint8]int{
 	BootParamInfoAck:   2,
 	BootParamBootFlags: 5,
+	BootParamInitInfo:  9,
+	BootParamInitMbox:  2,
 }
 
 // UnmarshalBinary implementation to handle variable length Data
@@ -197,6 +295,115 @@ func (r *ChassisStatusResponse) UnmarshalBinary(buf []byte) error {
 	return nil
 }
 
+// BootFlags is the boot flags parameter per section 28.13 - table 28
+type BootFlags struct {
+	Valid              bool
+	Persistent         bool // apply to all future boots instead of the next boot only
+	EFI                bool // EFI boot instead of PC compatible (legacy) boot
+	ClearCMOS          bool
+	LockKeyboard       bool
+	BootDevice         BootDevice
+	ScreenBlank        bool
+	LockResetButton    bool
+	LockPowerButton    bool
+	Verbosity          uint8 // one of the BiosVerbosity* values
+	ProgressEventTraps bool
+	PasswordBypass     bool
+	LockSleepButton    bool
+	ConsoleRedirection uint8 // one of the ConsoleRedirection* values
+	SharedModeOverride bool
+	MuxOverride        uint8 // one of the BiosMux* values
+	DeviceInstance     uint8 // 0 for any instance of BootDevice
+}
+
+func boolBit(b bool, bit uint8) uint8 {
+	if b {
+		return bit
+	}
+	return 0
+}
+
+// MarshalBinary encodes the 5 bytes of the boot flags parameter
+func (f *BootFlags) MarshalBinary() ([]byte, error) {
+	return []byte{
+		boolBit(f.Valid, 0x80) | boolBit(f.Persistent, 0x40) | boolBit(f.EFI, 0x20),
+		boolBit(f.ClearCMOS, 0x80) | boolBit(f.LockKeyboard, 0x40) | uint8(f.BootDevice)&0x3c |
+			boolBit(f.ScreenBlank, 0x02) | boolBit(f.LockResetButton, 0x01),
+		boolBit(f.LockPowerButton, 0x80) | (f.Verbosity&0x03)<<5 | boolBit(f.ProgressEventTraps, 0x10) |
+			boolBit(f.PasswordBypass, 0x08) | boolBit(f.LockSleepButton, 0x04) | f.ConsoleRedirection&0x03,
+		boolBit(f.SharedModeOverride, 0x08) | f.MuxOverride&0x07,
+		f.DeviceInstance & 0x1f,
+	}, nil
+}
+
+// UnmarshalBinary decodes the 5 bytes of the boot flags parameter
+func (f *BootFlags) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 5 {
+		return ErrShortPacket
+	}
+	*f = BootFlags{
+		Valid:              buf[0]&0x80 != 0,
+		Persistent:         buf[0]&0x40 != 0,
+		EFI:                buf[0]&0x20 != 0,
+		ClearCMOS:          buf[1]&0x80 != 0,
+		LockKeyboard:       buf[1]&0x40 != 0,
+		BootDevice:         BootDevice(buf[1] & 0x3c),
+		ScreenBlank:        buf[1]&0x02 != 0,
+		LockResetButton:    buf[1]&0x01 != 0,
+		LockPowerButton:    buf[2]&0x80 != 0,
+		Verbosity:          (buf[2] >> 5) & 0x03,
+		ProgressEventTraps: buf[2]&0x10 != 0,
+		PasswordBypass:     buf[2]&0x08 != 0,
+		LockSleepButton:    buf[2]&0x04 != 0,
+		ConsoleRedirection: buf[2] & 0x03,
+		SharedModeOverride: buf[3]&0x08 != 0,
+		MuxOverride:        buf[3] & 0x07,
+		DeviceInstance:     buf[4] & 0x1f,
+	}
+	return nil
+}
+
+// BootInitiatorInfo is the boot initiator info parameter per section 28.13 - table 28
+type BootInitiatorInfo struct {
+	Channel   uint8
+	SessionID uint32
+	Timestamp uint32
+}
+
+// MarshalBinary encodes the 9 bytes of the boot initiator info parameter
+func (i *BootInitiatorInfo) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 9)
+	buf[0] = i.Channel & 0x0f
+	binary.LittleEndian.PutUint32(buf[1:], i.SessionID)
+	binary.LittleEndian.PutUint32(buf[5:], i.Timestamp)
+	return buf, nil
+}
+
+// UnmarshalBinary decodes the 9 bytes of the boot initiator info parameter
+func (i *BootInitiatorInfo) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 9 {
+		return ErrShortPacket
+	}
+	i.Channel = buf[0] & 0x0f
+	i.SessionID = binary.LittleEndian.Uint32(buf[1:])
+	i.Timestamp = binary.LittleEndian.Uint32(buf[5:])
+	return nil
+}
+
+// IsValid tells whether the parameter data is valid, the BMC marks it invalid or locked otherwise
+func (r *SystemBootOptionsResponse) IsValid() bool {
+	return r.Param&0x80 == 0
+}
+
+// BootFlags decodes the data of a boot flags parameter response
+func (r *SystemBootOptionsResponse) BootFlags() (*BootFlags, error) {
+	flags := &BootFlags{}
+	if err := flags.UnmarshalBinary(r.Data); err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
 func (r *SystemBootOptionsResponse) BootDeviceSelector() BootDevice {
 	return BootDevice(((r.Data[1] >> 2) & 0x0f) << 2)
 }
@@ -216,6 +423,104 @@ func (s *ChassisStatusResponse) PowerRestorePolicy() uint8 {
 	return (s.PowerState & 0x60) >> 5
 }
 
+func (s *ChassisStatusResponse) IsPowerOverload() bool {
+	return (s.PowerState & PowerOverload) == PowerOverload
+}
+
+func (s *ChassisStatusResponse) IsPowerInterlock() bool {
+	return (s.PowerState & PowerInterlock) == PowerInterlock
+}
+
+func (s *ChassisStatusResponse) IsMainPowerFault() bool {
+	return (s.PowerState & MainPowerFault) == MainPowerFault
+}
+
+func (s *ChassisStatusResponse) IsPowerControlFault() bool {
+	return (s.PowerState & PowerControlFault) == PowerControlFault
+}
+
+var powerEventStrings = []struct {
+	event uint8
+	name  string
+}{
+	{PowerEventAcFailed, "ac-failed"},
+	{PowerEventOverload, "overload"},
+	{PowerEventInterlock, "interlock"},
+	{PowerEventFault, "fault"},
+	{PowerEventCommand, "command"},
+}
+
+// PowerEvents returns the causes of the last power event as named by ipmitool
+func (s *ChassisStatusResponse) PowerEvents() []string {
+	var events []string
+	for _, e := range powerEventStrings {
+		if s.LastPowerEvent&e.event != 0 {
+			events = append(events, e.name)
+		}
+	}
+	return events
+}
+
+func (s *ChassisStatusResponse) IsChassisIntrusion() bool {
+	return (s.State & ChassisIntrusion) == ChassisIntrusion
+}
+
+func (s *ChassisStatusResponse) IsFrontPanelLockout() bool {
+	return (s.State & FrontPanelLockout) == FrontPanelLockout
+}
+
+func (s *ChassisStatusResponse) IsDriveFault() bool {
+	return (s.State & DriveFault) == DriveFault
+}
+
+func (s *ChassisStatusResponse) IsCoolingFanFault() bool {
+	return (s.State & CoolingFanFault) == CoolingFanFault
+}
+
+// IdentifyState returns IdentifyOff, IdentifyTemporary or IdentifyIndefinite,
+// supported is false when the BMC does not report it
+func (s *ChassisStatusResponse) IdentifyState() (state uint8, supported bool) {
+	return (s.State >> 4) & 0x03, s.State&0x40 != 0
+}
+
+// FrontPanelButton returns the state of a button, one of PowerButtonDisabled, ResetButtonDisabled,
+// DiagButtonDisabled or SleepButtonDisabled
+func (s *ChassisStatusResponse) FrontPanelButton(button uint8) (disableAllowed bool, disabled bool) {
+	return s.FrontControlPanel&(button<<4) != 0, s.FrontControlPanel&button != 0
+}
+
+// Supports tells whether the BMC supports the given power restore policy
+func (r *SetPowerRestorePolicyResponse) Supports(policy uint8) bool {
+	return policy < PowerRestorePolicyUnknown && r.Supported&(1<<policy) != 0
+}
+
+var restartCauseStrings = map[uint8]string{
+	RestartCauseUnknown:        "unknown",
+	RestartCauseChassisControl: "chassis power control command",
+	RestartCauseResetButton:    "reset via pushbutton",
+	RestartCausePowerButton:    "power-up via pushbutton",
+	RestartCauseWatchdog:       "watchdog expired",
+	RestartCauseOEM:            "OEM",
+	RestartCauseAlwaysOn:       "power-up due to always-restore power policy",
+	RestartCausePrevious:       "power-up due to restore-previous power policy",
+	RestartCausePEFReset:       "reset via PEF",
+	RestartCausePEFPowerCycle:  "power-cycle via PEF",
+	RestartCauseSoftReset:      "soft reset",
+	RestartCauseRTCWakeup:      "power-up via RTC wakeup",
+}
+
+func (r *SystemRestartCauseResponse) String() string {
+	if s, ok := restartCauseStrings[r.Cause&0x0f]; ok {
+		return s
+	}
+	return "unknown"
+}
+
+// Hours returns the power-on hours counted
+func (r *POHCounterResponse) Hours() uint32 {
+	return uint32(uint64(r.Counter) * uint64(r.MinutesPerCount) / 60)
+}
+
 var bootDeviceStrings = map[BootDevice]string{
 	BootDeviceNone:          "none",
 	BootDevicePxe:           "pxe",
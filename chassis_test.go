@@ -70,3 +70,110 @@ func TestBootFlagsParse(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, BootDeviceFloppy, res.BootDeviceSelector())
 }
+
+func TestBootFlagsMarshal(t *testing.T) {
+	tests := []struct {
+		flags BootFlags
+		raw   []byte
+	}{
+		{BootFlags{Valid: true, BootDevice: BootDevicePxe}, []byte{0x80, 0x04, 0x00, 0x00, 0x00}},
+		{BootFlags{Valid: true, EFI: true, BootDevice: BootDevicePxe}, []byte{0xa0, 0x04, 0x00, 0x00, 0x00}},
+		{BootFlags{Valid: true, Persistent: true, BootDevice: BootDeviceDisk}, []byte{0xc0, 0x08, 0x00, 0x00, 0x00}},
+		{BootFlags{
+			Valid:              true,
+			ClearCMOS:          true,
+			LockKeyboard:       true,
+			BootDevice:         BootDeviceRemoteCdrom,
+			ScreenBlank:        true,
+			LockResetButton:    true,
+			LockPowerButton:    true,
+			Verbosity:          BiosVerbosityVerbose,
+			ProgressEventTraps: true,
+			PasswordBypass:     true,
+			LockSleepButton:    true,
+			ConsoleRedirection: ConsoleRedirectionEnable,
+			SharedModeOverride: true,
+			MuxOverride:        BiosMuxForceSystem,
+			DeviceInstance:     0x12,
+		}, []byte{0x80, 0xe3, 0xde, 0x0a, 0x12}},
+	}
+
+	for _, test := range tests {
+		raw, err := test.flags.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, test.raw, raw)
+
+		flags := BootFlags{}
+		assert.NoError(t, flags.UnmarshalBinary(raw))
+		assert.Equal(t, test.flags, flags)
+	}
+
+	assert.Equal(t, ErrShortPacket, (&BootFlags{}).UnmarshalBinary([]byte{0x80, 0x04}))
+}
+
+func TestBootInitiatorInfoParse(t *testing.T) {
+	res := &SystemBootOptionsResponse{}
+	err := responseFromString("01 06 01 78 56 34 12 00 00 00 00", res)
+	assert.NoError(t, err)
+	assert.True(t, res.IsValid())
+
+	info := &BootInitiatorInfo{}
+	assert.NoError(t, info.UnmarshalBinary(res.Data))
+	assert.Equal(t, BootInitiatorInfo{Channel: 1, SessionID: 0x12345678}, *info)
+
+	err = responseFromString("01 85 80 04 00 00 00", res)
+	assert.NoError(t, err)
+	assert.False(t, res.IsValid())
+}
+
+func TestChassisStatusDecode(t *testing.T) {
+	status := &ChassisStatusResponse{}
+	err := responseFromString("0b 11 69 11", status)
+	assert.NoError(t, err)
+
+	assert.True(t, status.IsSystemPowerOn())
+	assert.True(t, status.IsPowerOverload())
+	assert.False(t, status.IsPowerInterlock())
+	assert.True(t, status.IsMainPowerFault())
+	assert.False(t, status.IsPowerControlFault())
+	assert.Equal(t, []string{"ac-failed", "command"}, status.PowerEvents())
+	assert.True(t, status.IsChassisIntrusion())
+	assert.False(t, status.IsFrontPanelLockout())
+	assert.False(t, status.IsDriveFault())
+	assert.True(t, status.IsCoolingFanFault())
+	state, supported := status.IdentifyState()
+	assert.Equal(t, uint8(IdentifyIndefinite), state)
+	assert.True(t, supported)
+
+	allowed, disabled := status.FrontPanelButton(PowerButtonDisabled)
+	assert.True(t, allowed)
+	assert.True(t, disabled)
+	allowed, disabled = status.FrontPanelButton(ResetButtonDisabled)
+	assert.False(t, allowed)
+	assert.False(t, disabled)
+}
+
+func TestChassisIdentifyRequest(t *testing.T) {
+	req := &Request{
+		NetworkFunctionChassis,
+		CommandChassisIdentify,
+		&ChassisIdentifyRequest{Interval: 30},
+	}
+	raw := requestToStrings(req)
+	assert.Equal(t, []string{"0x00", "0x04", "0x1e", "0x00"}, raw)
+}
+
+func TestSystemRestartCauseParse(t *testing.T) {
+	res := &SystemRestartCauseResponse{}
+	err := responseFromString("07 01", res)
+	assert.NoError(t, err)
+	assert.Equal(t, "power-up due to restore-previous power policy", res.String())
+}
+
+func TestPOHCounterParse(t *testing.T) {
+	res := &POHCounterResponse{}
+	err := responseFromString("1e 10 27 00 00", res)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(10000), res.Counter)
+	assert.Equal(t, uint32(5000), res.Hours())
+}
@@ -16,10 +16,23 @@ limitations under the License.
 
 package ipmi
 
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// ErrBootParamInvalid is returned when the BMC marks the boot options parameter invalid or locked
+var ErrBootParamInvalid = errors.New("boot options parameter marked invalid")
+
 // Client provides common high level functionality around the underlying transport
 type Client struct {
 	*Connection
 	transport
+	deviceID *DeviceIDResponse
+	mu       sync.Mutex // serializes requests, a Client may be shared by goroutines
 }
 
 // NewClient creates a new Client with the given Connection properties
@@ -48,18 +61,182 @@ func (c *Client) Close() error {
 // Send a Request and unmarshal to given Response type
 func (c *Client) Send(req *Request, res Response) error {
 	// TODO: handle retry, timeouts, etc.
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.send(req, res)
 }
 
 // DeviceID get the Device ID of the BMC
 func (c *Client) DeviceID() (*DeviceIDResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.getDeviceID()
+}
+
+// getDeviceID is DeviceID for callers holding c.mu
+func (c *Client) getDeviceID() (*DeviceIDResponse, error) {
 	req := &Request{
 		NetworkFunctionApp,
 		CommandGetDeviceID,
 		&DeviceIDRequest{},
 	}
 	res := &DeviceIDResponse{}
-	return res, c.Send(req, res)
+	if err := c.send(req, res); err != nil {
+		return res, err
+	}
+	c.deviceID = res
+	return res, nil
+}
+
+// cachedDeviceID returns the Device ID of the BMC, only asking for it the first time
+func (c *Client) cachedDeviceID() (*DeviceIDResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.deviceID != nil {
+		return c.deviceID, nil
+	}
+	return c.getDeviceID()
+}
+
+// Capabilities returns the optional features the BMC implements, so that callers can
+// check for support before sending commands. The Device ID is only asked for once
+func (c *Client) Capabilities() (*DeviceCapabilities, error) {
+	id, err := c.cachedDeviceID()
+	if err != nil {
+		return nil, err
+	}
+	return id.Capabilities(), nil
+}
+
+// ColdReset resets the BMC and waits for it to come back, reopening the session.
+// The BMC is polled every interval, 1 second if zero, first until it stops answering
+// then until it answers Get Device ID as available again, or ctx is done.
+// Requests sent by other goroutines meanwhile wait for ColdReset to return
+func (c *Client) ColdReset(ctx context.Context, interval time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r := &Request{
+		NetworkFunctionApp,
+		CommandColdReset,
+		&ColdResetRequest{},
+	}
+	if err := c.send(r, &ColdResetResponse{}); err != nil {
+		// the BMC may reset before responding
+		if ne, ok := err.(net.Error); !ok || !ne.Timeout() {
+			return err
+		}
+	}
+
+	if interval == 0 {
+		interval = time.Second
+	}
+	sleep := func() error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+			return nil
+		}
+	}
+
+	// the BMC may keep answering for a while before it resets,
+	// the session does not survive the reset
+	for {
+		if _, err := c.getDeviceID(); err != nil {
+			break
+		}
+		if err := sleep(); err != nil {
+			return err
+		}
+	}
+
+	_ = c.close()
+	for {
+		if err := sleep(); err != nil {
+			return err
+		}
+
+		if err := c.open(); err != nil {
+			_ = c.close()
+			continue
+		}
+		if id, err := c.getDeviceID(); err == nil && !id.IsUpdateInProgress() {
+			return nil
+		}
+		_ = c.close()
+	}
+}
+
+// WarmReset resets the BMC, keeping its sensors and configuration
+func (c *Client) WarmReset() error {
+	r := &Request{
+		NetworkFunctionApp,
+		CommandWarmReset,
+		&WarmResetRequest{},
+	}
+	return c.Send(r, &WarmResetResponse{})
+}
+
+// SelfTestResults gets the results of the BMC self test
+func (c *Client) SelfTestResults() (*SelfTestResultsResponse, error) {
+	r := &Request{
+		NetworkFunctionApp,
+		CommandGetSelfTestResults,
+		&SelfTestResultsRequest{},
+	}
+	res := &SelfTestResultsResponse{}
+	return res, c.Send(r, res)
+}
+
+// SystemGUID gets the GUID of the managed system, use UUID() to match it with the host
+func (c *Client) SystemGUID() (*GUIDResponse, error) {
+	r := &Request{
+		NetworkFunctionApp,
+		CommandGetSystemGUID,
+		&GUIDRequest{},
+	}
+	res := &GUIDResponse{}
+	return res, c.Send(r, res)
+}
+
+// DeviceGUID gets the GUID of the BMC itself
+func (c *Client) DeviceGUID() (*GUIDResponse, error) {
+	r := &Request{
+		NetworkFunctionApp,
+		CommandGetDeviceGUID,
+		&GUIDRequest{},
+	}
+	res := &GUIDResponse{}
+	return res, c.Send(r, res)
+}
+
+// ACPIPowerState gets the ACPI power state the system software last set
+func (c *Client) ACPIPowerState() (*ACPIPowerStateResponse, error) {
+	r := &Request{
+		NetworkFunctionApp,
+		CommandGetACPIPowerState,
+		&ACPIPowerStateRequest{},
+	}
+	res := &ACPIPowerStateResponse{}
+	return res, c.Send(r, res)
+}
+
+// SetACPIPowerState records the ACPI power state, ACPINoChange leaves a state as is
+func (c *Client) SetACPIPowerState(system, device uint8) error {
+	req := &SetACPIPowerStateRequest{SystemState: system, DeviceState: device}
+	if system != ACPINoChange {
+		req.SystemState |= 0x80
+	}
+	if device != ACPINoChange {
+		req.DeviceState |= 0x80
+	}
+	r := &Request{
+		NetworkFunctionApp,
+		CommandSetACPIPowerState,
+		req,
+	}
+	return c.Send(r, &SetACPIPowerStateResponse{})
 }
 
 func (c *Client) setBootParam(param uint8, data ...uint8) error {
@@ -74,39 +251,112 @@ func (c *Client) setBootParam(param uint8, data ...uint8) error {
 	return c.Send(r, &SetSystemBootOptionsResponse{})
 }
 
-// SetBootDevice is a wrapper around SetSystemBootOptionsRequest to configure the BootDevice
-// per section 28.12 - table 28
-func (c *Client) SetBootDevice(dev BootDevice) error {
-	useProgress := true
-	// set set-in-progress flag
-	err := c.setBootParam(BootParamSetInProgress, 0x01)
+// GetBootParam reads a system boot options parameter per section 28.13,
+// set selects the set (the block number for the initiator mailbox) if the parameter has any
+func (c *Client) GetBootParam(param, set uint8) (*SystemBootOptionsResponse, error) {
+	r := &Request{
+		NetworkFunctionChassis,
+		CommandGetSystemBootOptions,
+		&SystemBootOptionsRequest{
+			Param: param,
+			Set:   set,
+		},
+	}
+	res := &SystemBootOptionsResponse{}
+	return res, c.Send(r, res)
+}
+
+// setBootParams writes the boot options parameters within a single set-in-progress
+// transaction, BootParamSetInProgress being parameter 0 like for LAN, SOL and PEF
+func (c *Client) setBootParams(params []configParamData) error {
+	return setConfigParams(c.setBootParam, params)
+}
+
+// SetBootParam writes a system boot options parameter, wrapped in the
+// set-in-progress protocol when the BMC implements it, per section 28.12
+func (c *Client) SetBootParam(param uint8, data ...uint8) error {
+	return c.setBootParams([]configParamData{{param, data}})
+}
+
+// GetBootOptions reads back the boot flags parameter,
+// ErrBootParamInvalid is returned if the BMC marks it invalid
+func (c *Client) GetBootOptions() (*BootFlags, error) {
+	res, err := c.GetBootParam(BootParamBootFlags, 0)
 	if err != nil {
-		useProgress = false
+		return nil, err
 	}
+	if !res.IsValid() {
+		return nil, ErrBootParamInvalid
+	}
+	return res.BootFlags()
+}
 
-	err = c.setBootParam(BootParamInfoAck, 0x01, 0x01)
+// SetBootOptions writes the boot flags parameter. The BIOS boot info acknowledge
+// is cleared first so that the BIOS picks up the new flags on the next boot
+func (c *Client) SetBootOptions(flags *BootFlags) error {
+	data, err := flags.MarshalBinary()
 	if err != nil {
-		if useProgress {
-			// set-in-progress = set-complete
-			_ = c.setBootParam(BootParamSetInProgress, 0x00)
-		}
 		return err
 	}
+	return c.setBootParams([]configParamData{
+		{BootParamInfoAck, []uint8{0x01, 0x01}},
+		{BootParamBootFlags, data},
+	})
+}
 
-	err = c.setBootParam(BootParamBootFlags, 0x80, uint8(dev), 0x00, 0x00, 0x00)
-	if err == nil {
-		if useProgress {
-			// set-in-progress = commit-write
-			_ = c.setBootParam(BootParamSetInProgress, 0x02)
-		}
+// SetBootDevice is a wrapper around SetBootOptions to configure the BootDevice
+// for the next legacy boot per section 28.12 - table 28
+func (c *Client) SetBootDevice(dev BootDevice) error {
+	return c.SetBootOptions(&BootFlags{Valid: true, BootDevice: dev})
+}
+
+// SetBootDeviceEFI configures the BootDevice for the next EFI boot,
+// SetBootDeviceEFI(BootDevicePxe) gives a one time PXE boot on UEFI systems
+func (c *Client) SetBootDeviceEFI(dev BootDevice) error {
+	return c.SetBootOptions(&BootFlags{Valid: true, EFI: true, BootDevice: dev})
+}
+
+// GetBootInitiatorInfo reads the boot initiator info parameter
+func (c *Client) GetBootInitiatorInfo() (*BootInitiatorInfo, error) {
+	res, err := c.GetBootParam(BootParamInitInfo, 0)
+	if err != nil {
+		return nil, err
+	}
+	info := &BootInitiatorInfo{}
+	if err := info.UnmarshalBinary(res.Data); err != nil {
+		return nil, err
 	}
+	return info, nil
+}
 
-	if useProgress {
-		// set-in-progress = set-complete
-		_ = c.setBootParam(BootParamSetInProgress, 0x00)
+// SetBootInitiatorInfo writes the boot initiator info parameter
+func (c *Client) SetBootInitiatorInfo(info *BootInitiatorInfo) error {
+	data, err := info.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return c.SetBootParam(BootParamInitInfo, data...)
+}
+
+// GetBootInitiatorMailbox reads one 16 byte block of the boot initiator mailbox
+func (c *Client) GetBootInitiatorMailbox(block uint8) ([]byte, error) {
+	res, err := c.GetBootParam(BootParamInitMbox, block)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Data) < 1 {
+		return nil, ErrShortPacket
 	}
+	return res.Data[1:], nil
+}
 
-	return err
+// SetBootInitiatorMailbox writes one block of the boot initiator mailbox,
+// block 0 must start with the IANA enterprise number of the data owner
+func (c *Client) SetBootInitiatorMailbox(block uint8, data []byte) error {
+	if len(data) > bootMailboxBlockSize {
+		return ErrRequestData
+	}
+	return c.SetBootParam(BootParamInitMbox, append([]byte{block}, data...)...)
 }
 
 // Control sends a chassis power control command
@@ -118,3 +368,96 @@ func (c *Client) Control(ctl ChassisControl) error {
 	}
 	return c.Send(r, &ChassisControlResponse{})
 }
+
+// ChassisStatus gets the power, fault and front panel state of the chassis
+func (c *Client) ChassisStatus() (*ChassisStatusResponse, error) {
+	r := &Request{
+		NetworkFunctionChassis,
+		CommandChassisStatus,
+		&ChassisStatusRequest{},
+	}
+	res := &ChassisStatusResponse{}
+	if err := c.Send(r, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ChassisIdentify turns the chassis identify light on for interval seconds, off when interval is 0,
+// or on until turned off when force is true
+func (c *Client) ChassisIdentify(interval uint8, force bool) error {
+	req := &ChassisIdentifyRequest{Interval: interval}
+	if force {
+		req.Force = 0x01
+	}
+	r := &Request{
+		NetworkFunctionChassis,
+		CommandChassisIdentify,
+		req,
+	}
+	return c.Send(r, &ChassisIdentifyResponse{})
+}
+
+// SetPowerRestorePolicy sets the policy applied when AC power comes back,
+// PowerRestorePolicyUnknown leaves it unchanged. The response tells which policies are supported.
+func (c *Client) SetPowerRestorePolicy(policy uint8) (*SetPowerRestorePolicyResponse, error) {
+	r := &Request{
+		NetworkFunctionChassis,
+		CommandSetPowerRestorePolicy,
+		&SetPowerRestorePolicyRequest{policy},
+	}
+	res := &SetPowerRestorePolicyResponse{}
+	if err := c.Send(r, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SystemRestartCause gets the cause of the last system restart
+func (c *Client) SystemRestartCause() (*SystemRestartCauseResponse, error) {
+	r := &Request{
+		NetworkFunctionChassis,
+		CommandGetSystemRestartCause,
+		&SystemRestartCauseRequest{},
+	}
+	res := &SystemRestartCauseResponse{}
+	if err := c.Send(r, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SetFrontPanelEnables disables the front panel buttons set in disable, any of PowerButtonDisabled,
+// ResetButtonDisabled, DiagButtonDisabled and SleepButtonDisabled, and enables the others
+func (c *Client) SetFrontPanelEnables(disable uint8) error {
+	r := &Request{
+		NetworkFunctionChassis,
+		CommandSetFrontPanelEnables,
+		&SetFrontPanelEnablesRequest{disable & 0x0f},
+	}
+	return c.Send(r, &SetFrontPanelEnablesResponse{})
+}
+
+// SetPowerCycleInterval sets the seconds the power stays off during a power cycle
+func (c *Client) SetPowerCycleInterval(interval uint8) error {
+	r := &Request{
+		NetworkFunctionChassis,
+		CommandSetPowerCycleInterval,
+		&SetPowerCycleIntervalRequest{interval},
+	}
+	return c.Send(r, &SetPowerCycleIntervalResponse{})
+}
+
+// POHCounter gets the power-on hours counter
+func (c *Client) POHCounter() (*POHCounterResponse, error) {
+	r := &Request{
+		NetworkFunctionChassis,
+		CommandGetPOHCounter,
+		&POHCounterRequest{},
+	}
+	res := &POHCounterResponse{}
+	if err := c.Send(r, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
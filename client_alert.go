@@ -0,0 +1,96 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import "time"
+
+// alertPollInterval is the interval of the Alert Immediate status polls of SendTestAlert
+const alertPollInterval = 500 * time.Millisecond
+
+func (c *Client) alertImmediate(r *AlertImmediateRequest) (uint8, error) {
+	req := &Request{
+		NetworkFunctionSensorEvent,
+		CommandAlertImmediate,
+		r,
+	}
+	res := &AlertImmediateResponse{}
+	err := c.Send(req, res)
+	return res.Status, err
+}
+
+// AlertImmediate sends an alert to the destination set of the LAN channel per section 30.7,
+// event is the platform event of the alert, nil leaving it to the BMC
+func (c *Client) AlertImmediate(channel, destination uint8, event *SELEvent) error {
+	_, err := c.alertImmediate(&AlertImmediateRequest{
+		Channel:     channel,
+		Operation:   AlertImmediateInitiate,
+		Destination: destination,
+		Event:       event,
+	})
+	return err
+}
+
+// GetAlertImmediateStatus gets the AlertStatus* of the last Alert Immediate to the destination
+func (c *Client) GetAlertImmediateStatus(channel, destination uint8) (uint8, error) {
+	return c.alertImmediate(&AlertImmediateRequest{
+		Channel:     channel,
+		Operation:   AlertImmediateGetStatus,
+		Destination: destination,
+	})
+}
+
+// ClearAlertImmediateStatus clears the status of the last Alert Immediate to the destination
+func (c *Client) ClearAlertImmediateStatus(channel, destination uint8) error {
+	_, err := c.alertImmediate(&AlertImmediateRequest{
+		Channel:     channel,
+		Operation:   AlertImmediateClearStatus,
+		Destination: destination,
+	})
+	return err
+}
+
+// SendTestAlert sends an alert to the destination set of the LAN channel and waits up to
+// timeout for the alert to end, returning its AlertStatus*. An alert the destination has to
+// acknowledge is still AlertStatusInProgress if the acknowledgement did not come in time
+func (c *Client) SendTestAlert(channel, destination uint8, timeout time.Duration) (uint8, error) {
+	if err := c.ClearAlertImmediateStatus(channel, destination); err != nil {
+		return AlertStatusNone, err
+	}
+	if err := c.AlertImmediate(channel, destination, nil); err != nil {
+		return AlertStatusNone, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		status, err := c.GetAlertImmediateStatus(channel, destination)
+		if err != nil || status != AlertStatusInProgress || time.Now().After(deadline) {
+			return status, err
+		}
+		time.Sleep(alertPollInterval)
+	}
+}
+
+// PETAcknowledge acknowledges a trap received from the BMC per section 30.8,
+// for alert destinations that require an acknowledgement
+func (c *Client) PETAcknowledge(e *PETEvent) error {
+	r := &Request{
+		NetworkFunctionSensorEvent,
+		CommandPETAcknowledge,
+		NewPETAcknowledgeRequest(e),
+	}
+	return c.Send(r, &PETAcknowledgeResponse{})
+}
@@ -0,0 +1,146 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlert(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	l := NewPETListener(net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 16201})
+	err = l.Run()
+	assert.NoError(t, err)
+	s.SetAlertPort(l.LocalAddr().Port)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+	err = client.Open()
+	assert.NoError(t, err)
+
+	receive := func() *PETEvent {
+		select {
+		case e := <-l.Events():
+			return e
+		case <-time.After(5 * time.Second):
+			t.Fatal("no trap received")
+			return nil
+		}
+	}
+
+	var count LANAlertDestinationCount
+	err = client.GetLANConfig(1, &count)
+	assert.NoError(t, err)
+	assert.Equal(t, LANAlertDestinationCount(3), count)
+	err = client.SetLANConfig(1, count)
+	assert.Equal(t, ErrLANParamReadOnly, err)
+
+	// destination 1 acknowledges the traps, destination 2 does not
+	ack := &LANAlertDestinationType{Set: 1, Type: LANAlertPET, AckRequired: true, Timeout: 2 * time.Second, Retries: 1}
+	addr := &LANAlertDestinationAddr{Set: 1, IP: net.IPv4(127, 0, 0, 1), MAC: net.HardwareAddr{0x02, 0, 0, 0, 0, 0x01}}
+	err = client.SetLANConfig(1,
+		ack, addr,
+		&LANAlertDestinationAddr{Set: 2, IP: net.IPv4(127, 0, 0, 1)},
+	)
+	assert.NoError(t, err)
+
+	destType := &LANAlertDestinationType{Set: 1}
+	err = client.GetLANConfig(1, destType)
+	assert.NoError(t, err)
+	assert.Equal(t, ack, destType)
+	destAddr := &LANAlertDestinationAddr{Set: 1}
+	err = client.GetLANConfig(1, destAddr)
+	assert.NoError(t, err)
+	assert.Equal(t, addr, destAddr)
+
+	// an IPv6 destination
+	addr6 := &LANAlertDestinationAddr{Set: 3, IP: net.ParseIP("2001:db8::9")}
+	err = client.SetLANConfig(1, addr6)
+	assert.NoError(t, err)
+	destAddr = &LANAlertDestinationAddr{Set: 3}
+	err = client.GetLANConfig(1, destAddr)
+	assert.NoError(t, err)
+	assert.Equal(t, addr6, destAddr)
+
+	err = client.SetLANConfig(1, &LANAlertDestinationAddr{Set: 4, IP: net.IPv4(127, 0, 0, 1)})
+	assert.Equal(t, ErrParamRange, err)
+
+	// an alert waits for its acknowledgement
+	event := &SELEvent{
+		RecordType:   SELRecordSystemEvent,
+		GeneratorID:  bmcSlaveAddr,
+		EvMRev:       SELEvMRev,
+		SensorType:   SDR_SENSOR_TYPECODES_FAN,
+		SensorNumber: 0x40,
+		EventType:    SENSOR_READTYPE_THREADHOLD,
+		EventData:    [3]uint8{0x52, 0xff, 0xff},
+	}
+	err = client.AlertImmediate(1, 1, event)
+	assert.NoError(t, err)
+
+	trap := receive()
+	assert.Equal(t, "public", trap.Community)
+	assert.Equal(t, "192.168.1.120", trap.AgentAddr.String())
+	assert.Equal(t, SDRSensorType(SDR_SENSOR_TYPECODES_FAN), trap.SensorType)
+	assert.Equal(t, uint8(0x40), trap.SensorNumber)
+	assert.Equal(t, uint8(2), trap.Offset())
+
+	status, err := client.GetAlertImmediateStatus(1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(AlertStatusInProgress), status)
+	err = client.AlertImmediate(1, 1, nil)
+	assert.Equal(t, ErrAlertInProgress, err)
+
+	err = client.PETAcknowledge(trap)
+	assert.NoError(t, err)
+	status, err = client.GetAlertImmediateStatus(1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(AlertStatusNormalEnd), status)
+
+	err = client.ClearAlertImmediateStatus(1, 1)
+	assert.NoError(t, err)
+	status, err = client.GetAlertImmediateStatus(1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(AlertStatusNone), status)
+
+	// test alerts
+	status, err = client.SendTestAlert(1, 2, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(AlertStatusNormalEnd), status)
+	test := receive()
+	assert.Equal(t, trap.Sequence+1, test.Sequence)
+
+	// not acknowledged in time
+	status, err = client.SendTestAlert(1, 1, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(AlertStatusInProgress), status)
+	receive()
+
+	_, err = client.GetAlertImmediateStatus(1, simulatorAlertDestinations+1)
+	assert.Equal(t, ErrParamRange, err)
+
+	client.Close()
+	l.Stop()
+	s.Stop()
+}
@@ -0,0 +1,190 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+// Channel is a channel of the BMC with its access configuration
+type Channel struct {
+	Number            uint8
+	Info              *ChannelInfoResponse
+	Access            *ChannelAccess // present volatile setting, nil for session-less channels
+	NonVolatileAccess *ChannelAccess
+	// Auth is asked for at the callback privilege level, nil for session-less channels
+	Auth *AuthCapabilitiesResponse
+}
+
+// Enabled tells whether the channel accepts sessions
+func (c *Channel) Enabled() bool {
+	return c.Access != nil && c.Access.Mode != ChannelAccessDisabled
+}
+
+// AllowsAnonymous tells whether the null user can open a session on the channel
+func (c *Channel) AllowsAnonymous() bool {
+	return c.Enabled() && c.Auth != nil && c.Auth.AllowsAnonymous()
+}
+
+// AllowsCallback tells whether callback level sessions can be opened on the channel
+func (c *Channel) AllowsCallback() bool {
+	return c.Enabled() && c.Access.PrivilegeLimit >= PrivLevelCallback &&
+		c.Access.PrivilegeLimit != PrivLevelNoAccess && c.Auth != nil && c.Auth.AuthTypeSupport&0x3f != 0
+}
+
+// GetChannelAuthCapabilities gets the authentication capabilities of a channel at the given
+// privilege level, with the IPMI v2.0 extended data if the BMC supports it
+func (c *Client) GetChannelAuthCapabilities(channel, priv uint8) (*AuthCapabilitiesResponse, error) {
+	r := &Request{
+		NetworkFunctionApp,
+		CommandGetAuthCapabilities,
+		&AuthCapabilitiesRequest{
+			ChannelNumber: 0x80 | channel&0x0f,
+			PrivLevel:     priv,
+		},
+	}
+	res := &AuthCapabilitiesResponse{}
+	err := c.Send(r, res)
+	if err == ErrInvalidPacket {
+		// IPMI v1.5 BMCs may reject the request for extended data
+		r.Data = &AuthCapabilitiesRequest{
+			ChannelNumber: channel & 0x0f,
+			PrivLevel:     priv,
+		}
+		err = c.Send(r, res)
+	}
+	return res, err
+}
+
+// GetChannelInfo gets the medium, protocol and session support of a channel
+func (c *Client) GetChannelInfo(channel uint8) (*ChannelInfoResponse, error) {
+	r := &Request{
+		NetworkFunctionApp,
+		CommandGetChannelInfo,
+		&ChannelInfoRequest{channel & 0x0f},
+	}
+	res := &ChannelInfoResponse{}
+	return res, c.Send(r, res)
+}
+
+// GetChannelAccess gets the access configuration of a channel,
+// selector is either ChannelAccessVolatile or ChannelAccessNonVolatile
+func (c *Client) GetChannelAccess(channel, selector uint8) (*ChannelAccess, error) {
+	r := &Request{
+		NetworkFunctionApp,
+		CommandGetChannelAccess,
+		&ChannelAccessRequest{
+			Channel: channel & 0x0f,
+			Type:    selector & 0xc0,
+		},
+	}
+	res := &ChannelAccessResponse{}
+	if err := c.Send(r, res); err != nil {
+		return nil, err
+	}
+	return res.ChannelAccess(), nil
+}
+
+// SetChannelAccess sets the access configuration of a channel, selector is
+// ChannelAccessVolatile, ChannelAccessNonVolatile or both to set both
+func (c *Client) SetChannelAccess(channel, selector uint8, access *ChannelAccess) error {
+	for _, sel := range []uint8{ChannelAccessNonVolatile, ChannelAccessVolatile} {
+		if selector&sel == 0 {
+			continue
+		}
+		r := &Request{
+			NetworkFunctionApp,
+			CommandSetChannelAccess,
+			&SetChannelAccessRequest{
+				Channel:   channel & 0x0f,
+				Access:    sel | access.bits(),
+				Privilege: sel | access.PrivilegeLimit&0x0f,
+			},
+		}
+		if err := c.Send(r, &SetChannelAccessResponse{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetChannelCipherSuites gets the cipher suites a channel supports for IPMI payloads
+func (c *Client) GetChannelCipherSuites(channel uint8) ([]*CipherSuite, error) {
+	var data []byte
+	for index := uint8(0); index <= 0x3f; index++ {
+		r := &Request{
+			NetworkFunctionApp,
+			CommandGetChannelCipherSuites,
+			&ChannelCipherSuitesRequest{
+				Channel:     channel & 0x0f,
+				PayloadType: PayloadIPMI,
+				Index:       0x80 | index,
+			},
+		}
+		res := &ChannelCipherSuitesResponse{}
+		if err := c.Send(r, res); err != nil {
+			return nil, err
+		}
+		data = append(data, res.Data...)
+		if len(res.Data) < cipherSuitePageSize {
+			break
+		}
+	}
+	return parseCipherSuites(data)
+}
+
+// GetChannel gets the information, access configuration and authentication capabilities of a channel
+func (c *Client) GetChannel(channel uint8) (*Channel, error) {
+	info, err := c.GetChannelInfo(channel)
+	if err != nil {
+		return nil, err
+	}
+	ch := &Channel{
+		Number: info.Channel & 0x0f,
+		Info:   info,
+	}
+	if info.Sessions() == ChannelSessionless {
+		return ch, nil
+	}
+
+	if ch.Access, err = c.GetChannelAccess(channel, ChannelAccessVolatile); err != nil {
+		return nil, err
+	}
+	if ch.NonVolatileAccess, err = c.GetChannelAccess(channel, ChannelAccessNonVolatile); err != nil {
+		return nil, err
+	}
+	if ch.Auth, err = c.GetChannelAuthCapabilities(channel, PrivLevelCallback); err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+// Channels gets all the channels the BMC implements
+func (c *Client) Channels() ([]*Channel, error) {
+	var channels []*Channel
+	for n := uint8(0); n <= ChannelSystem; n++ {
+		if n > channelNumberMax && n != ChannelSystem {
+			continue
+		}
+		ch, err := c.GetChannel(n)
+		switch err {
+		case nil:
+			channels = append(channels, ch)
+		case ErrInvalidPacket, ErrParamRange, ErrNoObj:
+			// not implemented
+		default:
+			return nil, err
+		}
+	}
+	return channels, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannels(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	s.SetUser(2, "admin", "secret", PrivLevelAdmin)
+	err := s.Run()
+	assert.NoError(t, err)
+
+	c := s.NewConnection()
+	c.Username = "admin"
+	c.Password = "secret"
+	client, err := NewClient(c)
+	assert.NoError(t, err)
+	err = client.Open()
+	assert.NoError(t, err)
+
+	channels, err := client.Channels()
+	assert.NoError(t, err)
+	assert.Len(t, channels, 3)
+	assert.Equal(t, uint8(ChannelPrimaryIPMB), channels[0].Number)
+	assert.Nil(t, channels[0].Access)
+	assert.False(t, channels[0].AllowsAnonymous())
+	assert.Equal(t, uint8(ChannelSystem), channels[2].Number)
+	assert.Equal(t, uint8(ChannelMediumSystem), channels[2].Info.Medium())
+
+	lan := channels[1]
+	assert.Equal(t, uint8(ChannelMediumLAN), lan.Info.Medium())
+	assert.Equal(t, uint8(ChannelMultiSession), lan.Info.Sessions())
+	assert.Equal(t, uint8(1), lan.Info.ActiveSessions())
+	assert.True(t, lan.Enabled())
+	assert.True(t, lan.AllowsAnonymous())
+	assert.True(t, lan.AllowsCallback())
+	assert.True(t, lan.Auth.Status&AuthStatusNonNullUsers != 0)
+	assert.True(t, lan.Auth.SupportsIPMI15())
+	assert.False(t, lan.Auth.SupportsIPMI20())
+
+	// close the anonymous access
+	err = client.DisableUser(1)
+	assert.NoError(t, err)
+	lan, err = client.GetChannel(ChannelCurrent)
+	assert.NoError(t, err)
+	assert.False(t, lan.AllowsAnonymous())
+
+	// lower the volatile privilege limit only
+	access := *lan.Access
+	access.PrivilegeLimit = PrivLevelOperator
+	access.PerMessageAuth = false
+	err = client.SetChannelAccess(1, ChannelAccessVolatile, &access)
+	assert.NoError(t, err)
+	lan, err = client.GetChannel(1)
+	assert.NoError(t, err)
+	assert.Equal(t, &access, lan.Access)
+	assert.Equal(t, uint8(PrivLevelAdmin), lan.NonVolatileAccess.PrivilegeLimit)
+	assert.True(t, lan.Auth.Status&AuthStatusPerMessageOff != 0)
+
+	_, err = client.GetChannelAccess(0, ChannelAccessVolatile)
+	assert.Equal(t, ErrInvalidPacket, err)
+
+	suites, err := client.GetChannelCipherSuites(1)
+	assert.NoError(t, err)
+	assert.Len(t, suites, 4)
+	assert.Equal(t, uint8(3), suites[3].ID)
+	assert.Equal(t, []uint8{CipherConfidentialityAESCBC128}, suites[3].Confidentiality)
+
+	client.Close()
+
+	// administrator sessions are no longer allowed on the channel
+	client, err = NewClient(c)
+	assert.NoError(t, err)
+	err = client.Open()
+	assert.Equal(t, ErrSessionPrivLimit, err)
+	client.Close()
+
+	s.Stop()
+}
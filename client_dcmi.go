@@ -0,0 +1,214 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import "time"
+
+// GetDCMICapabilities gets a DCMICapParam* parameter of the DCMI capabilities
+func (c *Client) GetDCMICapabilities(param uint8) (*DCMICapabilitiesResponse, error) {
+	r := &Request{
+		NetworkFunctionGroupExtension,
+		CommandGetDCMICapabilities,
+		&DCMICapabilitiesRequest{DCMIGroupExtension, param},
+	}
+	res := &DCMICapabilitiesResponse{}
+	return res, c.Send(r, res)
+}
+
+// GetPowerReading gets the system power statistics since the last reset of the statistics
+func (c *Client) GetPowerReading() (*PowerReadingResponse, error) {
+	r := &Request{
+		NetworkFunctionGroupExtension,
+		CommandGetPowerReading,
+		&PowerReadingRequest{DCMIGroup: DCMIGroupExtension, Mode: DCMIPowerStatistics},
+	}
+	res := &PowerReadingResponse{}
+	return res, c.Send(r, res)
+}
+
+// GetEnhancedPowerReading gets the system power statistics over a rolling average time period,
+// one of DCMIPowerStatisticsAttributes
+func (c *Client) GetEnhancedPowerReading(period time.Duration) (*PowerReadingResponse, error) {
+	p, err := dcmiPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+	r := &Request{
+		NetworkFunctionGroupExtension,
+		CommandGetPowerReading,
+		&PowerReadingRequest{DCMIGroup: DCMIGroupExtension, Mode: DCMIPowerEnhancedStatistics, Period: p},
+	}
+	res := &PowerReadingResponse{}
+	return res, c.Send(r, res)
+}
+
+// GetPowerLimit gets the power limit, ErrDCMINoPowerLimit is returned when none is set
+func (c *Client) GetPowerLimit() (*DCMIPowerLimit, error) {
+	r := &Request{
+		NetworkFunctionGroupExtension,
+		CommandGetPowerLimit,
+		&PowerLimitRequest{DCMIGroup: DCMIGroupExtension},
+	}
+	res := &PowerLimitResponse{}
+	if err := c.Send(r, res); err != nil {
+		return nil, err
+	}
+	return &res.DCMIPowerLimit, nil
+}
+
+// SetPowerLimit sets the power limit, which takes effect once activated
+func (c *Client) SetPowerLimit(limit *DCMIPowerLimit) error {
+	r := &Request{
+		NetworkFunctionGroupExtension,
+		CommandSetPowerLimit,
+		&SetPowerLimitRequest{DCMIGroup: DCMIGroupExtension, DCMIPowerLimit: *limit},
+	}
+	return c.Send(r, &DCMIResponse{})
+}
+
+// ActivatePowerLimit activates or deactivates the power limit
+func (c *Client) ActivatePowerLimit(activate bool) error {
+	r := &Request{
+		NetworkFunctionGroupExtension,
+		CommandActivatePowerLimit,
+		&ActivatePowerLimitRequest{DCMIGroup: DCMIGroupExtension, Activate: boolBit(activate, 0x01)},
+	}
+	return c.Send(r, &DCMIResponse{})
+}
+
+// getDCMIString reads the asset tag or management controller ID string by blocks
+func (c *Client) getDCMIString(command Command) (string, error) {
+	var data []byte
+	for {
+		r := &Request{
+			NetworkFunctionGroupExtension,
+			command,
+			&DCMIStringRequest{DCMIGroupExtension, uint8(len(data)), dcmiStringBlockSize},
+		}
+		res := &DCMIStringResponse{}
+		if err := c.Send(r, res); err != nil {
+			return "", err
+		}
+		data = append(data, res.Data...)
+		if len(data) >= int(res.Total) || len(res.Data) == 0 {
+			break
+		}
+	}
+
+	// the management controller ID string is null terminated
+	for i, b := range data {
+		if b == 0 {
+			return string(data[:i]), nil
+		}
+	}
+	return string(data), nil
+}
+
+// setDCMIString writes the asset tag or management controller ID string by blocks
+func (c *Client) setDCMIString(command Command, data []byte) error {
+	for offset := 0; offset < len(data); offset += dcmiStringBlockSize {
+		end := offset + dcmiStringBlockSize
+		if end > len(data) {
+			end = len(data)
+		}
+		r := &Request{
+			NetworkFunctionGroupExtension,
+			command,
+			&SetDCMIStringRequest{DCMIGroupExtension, uint8(offset), data[offset:end]},
+		}
+		if err := c.Send(r, &DCMIStringResponse{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetAssetTag gets the asset tag
+func (c *Client) GetAssetTag() (string, error) {
+	return c.getDCMIString(CommandGetAssetTag)
+}
+
+// SetAssetTag sets the asset tag, up to 63 bytes
+func (c *Client) SetAssetTag(tag string) error {
+	if len(tag) == 0 || len(tag) > dcmiAssetTagSize {
+		return ErrDCMIValue
+	}
+	return c.setDCMIString(CommandSetAssetTag, []byte(tag))
+}
+
+// GetMCIDString gets the management controller identifier string, the DHCP host name of the BMC
+func (c *Client) GetMCIDString() (string, error) {
+	return c.getDCMIString(CommandGetMCIDString)
+}
+
+// SetMCIDString sets the management controller identifier string, up to 63 bytes
+func (c *Client) SetMCIDString(id string) error {
+	if len(id) == 0 || len(id) >= dcmiMCIDSize {
+		return ErrDCMIValue
+	}
+	return c.setDCMIString(CommandSetMCIDString, append([]byte(id), 0))
+}
+
+// GetDCMISensorInfo gets the SDR record IDs of the temperature sensors of a DCMIEntity*
+func (c *Client) GetDCMISensorInfo(entity uint8) ([]uint16, error) {
+	var ids []uint16
+	for {
+		r := &Request{
+			NetworkFunctionGroupExtension,
+			CommandGetDCMISensorInfo,
+			&DCMISensorRequest{
+				DCMIGroup:     DCMIGroupExtension,
+				SensorType:    DCMISensorTypeTemperature,
+				EntityID:      entity,
+				InstanceStart: uint8(len(ids) + 1),
+			},
+		}
+		res := &DCMISensorInfoResponse{}
+		if err := c.Send(r, res); err != nil {
+			return nil, err
+		}
+		ids = append(ids, res.RecordIDs...)
+		if len(ids) >= int(res.Total) || len(res.RecordIDs) == 0 {
+			return ids, nil
+		}
+	}
+}
+
+// GetTemperatureReadings gets the temperatures of the instances of a DCMIEntity*
+func (c *Client) GetTemperatureReadings(entity uint8) ([]DCMITemperature, error) {
+	var readings []DCMITemperature
+	for {
+		r := &Request{
+			NetworkFunctionGroupExtension,
+			CommandGetTemperatureReadings,
+			&DCMISensorRequest{
+				DCMIGroup:     DCMIGroupExtension,
+				SensorType:    DCMISensorTypeTemperature,
+				EntityID:      entity,
+				InstanceStart: uint8(len(readings) + 1),
+			},
+		}
+		res := &TemperatureReadingsResponse{}
+		if err := c.Send(r, res); err != nil {
+			return nil, err
+		}
+		readings = append(readings, res.Readings...)
+		if len(readings) >= int(res.Total) || len(res.Readings) == 0 {
+			return readings, nil
+		}
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDCMI(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+	err = client.Open()
+	assert.NoError(t, err)
+
+	res, err := client.GetDCMICapabilities(DCMICapParamSupported)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(0x01), res.MajorVersion)
+	caps := &DCMISupportedCapabilities{}
+	assert.NoError(t, caps.UnmarshalBinary(res.Data))
+	assert.True(t, caps.PowerManagement)
+
+	res, err = client.GetDCMICapabilities(DCMICapParamPowerStatsAttrs)
+	assert.NoError(t, err)
+	stats := &DCMIPowerStatisticsAttributes{}
+	assert.NoError(t, stats.UnmarshalBinary(res.Data))
+	assert.Contains(t, stats.Periods, 5*time.Minute)
+
+	// power statistics
+	s.SetPowerDraw(100)
+	s.SetPowerDraw(200)
+	reading, err := client.GetPowerReading()
+	assert.NoError(t, err)
+	assert.True(t, reading.IsActive())
+	assert.Equal(t, uint16(200), reading.Current)
+	assert.Equal(t, uint16(100), reading.Minimum)
+	assert.Equal(t, uint16(200), reading.Maximum)
+	assert.Equal(t, uint16(150), reading.Average)
+	assert.WithinDuration(t, time.Now(), reading.Time(), 2*time.Second)
+
+	reading, err = client.GetEnhancedPowerReading(5 * time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(200), reading.Current)
+	assert.Equal(t, 5*time.Minute, reading.StatisticsPeriod())
+	_, err = client.GetEnhancedPowerReading(2 * time.Minute)
+	assert.Equal(t, ErrParamRange, err)
+
+	// power limit
+	_, err = client.GetPowerLimit()
+	assert.Equal(t, ErrDCMINoPowerLimit, err)
+	err = client.ActivatePowerLimit(true)
+	assert.Equal(t, ErrDCMINoPowerLimit, err)
+
+	limit := &DCMIPowerLimit{
+		ExceptionAction: DCMIExceptionPowerOff,
+		Limit:           300,
+		CorrectionTime:  5000,
+		SamplingPeriod:  10,
+	}
+	err = client.SetPowerLimit(&DCMIPowerLimit{Limit: 2000, CorrectionTime: 5000, SamplingPeriod: 10})
+	assert.Equal(t, ErrDCMIPowerLimitRange, err)
+	err = client.SetPowerLimit(&DCMIPowerLimit{Limit: 300, CorrectionTime: 10, SamplingPeriod: 10})
+	assert.Equal(t, ErrDCMICorrectionTimeRange, err)
+	err = client.SetPowerLimit(limit)
+	assert.NoError(t, err)
+	current, err := client.GetPowerLimit()
+	assert.NoError(t, err)
+	assert.Equal(t, limit, current)
+
+	err = client.ActivatePowerLimit(true)
+	assert.NoError(t, err)
+
+	// exceeding the limit powers the system off
+	s.SetPowerDraw(350)
+	time.Sleep(10 * time.Millisecond)
+	status, err := client.ChassisStatus()
+	assert.NoError(t, err)
+	assert.False(t, status.IsSystemPowerOn())
+	events, err := client.GetSELEvents()
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, SDRSensorType(SDR_SENSOR_TYPECODES_CURRENT), events[0].SensorType)
+
+	err = client.ActivatePowerLimit(false)
+	assert.NoError(t, err)
+
+	// asset tag and management controller ID string, by blocks of 16 bytes
+	tag, err := client.GetAssetTag()
+	assert.NoError(t, err)
+	assert.Equal(t, "", tag)
+	long := strings.Repeat("0123456789", 4)
+	err = client.SetAssetTag(long)
+	assert.NoError(t, err)
+	tag, err = client.GetAssetTag()
+	assert.NoError(t, err)
+	assert.Equal(t, long, tag)
+	err = client.SetAssetTag("rack-12")
+	assert.NoError(t, err)
+	tag, err = client.GetAssetTag()
+	assert.NoError(t, err)
+	assert.Equal(t, "rack-12", tag)
+	err = client.SetAssetTag(strings.Repeat("x", 64))
+	assert.Equal(t, ErrDCMIValue, err)
+
+	id, err := client.GetMCIDString()
+	assert.NoError(t, err)
+	assert.Equal(t, "simulator", id)
+	err = client.SetMCIDString("bmc-rack-12-node-3")
+	assert.NoError(t, err)
+	id, err = client.GetMCIDString()
+	assert.NoError(t, err)
+	assert.Equal(t, "bmc-rack-12-node-3", id)
+
+	// temperatures
+	ids, err := client.GetDCMISensorInfo(DCMIEntityCPU)
+	assert.NoError(t, err)
+	assert.Len(t, ids, 2)
+	temps, err := client.GetTemperatureReadings(DCMIEntityCPU)
+	assert.NoError(t, err)
+	assert.Equal(t, []DCMITemperature{{45, 1}, {47, 2}}, temps)
+	temps, err = client.GetTemperatureReadings(DCMIEntityInlet)
+	assert.NoError(t, err)
+	assert.Equal(t, []DCMITemperature{{22, 1}}, temps)
+
+	client.Close()
+	s.Stop()
+}
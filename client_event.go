@@ -0,0 +1,134 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+// SetEventReceiver sets where the BMC sends the event messages it generates per section 29.1,
+// EventReceiverDisabled disables event message generation
+func (c *Client) SetEventReceiver(address, lun uint8) error {
+	r := &Request{
+		NetworkFunctionSensorEvent,
+		CommandSetEventReceiver,
+		&SetEventReceiverRequest{address, lun & 0x03},
+	}
+	return c.Send(r, &SetEventReceiverResponse{})
+}
+
+// GetEventReceiver gets the event receiver slave address and LUN per section 29.2
+func (c *Client) GetEventReceiver() (*EventReceiverResponse, error) {
+	r := &Request{
+		NetworkFunctionSensorEvent,
+		CommandGetEventReceiver,
+		&EventReceiverRequest{},
+	}
+	res := &EventReceiverResponse{}
+	return res, c.Send(r, res)
+}
+
+// PlatformEvent sends an event message to the BMC per section 29.3, which logs it in the SEL.
+// The GeneratorID of e is not sent, the BMC records the requester address and LUN instead
+func (c *Client) PlatformEvent(e *SELEvent) error {
+	r := &Request{
+		NetworkFunctionSensorEvent,
+		CommandPlatformEvent,
+		&PlatformEventRequest{e},
+	}
+	return c.Send(r, &PlatformEventResponse{})
+}
+
+// GetBMCGlobalEnables gets the BMCGlobal* bits per section 22.2
+func (c *Client) GetBMCGlobalEnables() (uint8, error) {
+	r := &Request{
+		NetworkFunctionApp,
+		CommandGetBMCGlobalEnables,
+		&BMCGlobalEnablesRequest{},
+	}
+	res := &BMCGlobalEnablesResponse{}
+	err := c.Send(r, res)
+	return res.Enables, err
+}
+
+// SetBMCGlobalEnables sets the BMCGlobal* bits per section 22.1
+func (c *Client) SetBMCGlobalEnables(enables uint8) error {
+	r := &Request{
+		NetworkFunctionApp,
+		CommandSetBMCGlobalEnables,
+		&SetBMCGlobalEnablesRequest{enables},
+	}
+	return c.Send(r, &SetBMCGlobalEnablesResponse{})
+}
+
+// UpdateBMCGlobalEnables sets the enable bits and clears the disable bits, keeping the others
+func (c *Client) UpdateBMCGlobalEnables(enable, disable uint8) error {
+	enables, err := c.GetBMCGlobalEnables()
+	if err != nil {
+		return err
+	}
+	return c.SetBMCGlobalEnables(enables&^disable | enable)
+}
+
+// ReadEventMessageBuffer reads the next message of the event message buffer per section 22.8,
+// ErrEventBufferEmpty is returned when there is none
+func (c *Client) ReadEventMessageBuffer() (*SELEvent, error) {
+	r := &Request{
+		NetworkFunctionApp,
+		CommandReadEventMessageBuffer,
+		&ReadEventMessageBufferRequest{},
+	}
+	res := &ReadEventMessageBufferResponse{}
+	if err := c.Send(r, res); err != nil {
+		return nil, err
+	}
+	e := &SELEvent{}
+	return e, e.UnmarshalBinary(res.Data[:])
+}
+
+// GetSELEntry reads the SEL record id, SELEntryFirst or SELEntryLast, per section 31.5.
+// The ID of the next record is returned, SELEntryLast after the last record
+func (c *Client) GetSELEntry(id uint16) (*SELEvent, uint16, error) {
+	r := &Request{
+		NetworkFunctionStorge,
+		CommandGetSELEntry,
+		&SELEntryRequest{
+			RecordID: id,
+			Length:   selReadEntireRecord,
+		},
+	}
+	res := &SELEntryResponse{}
+	if err := c.Send(r, res); err != nil {
+		return nil, 0, err
+	}
+	e := &SELEvent{}
+	return e, res.NextRecordID, e.UnmarshalBinary(res.Data)
+}
+
+// GetSELEvents reads all the SEL records
+func (c *Client) GetSELEvents() ([]*SELEvent, error) {
+	var events []*SELEvent
+	for id := uint16(SELEntryFirst); id != SELEntryLast; {
+		e, next, err := c.GetSELEntry(id)
+		if err == ErrNoObj && id == SELEntryFirst {
+			// empty SEL
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+		id = next
+	}
+	return events, nil
+}
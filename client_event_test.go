@@ -0,0 +1,129 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvents(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+	err = client.Open()
+	assert.NoError(t, err)
+
+	receiver, err := client.GetEventReceiver()
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(bmcSlaveAddr), receiver.Address)
+
+	err = client.SetEventReceiver(EventReceiverDisabled, 0)
+	assert.NoError(t, err)
+	receiver, err = client.GetEventReceiver()
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(EventReceiverDisabled), receiver.Address)
+
+	events, err := client.GetSELEvents()
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+
+	enables, err := client.GetBMCGlobalEnables()
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(BMCGlobalSystemEventLogging), enables)
+
+	err = client.UpdateBMCGlobalEnables(BMCGlobalEventBuffer|BMCGlobalOEM0, 0)
+	assert.NoError(t, err)
+	enables, err = client.GetBMCGlobalEnables()
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(BMCGlobalSystemEventLogging|BMCGlobalEventBuffer|BMCGlobalOEM0), enables)
+
+	_, err = client.ReadEventMessageBuffer()
+	assert.Equal(t, ErrEventBufferEmpty, err)
+
+	fan := &SELEvent{
+		RecordType:   SELRecordSystemEvent,
+		GeneratorID:  0x1081, // the client software ID on the LAN channel
+		EvMRev:       SELEvMRev,
+		SensorType:   SDR_SENSOR_TYPECODES_FAN,
+		SensorNumber: 0x40,
+		EventType:    SENSOR_READTYPE_THREADHOLD,
+		EventData:    [3]uint8{0x52, 0xff, 0xff},
+	}
+	temp := &SELEvent{
+		RecordType:   SELRecordSystemEvent,
+		GeneratorID:  0x1081, // the client software ID on the LAN channel
+		EvMRev:       SELEvMRev,
+		SensorType:   SDR_SENSOR_TYPECODES_TEMPERATURE,
+		SensorNumber: 0x30,
+		EventType:    SENSOR_READTYPE_THREADHOLD,
+		Deassertion:  true,
+		EventData:    [3]uint8{0x59, 0x40, 0x50},
+	}
+	for _, e := range []*SELEvent{fan, temp} {
+		err = client.PlatformEvent(e)
+		assert.NoError(t, err)
+	}
+
+	// the event message buffer is a queue
+	for _, e := range []*SELEvent{fan, temp} {
+		msg, err := client.ReadEventMessageBuffer()
+		assert.NoError(t, err)
+		assert.Equal(t, e.SensorType, msg.SensorType)
+		assert.Equal(t, e.Deassertion, msg.Deassertion)
+		assert.Equal(t, e.EventData, msg.EventData)
+	}
+	_, err = client.ReadEventMessageBuffer()
+	assert.Equal(t, ErrEventBufferEmpty, err)
+
+	events, err = client.GetSELEvents()
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	for i, e := range []*SELEvent{fan, temp} {
+		assert.Equal(t, uint16(i+1), events[i].RecordID)
+		assert.False(t, events[i].Timestamp.IsZero())
+		e.RecordID = events[i].RecordID
+		e.Timestamp = events[i].Timestamp
+		assert.Equal(t, e, events[i])
+	}
+
+	last, next, err := client.GetSELEntry(SELEntryLast)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(SELEntryLast), next)
+	assert.Equal(t, temp, last)
+	_, _, err = client.GetSELEntry(3)
+	assert.Equal(t, ErrNoObj, err)
+
+	// events are neither logged nor buffered once disabled
+	err = client.UpdateBMCGlobalEnables(0, BMCGlobalSystemEventLogging|BMCGlobalEventBuffer)
+	assert.NoError(t, err)
+	err = client.PlatformEvent(fan)
+	assert.NoError(t, err)
+	events, err = client.GetSELEvents()
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	_, err = client.ReadEventMessageBuffer()
+	assert.Equal(t, ErrEventBufferEmpty, err)
+
+	client.Close()
+	s.Stop()
+}
@@ -0,0 +1,281 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import "errors"
+
+var (
+	ErrFRUBridgingNotSupported = errors.New("FRU device is behind a satellite controller, bridging not supported")
+)
+
+const (
+	// bytes requested per Read FRU Data / Master Write-Read, halved when the BMC can't return that many
+	fruReadChunkSize = 16
+	// non-intelligent FRU devices are assumed to be 8-bit addressed SEEPROMs (24C02 and alike)
+	fruPhysicalSize = 256
+)
+
+// FRUDevice describes a FRU device resolved from the SDR repository
+type FRUDevice struct {
+	Name               string
+	Logical            bool
+	FRUDeviceID        uint8 // FRU Device ID for logical devices, slave address otherwise
+	AccessAddr         uint8
+	AccessLUN          uint8
+	PrivateBusID       uint8
+	Channel            uint8
+	DeviceType         uint8
+	DeviceTypeModifier uint8
+	EntityID           uint8
+	EntityInstance     uint8
+	Data               []byte
+	Err                error // why Data is nil when GetFRUInventory could not read the device
+}
+
+func newFRUDeviceFromLocator(r *SDRFruDeviceLocator) *FRUDevice {
+	return &FRUDevice{
+		Name:               r.DeviceId(),
+		Logical:            r.IsLogical(),
+		FRUDeviceID:        r.FRUDeviceID,
+		AccessAddr:         r.DeviceAccAddr,
+		AccessLUN:          r.AccessLUN(),
+		PrivateBusID:       r.PrivateBusID(),
+		Channel:            r.Channel(),
+		DeviceType:         r.DeviceType,
+		DeviceTypeModifier: r.DevTypeModif,
+		EntityID:           r.FruEntityId,
+		EntityInstance:     r.FruEntityInst,
+	}
+}
+
+// FRUInventoryAreaInfo get the size and access type of a logical FRU device
+func (c *Client) FRUInventoryAreaInfo(fruID uint8) (*FRUInventoryAreaInfoResponse, error) {
+	req := &Request{
+		NetworkFunctionStorge,
+		CommandGetFRUInventoryAreaInfo,
+		&FRUInventoryAreaInfoRequest{
+			FRUDeviceID: fruID,
+		},
+	}
+	res := &FRUInventoryAreaInfoResponse{}
+	return res, c.Send(req, res)
+}
+
+// ReadFRUData reads count bytes (or words, depending on the access type) at offset of a logical FRU device
+func (c *Client) ReadFRUData(fruID uint8, offset uint16, count uint8) ([]byte, error) {
+	req := &Request{
+		NetworkFunctionStorge,
+		CommandReadFRUData,
+		&ReadFRUDataRequest{
+			FRUDeviceID: fruID,
+			Offset:      offset,
+			CountToRead: count,
+		},
+	}
+	res := &ReadFRUDataResponse{}
+	if err := c.Send(req, res); err != nil {
+		return nil, err
+	}
+	return res.Data, nil
+}
+
+// MasterWriteRead writes data to and then reads readCount bytes from a device on an I2C/IPMB bus
+func (c *Client) MasterWriteRead(busID, slaveAddr, readCount uint8, data []byte) ([]byte, error) {
+	req := &Request{
+		NetworkFunctionApp,
+		CommandMasterWriteRead,
+		&MasterWriteReadRequest{
+			BusID:     busID,
+			SlaveAddr: slaveAddr,
+			ReadCount: readCount,
+			Data:      data,
+		},
+	}
+	res := &MasterWriteReadResponse{}
+	if err := c.Send(req, res); err != nil {
+		return nil, err
+	}
+	return res.Data, nil
+}
+
+// ReadFRU reads the whole inventory area of a logical FRU device on the BMC
+func (c *Client) ReadFRU(fruID uint8) ([]byte, error) {
+	info, err := c.FRUInventoryAreaInfo(fruID)
+	if err != nil {
+		return nil, err
+	}
+
+	unit := 1
+	if info.WordAccess() {
+		unit = 2
+	}
+
+	size := int(info.AreaSize)
+	data := make([]byte, 0, size)
+	chunk := fruReadChunkSize
+	for len(data) < size {
+		n := size - len(data)
+		if n > chunk {
+			n = chunk
+		}
+		buf, err := c.ReadFRUData(fruID, uint16(len(data)/unit), uint8(n/unit))
+		if err != nil {
+			if shrinkReadChunk(err, &chunk, unit) {
+				continue
+			}
+			return nil, err
+		}
+		if len(buf) == 0 {
+			break
+		}
+		data = append(data, buf...)
+	}
+
+	return data, nil
+}
+
+// shrinkReadChunk halves the read chunk size when the BMC can't return the number of bytes requested
+func shrinkReadChunk(err error, chunk *int, min int) bool {
+	switch err {
+	case ErrRequestData, ErrLongPacket, ErrCommandTimeout:
+		if *chunk/2 >= min {
+			*chunk /= 2
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) readPhysicalFRU(dev *FRUDevice) ([]byte, error) {
+	busID := dev.Channel<<4 | dev.PrivateBusID<<1
+	if dev.AccessAddr != 0 {
+		busID |= 0x01 // private bus behind the access controller
+	}
+
+	data := make([]byte, 0, fruPhysicalSize)
+	chunk := fruReadChunkSize
+	for len(data) < fruPhysicalSize {
+		n := fruPhysicalSize - len(data)
+		if n > chunk {
+			n = chunk
+		}
+		// write the SEEPROM word address, then read
+		buf, err := c.MasterWriteRead(busID, dev.FRUDeviceID, uint8(n), []byte{uint8(len(data))})
+		if err != nil {
+			if shrinkReadChunk(err, &chunk, 1) {
+				continue
+			}
+			return nil, err
+		}
+		if len(buf) == 0 {
+			break
+		}
+		data = append(data, buf...)
+	}
+
+	return data, nil
+}
+
+// bridged tells whether dev is a logical device behind a satellite controller
+func (dev *FRUDevice) bridged() bool {
+	return dev.Logical && dev.AccessAddr != 0 && dev.AccessAddr != bmcSlaveAddr
+}
+
+// ReadFRUDevice reads the FRU data of dev.
+// Logical devices are read with Read FRU Data, non-intelligent ones with Master Write-Read.
+func (c *Client) ReadFRUDevice(dev *FRUDevice) ([]byte, error) {
+	if !dev.Logical {
+		return c.readPhysicalFRU(dev)
+	}
+	if dev.bridged() {
+		return nil, ErrFRUBridgingNotSupported
+	}
+	return c.ReadFRU(dev.FRUDeviceID)
+}
+
+// GetFRUDeviceList walks the SDR repository and resolves the FRU devices described by
+// FRU Device Locator and MC Device Locator records. The BMC's built-in FRU 0 comes first.
+// Logical devices behind a satellite controller are listed too, although ReadFRUDevice
+// can't read them as that needs the request to be bridged over IPMB.
+func (c *Client) GetFRUDeviceList(reservationID uint16) ([]*FRUDevice, error) {
+	devices := []*FRUDevice{
+		{
+			Name:        "Builtin FRU Device",
+			Logical:     true,
+			FRUDeviceID: 0,
+			AccessAddr:  bmcSlaveAddr,
+		},
+	}
+
+	records, err := c.readSDRRepository(reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		switch r := record.SDRRecord.(type) {
+		case *SDRFruDeviceLocator:
+			devices = append(devices, newFRUDeviceFromLocator(r))
+		case *SDRMcDeviceLocator:
+			if !r.HasFRUInventory() {
+				continue
+			}
+			// FRU 0 of the BMC itself is the built-in device
+			if r.DeviceSlaveAddr == bmcSlaveAddr {
+				devices[0].Channel = r.ChannelNumber & 0x0f
+				devices[0].EntityID = r.EntityId
+				devices[0].EntityInstance = r.EntityIns
+				continue
+			}
+			devices = append(devices, &FRUDevice{
+				Name:           r.DeviceId(),
+				Logical:        true,
+				FRUDeviceID:    0,
+				AccessAddr:     r.DeviceSlaveAddr,
+				Channel:        r.ChannelNumber & 0x0f,
+				EntityID:       r.EntityId,
+				EntityInstance: r.EntityIns,
+			})
+		}
+	}
+
+	return devices, nil
+}
+
+// GetFRUInventory discovers the FRU devices in the SDR repository and reads each one.
+// Devices that can't be read (not present, bridged, ...) are returned with nil Data
+// and the reason in Err.
+func (c *Client) GetFRUInventory(reservationID uint16) ([]*FRUDevice, error) {
+	devices, err := c.GetFRUDeviceList(reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dev := range devices {
+		data, err := c.ReadFRUDevice(dev)
+		if err != nil {
+			if _, ok := err.(CompletionCode); ok || err == ErrFRUBridgingNotSupported {
+				dev.Err = err
+				continue
+			}
+			return nil, err
+		}
+		dev.Data = data
+	}
+
+	return devices, nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFRUInventory(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	resp := s.reserveRepository(nil)
+	reserve, _ := resp.(*ReserveRepositoryResponse)
+
+	psu, _ := NewSDRFruDeviceLocator(3, "PSU1 FRU")
+	psu.DeviceAccAddr = bmcSlaveAddr
+	psu.FRUDeviceID = 1
+	psu.LogPhyAccLUNBusID = 0x80
+	psu.FruEntityId = 0x0a
+	psu.FruEntityInst = 0x01
+
+	dimm, _ := NewSDRFruDeviceLocator(4, "DIMM A1")
+	dimm.DeviceAccAddr = bmcSlaveAddr
+	dimm.FRUDeviceID = 0xa0
+	dimm.LogPhyAccLUNBusID = 0x02 // physical, private bus 2
+	dimm.DeviceType = 0x10
+	dimm.DevTypeModif = 0x02
+
+	bmc, _ := NewSDRMcDeviceLocator(5, "BMC")
+	bmc.DeviceSlaveAddr = bmcSlaveAddr
+	bmc.DeviceCap = 0x08
+
+	satellite, _ := NewSDRMcDeviceLocator(6, "Backplane")
+	satellite.DeviceSlaveAddr = 0xc0
+	satellite.DeviceCap = 0x08
+
+	rep := defaultRepo[reserve.ReservationId]
+	for _, r := range []SDRRecord{psu, dimm, bmc, satellite} {
+		rep.addRecord(&sDRRecordAndValue{SDRRecord: r})
+	}
+
+	builtin := make([]byte, 40)
+	for i := range builtin {
+		builtin[i] = uint8(i)
+	}
+	s.fru[0] = builtin
+	s.fru[1] = []byte{0x01, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0xfe}
+	spd := make([]byte, 128)
+	spd[0] = 0x92
+	s.i2c[i2cKey(0x05, 0xa0)] = spd
+
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	devices, err := client.GetFRUInventory(reserve.ReservationId)
+	assert.NoError(t, err)
+	require.Len(t, devices, 4)
+
+	assert.Equal(t, "Builtin FRU Device", devices[0].Name)
+	assert.Equal(t, builtin, devices[0].Data)
+
+	assert.Equal(t, "PSU1 FRU", devices[1].Name)
+	assert.True(t, devices[1].Logical)
+	assert.Equal(t, uint8(0x0a), devices[1].EntityID)
+	assert.Equal(t, s.fru[1], devices[1].Data)
+
+	assert.Equal(t, "DIMM A1", devices[2].Name)
+	assert.False(t, devices[2].Logical)
+	assert.Equal(t, uint8(2), devices[2].PrivateBusID)
+	assert.Equal(t, fruPhysicalSize, len(devices[2].Data))
+	assert.Equal(t, uint8(0x92), devices[2].Data[0])
+	assert.Equal(t, uint8(0x92), devices[2].Data[128])
+
+	// the backplane FRU is behind a satellite controller
+	assert.Equal(t, "Backplane", devices[3].Name)
+	assert.Equal(t, uint8(0xc0), devices[3].AccessAddr)
+	assert.Nil(t, devices[3].Data)
+	assert.Equal(t, ErrFRUBridgingNotSupported, devices[3].Err)
+
+	_, err = client.ReadFRUDevice(devices[3])
+	assert.Equal(t, ErrFRUBridgingNotSupported, err)
+
+	_, err = client.ReadFRU(7)
+	assert.Equal(t, ErrNoObj, err)
+
+	err = client.Close()
+	assert.NoError(t, err)
+	s.Stop()
+}
+
+func TestReadFRUShrinksChunk(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	data := make([]byte, 20)
+	for i := range data {
+		data[i] = uint8(0xff - i)
+	}
+	s.fru[0] = data
+
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	readFRUData := s.readFRUData
+	s.SetHandler(NetworkFunctionStorge, CommandReadFRUData, func(m *Message) Response {
+		r := &ReadFRUDataRequest{}
+		if err := m.Request(r); err != nil {
+			return err
+		}
+		if r.CountToRead > 4 {
+			return ErrRequestData
+		}
+		return readFRUData(m)
+	})
+
+	fru, err := client.ReadFRU(0)
+	assert.NoError(t, err)
+	assert.Equal(t, data, fru)
+
+	err = client.Close()
+	assert.NoError(t, err)
+	s.Stop()
+}
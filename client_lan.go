@@ -0,0 +1,163 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+// configParamData is the encoded data of a LAN, SOL, PEF or boot options configuration parameter
+type configParamData struct {
+	param uint8
+	data  []uint8
+}
+
+// setConfigParams writes the parameters with set within a single set-in-progress
+// transaction when the BMC implements it, per section 23.2 - table 23-4, 26.3 - table 26-5,
+// 30.4 - table 30-6 and 28.12
+func setConfigParams(set func(param uint8, data ...uint8) error, params []configParamData) error {
+	useProgress := true
+	// set set-in-progress flag, parameter 0 of LAN, SOL, PEF and boot options alike
+	err := set(0, 0x01)
+	if err == ErrSetInProgress {
+		// another party is in the middle of an update
+		return err
+	}
+	if err != nil {
+		useProgress = false
+	}
+
+	for _, p := range params {
+		err = set(p.param, p.data...)
+		if err != nil {
+			break
+		}
+	}
+
+	if err == nil {
+		if useProgress {
+			// set-in-progress = commit-write
+			_ = set(0, 0x02)
+		}
+	}
+
+	if useProgress {
+		// set-in-progress = set-complete
+		_ = set(0, 0x00)
+	}
+
+	return err
+}
+
+func (c *Client) setLANParam(channel, param uint8, data ...uint8) error {
+	r := &Request{
+		NetworkFunctionTransport,
+		CommandSetLANConfig,
+		&SetLANConfigRequest{
+			Channel: channel,
+			Param:   param,
+			Data:    data,
+		},
+	}
+	return c.Send(r, &SetLANConfigResponse{})
+}
+
+// setLANParams writes the parameters within a single set-in-progress transaction
+func (c *Client) setLANParams(channel uint8, params []configParamData) error {
+	return setConfigParams(func(param uint8, data ...uint8) error {
+		return c.setLANParam(channel, param, data...)
+	}, params)
+}
+
+// GetLANParam reads a LAN configuration parameter per section 23.2,
+// set selects the set if the parameter has any
+func (c *Client) GetLANParam(channel, param, set uint8) (*LANConfigResponse, error) {
+	r := &Request{
+		NetworkFunctionTransport,
+		CommandGetLANConfig,
+		&LANConfigRequest{
+			Channel: channel,
+			Param:   param,
+			Set:     set,
+		},
+	}
+	res := &LANConfigResponse{}
+	return res, c.Send(r, res)
+}
+
+// SetLANParam writes a LAN configuration parameter, wrapped in the
+// set-in-progress protocol when the BMC implements it, per section 23.1
+func (c *Client) SetLANParam(channel, param uint8, data ...uint8) error {
+	return c.setLANParams(channel, []configParamData{{param, data}})
+}
+
+// GetLANConfig reads the parameter p of the LAN channel into p.
+// The set selector of the IPv6 address and alert destination parameters is taken from p
+func (c *Client) GetLANConfig(channel uint8, p LANConfigParamDecoder) error {
+	var set uint8
+	if s, ok := p.(lanConfigSetParam); ok {
+		set = s.setSelector()
+	}
+	res, err := c.GetLANParam(channel, p.Param(), set)
+	if err != nil {
+		return err
+	}
+	return p.UnmarshalBinary(res.Data)
+}
+
+// SetLANConfig writes the parameters of the LAN channel in order, as a single
+// set-in-progress transaction. Changing the address of the channel in use
+// takes effect once committed, the remaining commands of the transaction may fail
+func (c *Client) SetLANConfig(channel uint8, params ...LANConfigParam) error {
+	list := make([]configParamData, len(params))
+	for i, p := range params {
+		data, err := p.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		list[i] = configParamData{p.Param(), data}
+	}
+	return c.setLANParams(channel, list)
+}
+
+// GetLANCipherSuitePrivLevels maps the cipher suite IDs supported by the channel
+// to their maximum privilege level
+func (c *Client) GetLANCipherSuitePrivLevels(channel uint8) (map[uint8]uint8, error) {
+	res, err := c.GetLANParam(channel, LANParamCipherSuiteEntryCount, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Data) < 1 {
+		return nil, ErrShortPacket
+	}
+	count := int(res.Data[0] & 0x1f)
+
+	var entries LANCipherSuiteEntries
+	if err = c.GetLANConfig(channel, &entries); err != nil {
+		return nil, err
+	}
+	if count > len(entries) || count > lanCipherSuiteEntriesMax {
+		return nil, ErrShortPacket
+	}
+
+	levels := &LANCipherSuitePrivLevels{}
+	if err = c.GetLANConfig(channel, levels); err != nil {
+		return nil, err
+	}
+
+	privs := make(map[uint8]uint8, count)
+	for i, id := range entries[:count] {
+		privs[id] = levels[i]
+	}
+	return privs, nil
+}
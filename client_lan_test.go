@@ -0,0 +1,136 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLANConfig(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+	err = client.Open()
+	assert.NoError(t, err)
+
+	var source LANIPSource
+	err = client.GetLANConfig(ChannelCurrent, &source)
+	assert.NoError(t, err)
+	assert.Equal(t, LANIPSourceStatic, source)
+
+	mac := &LANMACAddress{}
+	err = client.GetLANConfig(1, mac)
+	assert.NoError(t, err)
+	assert.Equal(t, "02:00:00:00:00:01", mac.MAC.String())
+
+	// move the BMC to another subnet
+	err = client.SetLANConfig(1,
+		LANIPSourceStatic,
+		&LANIPAddress{net.IPv4(10, 20, 0, 5)},
+		&LANSubnetMask{net.IPv4Mask(255, 255, 0, 0)},
+		&LANDefaultGateway{net.IPv4(10, 20, 0, 1)},
+		&LANVLAN{Enabled: true, ID: 42},
+		LANVLANPriority(3),
+	)
+	assert.NoError(t, err)
+
+	ip := &LANIPAddress{}
+	mask := &LANSubnetMask{}
+	gateway := &LANDefaultGateway{}
+	vlan := &LANVLAN{}
+	for _, p := range []LANConfigParamDecoder{ip, mask, gateway, vlan} {
+		err = client.GetLANConfig(1, p)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, "10.20.0.5", ip.IP.String())
+	assert.Equal(t, "ffff0000", mask.Mask.String())
+	assert.Equal(t, "10.20.0.1", gateway.IP.String())
+	assert.Equal(t, &LANVLAN{Enabled: true, ID: 42}, vlan)
+
+	// the transaction is complete
+	res, err := client.GetLANParam(1, LANParamSetInProgress, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint8{0x00}, res.Data)
+
+	// another party holds set-in-progress
+	err = client.setLANParam(1, LANParamSetInProgress, 0x01)
+	assert.NoError(t, err)
+	err = client.SetLANConfig(1, LANCommunityString("private"))
+	assert.Equal(t, ErrLANSetInProgress, err)
+	err = client.setLANParam(1, LANParamSetInProgress, 0x00)
+	assert.NoError(t, err)
+
+	err = client.SetLANConfig(1, LANIPv6Support(LANIPv6SupportIPv6Only))
+	assert.Equal(t, ErrLANParamReadOnly, err)
+	err = client.setLANParam(1, LANParamCipherSuiteEntries, 0x00, 0x03)
+	assert.Equal(t, ErrLANParamReadOnly, err)
+	err = client.SetLANConfig(1, &LANIPAddress{net.ParseIP("2001:db8::1")})
+	assert.Equal(t, ErrLANParamValue, err)
+	_, err = client.GetLANParam(1, 0x63, 0)
+	assert.Equal(t, ErrLANParamNotSupported, err)
+	_, err = client.GetLANParam(ChannelPrimaryIPMB, LANParamIPAddress, 0)
+	assert.Equal(t, ErrInvalidPacket, err)
+
+	privs, err := client.GetLANCipherSuitePrivLevels(1)
+	assert.NoError(t, err)
+	assert.Equal(t, map[uint8]uint8{
+		0: PrivLevelAdmin,
+		1: PrivLevelAdmin,
+		2: PrivLevelAdmin,
+		3: PrivLevelAdmin,
+	}, privs)
+
+	// IPv6
+	status := &LANIPv6Status{}
+	err = client.GetLANConfig(1, status)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(2), status.StaticAddresses)
+
+	static := &LANIPv6StaticAddress{LANIPv6Address{
+		Set:          1,
+		Enabled:      true,
+		Address:      net.ParseIP("2001:db8::5"),
+		PrefixLength: 64,
+	}}
+	err = client.SetLANConfig(1, LANIPv4AndIPv6, static)
+	assert.NoError(t, err)
+
+	var addressing LANIPAddressing
+	err = client.GetLANConfig(1, &addressing)
+	assert.NoError(t, err)
+	assert.Equal(t, LANIPv4AndIPv6, addressing)
+
+	addr := &LANIPv6StaticAddress{LANIPv6Address{Set: 1}}
+	err = client.GetLANConfig(1, addr)
+	assert.NoError(t, err)
+	assert.Equal(t, "2001:db8::5", addr.Address.String())
+	assert.Equal(t, uint8(64), addr.PrefixLength)
+	assert.Equal(t, uint8(LANIPv6StatusActive), addr.Status)
+
+	dynamic := &LANIPv6DynamicAddress{LANIPv6Address{Set: 2}}
+	err = client.GetLANConfig(1, dynamic)
+	assert.Equal(t, ErrParamRange, err)
+
+	client.Close()
+	s.Stop()
+}
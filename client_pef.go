@@ -0,0 +1,220 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import "errors"
+
+// ErrPEFTableFull is returned when the event filter table has no free entry
+var ErrPEFTableFull = errors.New("no free PEF event filter entry")
+
+// GetPEFCapabilities gets the PEF version, actions and event filter table size per section 30.1
+func (c *Client) GetPEFCapabilities() (*PEFCapabilitiesResponse, error) {
+	r := &Request{
+		NetworkFunctionSensorEvent,
+		CommandGetPEFCapabilities,
+		&PEFCapabilitiesRequest{},
+	}
+	res := &PEFCapabilitiesResponse{}
+	return res, c.Send(r, res)
+}
+
+// ArmPEFPostponeTimer postpones PEF for timeout seconds, or one of the PEFPostpone*
+// values, per section 30.2. The present countdown value is returned
+func (c *Client) ArmPEFPostponeTimer(timeout uint8) (uint8, error) {
+	r := &Request{
+		NetworkFunctionSensorEvent,
+		CommandArmPEFPostponeTimer,
+		&ArmPEFPostponeTimerRequest{timeout},
+	}
+	res := &ArmPEFPostponeTimerResponse{}
+	err := c.Send(r, res)
+	return res.Countdown, err
+}
+
+func (c *Client) setPEFParam(param uint8, data ...uint8) error {
+	r := &Request{
+		NetworkFunctionSensorEvent,
+		CommandSetPEFConfig,
+		&SetPEFConfigRequest{
+			Param: param,
+			Data:  data,
+		},
+	}
+	return c.Send(r, &SetPEFConfigResponse{})
+}
+
+// setPEFParams writes the parameters within a single set-in-progress transaction
+func (c *Client) setPEFParams(params []configParamData) error {
+	return setConfigParams(c.setPEFParam, params)
+}
+
+// GetPEFParam reads a PEF configuration parameter per section 30.4,
+// set and block select the set and block if the parameter has any
+func (c *Client) GetPEFParam(param, set, block uint8) (*PEFConfigResponse, error) {
+	r := &Request{
+		NetworkFunctionSensorEvent,
+		CommandGetPEFConfig,
+		&PEFConfigRequest{
+			Param: param,
+			Set:   set,
+			Block: block,
+		},
+	}
+	res := &PEFConfigResponse{}
+	return res, c.Send(r, res)
+}
+
+// SetPEFParam writes a PEF configuration parameter, wrapped in the
+// set-in-progress protocol when the BMC implements it, per section 30.3
+func (c *Client) SetPEFParam(param uint8, data ...uint8) error {
+	return c.setPEFParams([]configParamData{{param, data}})
+}
+
+// GetPEFConfig reads the parameter p into p. The set selector of the
+// event filter, alert policy and alert string key parameters is taken from p
+func (c *Client) GetPEFConfig(p PEFConfigParamDecoder) error {
+	var set uint8
+	if s, ok := p.(pefConfigSetParam); ok {
+		set = s.setSelector()
+	}
+	res, err := c.GetPEFParam(p.Param(), set, 0)
+	if err != nil {
+		return err
+	}
+	return p.UnmarshalBinary(res.Data)
+}
+
+// SetPEFConfig writes the parameters in order, as a single set-in-progress transaction
+func (c *Client) SetPEFConfig(params ...PEFConfigParam) error {
+	list := make([]configParamData, len(params))
+	for i, p := range params {
+		data, err := p.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		list[i] = configParamData{p.Param(), data}
+	}
+	return c.setPEFParams(list)
+}
+
+// pefCount reads one of the read only table size parameters
+func (c *Client) pefCount(param uint8) (uint8, error) {
+	res, err := c.GetPEFParam(param, 0, 0)
+	if err != nil {
+		return 0, err
+	}
+	if len(res.Data) < 1 {
+		return 0, ErrShortPacket
+	}
+	return res.Data[0] & 0x7f, nil
+}
+
+// GetPEFEventFilters reads the event filter table
+func (c *Client) GetPEFEventFilters() ([]*PEFEventFilter, error) {
+	count, err := c.pefCount(PEFParamEventFilterCount)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := make([]*PEFEventFilter, count)
+	for i := range filters {
+		filters[i] = &PEFEventFilter{ID: uint8(i + 1)}
+		if err = c.GetPEFConfig(filters[i]); err != nil {
+			return nil, err
+		}
+	}
+	return filters, nil
+}
+
+// AddPEFEventFilter writes the filter to the first disabled, software configurable
+// entry of the event filter table, setting the filter ID to the entry number
+func (c *Client) AddPEFEventFilter(filter *PEFEventFilter) error {
+	filters, err := c.GetPEFEventFilters()
+	if err != nil {
+		return err
+	}
+
+	for _, f := range filters {
+		if !f.Enabled && f.Type == PEFFilterSoftware {
+			filter.ID = f.ID
+			return c.SetPEFConfig(filter)
+		}
+	}
+	return ErrPEFTableFull
+}
+
+// GetPEFAlertPolicies reads the alert policy table
+func (c *Client) GetPEFAlertPolicies() ([]*PEFAlertPolicy, error) {
+	count, err := c.pefCount(PEFParamAlertPolicyCount)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make([]*PEFAlertPolicy, count)
+	for i := range policies {
+		policies[i] = &PEFAlertPolicy{ID: uint8(i + 1)}
+		if err = c.GetPEFConfig(policies[i]); err != nil {
+			return nil, err
+		}
+	}
+	return policies, nil
+}
+
+// GetPEFAlertString reads the alert string with the given selector, 0 being the volatile string
+func (c *Client) GetPEFAlertString(selector uint8) (string, error) {
+	var text []byte
+	for block := uint8(1); ; block++ {
+		res, err := c.GetPEFParam(PEFParamAlertString, selector, block)
+		if err != nil {
+			return "", err
+		}
+		if len(res.Data) < 2 {
+			return "", ErrShortPacket
+		}
+		data := res.Data[2:]
+		for i, b := range data {
+			if b == 0 {
+				return string(append(text, data[:i]...)), nil
+			}
+		}
+		text = append(text, data...)
+		if len(data) < pefAlertStringBlockSize {
+			return string(text), nil
+		}
+	}
+}
+
+// SetPEFAlertString writes the alert string with the given selector, in blocks of 16 bytes
+func (c *Client) SetPEFAlertString(selector uint8, text string) error {
+	data := append([]byte(text), 0)
+	if selector > 0x7f || len(data) > 0xff*pefAlertStringBlockSize {
+		return ErrPEFParamValue
+	}
+
+	var params []configParamData
+	for block := 0; block*pefAlertStringBlockSize < len(data); block++ {
+		end := (block + 1) * pefAlertStringBlockSize
+		if end > len(data) {
+			end = len(data)
+		}
+		params = append(params, configParamData{
+			PEFParamAlertString,
+			append([]byte{selector, uint8(block + 1)}, data[block*pefAlertStringBlockSize:end]...),
+		})
+	}
+	return c.setPEFParams(params)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPEF(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+	err = client.Open()
+	assert.NoError(t, err)
+
+	caps, err := client.GetPEFCapabilities()
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(8), caps.EventFilterCount)
+	assert.True(t, caps.ActionSupport&PEFActionAlert != 0)
+
+	countdown, err := client.ArmPEFPostponeTimer(30)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(30), countdown)
+	countdown, err = client.ArmPEFPostponeTimer(PEFPostponeGet)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(30), countdown)
+
+	err = client.SetPEFConfig(&PEFControl{Enabled: true}, PEFActionControl(PEFActionAlert))
+	assert.NoError(t, err)
+	control := &PEFControl{}
+	err = client.GetPEFConfig(control)
+	assert.NoError(t, err)
+	assert.Equal(t, &PEFControl{Enabled: true}, control)
+
+	// alert on fan failures through policy 1
+	fan := &PEFEventFilter{
+		Enabled:      true,
+		Action:       PEFActionAlert,
+		PolicyNumber: 1,
+		Severity:     PEFSeverityCritical,
+		GeneratorID:  0xffff,
+		SensorType:   SDR_SENSOR_TYPECODES_FAN,
+		SensorNumber: PEFMatchAny,
+		EventTrigger: SENSOR_READTYPE_THREADHOLD,
+		OffsetMask:   0x0014,
+	}
+	err = client.AddPEFEventFilter(fan)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(2), fan.ID)
+
+	filters, err := client.GetPEFEventFilters()
+	assert.NoError(t, err)
+	assert.Len(t, filters, 8)
+	assert.Equal(t, uint8(PEFFilterManufacturer), filters[0].Type)
+	assert.Equal(t, fan, filters[1])
+	assert.False(t, filters[2].Enabled)
+
+	// disable the filter through its data 1 byte
+	err = client.SetPEFParam(PEFParamEventFilterData1, fan.ID, 0x00)
+	assert.NoError(t, err)
+	filter := &PEFEventFilter{ID: fan.ID}
+	err = client.GetPEFConfig(filter)
+	assert.NoError(t, err)
+	assert.False(t, filter.Enabled)
+	assert.Equal(t, fan.SensorType, filter.SensorType)
+
+	policy := &PEFAlertPolicy{
+		ID:           1,
+		PolicyNumber: 1,
+		Enabled:      true,
+		Policy:       PEFPolicyAlways,
+		Channel:      1,
+		Destination:  1,
+	}
+	err = client.SetPEFConfig(policy)
+	assert.NoError(t, err)
+	policies, err := client.GetPEFAlertPolicies()
+	assert.NoError(t, err)
+	assert.Len(t, policies, 8)
+	assert.Equal(t, policy, policies[0])
+
+	text := "Fan failure on " + strings.Repeat("rack 42 ", 4)
+	err = client.SetPEFAlertString(1, text)
+	assert.NoError(t, err)
+	got, err := client.GetPEFAlertString(1)
+	assert.NoError(t, err)
+	assert.Equal(t, text, got)
+	err = client.SetPEFAlertString(1, "short")
+	assert.NoError(t, err)
+	got, err = client.GetPEFAlertString(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "short", got)
+
+	err = client.SetPEFParam(PEFParamEventFilterCount, 16)
+	assert.Equal(t, ErrPEFParamReadOnly, err)
+	_, err = client.GetPEFParam(0x60, 0, 0)
+	assert.Equal(t, ErrPEFParamNotSupported, err)
+	err = client.GetPEFConfig(&PEFEventFilter{ID: 9})
+	assert.Equal(t, ErrParamRange, err)
+
+	// fill the table, the disabled entry 2 included
+	for i := 0; i < 7; i++ {
+		f := *fan
+		err = client.AddPEFEventFilter(&f)
+		assert.NoError(t, err)
+	}
+	f := *fan
+	err = client.AddPEFEventFilter(&f)
+	assert.Equal(t, ErrPEFTableFull, err)
+
+	client.Close()
+	s.Stop()
+}
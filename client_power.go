@@ -0,0 +1,282 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrPowerTimeout = errors.New("timeout waiting for the chassis power state")
+)
+
+// PowerOptions tunes the power operations, zero values take the defaults
+type PowerOptions struct {
+	// Timeout bounds the whole operation, defaults to 5 minutes
+	Timeout time.Duration
+	// PollInterval is the delay between Chassis Status polls, defaults to 1 second
+	PollInterval time.Duration
+	// SoftOffGrace is how long SoftOff waits for the OS to shut down
+	// before escalating to a hard power down, defaults to 2 minutes
+	SoftOffGrace time.Duration
+}
+
+func (o *PowerOptions) withDefaults() PowerOptions {
+	opts := PowerOptions{}
+	if o != nil {
+		opts = *o
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 5 * time.Minute
+	}
+	if opts.PollInterval == 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.SoftOffGrace == 0 {
+		opts.SoftOffGrace = 2 * time.Minute
+	}
+	return opts
+}
+
+// PowerTransition is a change of the system power observed by polling Chassis Status
+type PowerTransition struct {
+	On bool
+	At time.Time
+}
+
+// PowerResult reports what a power operation did and observed
+type PowerResult struct {
+	InitialOn   bool             // power state before the operation
+	FinalOn     bool             // power state last observed
+	Controls    []ChassisControl // chassis controls sent, in order
+	Transitions []PowerTransition
+	Escalated   bool // ACPI soft-off was escalated to a hard power down
+}
+
+// powerOperation polls the chassis power state on behalf of a power operation
+type powerOperation struct {
+	c      *Client
+	parent context.Context
+	ctx    context.Context // parent bounded by PowerOptions.Timeout
+	cancel context.CancelFunc
+	opts   PowerOptions
+	res    *PowerResult
+	event  uint8 // LastPowerEvent before the operation
+}
+
+// powerOperation starts a power operation, the caller must call cancel once done
+func (c *Client) powerOperation(ctx context.Context, opts *PowerOptions) (*powerOperation, error) {
+	p := &powerOperation{
+		c:      c,
+		parent: ctx,
+		opts:   opts.withDefaults(),
+		res:    &PowerResult{},
+	}
+	// the timeout bounds the whole operation, the initial Chassis Status included
+	p.ctx, p.cancel = context.WithTimeout(ctx, p.opts.Timeout)
+	if err := ctx.Err(); err != nil {
+		p.cancel()
+		return nil, err
+	}
+	status, err := c.ChassisStatus()
+	if err != nil {
+		p.cancel()
+		return nil, err
+	}
+	p.res.InitialOn = status.IsSystemPowerOn()
+	p.res.FinalOn = p.res.InitialOn
+	p.event = status.LastPowerEvent
+	return p, nil
+}
+
+func (p *powerOperation) control(ctl ChassisControl) error {
+	p.res.Controls = append(p.res.Controls, ctl)
+	return p.c.Control(ctl)
+}
+
+// poll records the current power state
+func (p *powerOperation) poll() (*ChassisStatusResponse, error) {
+	status, err := p.c.ChassisStatus()
+	if err != nil {
+		return nil, err
+	}
+	if now := status.IsSystemPowerOn(); now != p.res.FinalOn {
+		p.res.Transitions = append(p.res.Transitions, PowerTransition{On: now, At: time.Now()})
+		p.res.FinalOn = now
+	}
+	return status, nil
+}
+
+// wait polls until the power state matches on, giving up after limit if not zero
+func (p *powerOperation) wait(on bool, limit time.Duration) error {
+	return p.waitFor(limit, func(*ChassisStatusResponse) bool {
+		return p.res.FinalOn == on
+	})
+}
+
+// waitFor polls until done returns true, giving up after limit if not zero
+func (p *powerOperation) waitFor(limit time.Duration, done func(*ChassisStatusResponse) bool) error {
+	var expired <-chan time.Time
+	if limit > 0 {
+		timer := time.NewTimer(limit)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	for {
+		status, err := p.poll()
+		if err != nil {
+			return err
+		}
+		if done(status) {
+			return nil
+		}
+
+		select {
+		case <-p.ctx.Done():
+			if err := p.parent.Err(); err != nil {
+				return err
+			}
+			return ErrPowerTimeout
+		case <-expired:
+			return ErrPowerTimeout
+		case <-time.After(p.opts.PollInterval):
+		}
+	}
+}
+
+// PowerOn powers the system on and waits until the power is on,
+// nothing is sent if the power is already on
+func (c *Client) PowerOn(ctx context.Context, opts *PowerOptions) (*PowerResult, error) {
+	p, err := c.powerOperation(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer p.cancel()
+
+	if p.res.InitialOn {
+		return p.res, nil
+	}
+	return p.res, p.powerOn()
+}
+
+func (p *powerOperation) powerOn() error {
+	if err := p.control(ControlPowerUp); err != nil {
+		return err
+	}
+	return p.wait(true, 0)
+}
+
+// PowerOff powers the system down without waiting for the OS to shut down and waits
+// until the power is off, nothing is sent if the power is already off
+func (c *Client) PowerOff(ctx context.Context, opts *PowerOptions) (*PowerResult, error) {
+	p, err := c.powerOperation(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer p.cancel()
+
+	if !p.res.InitialOn {
+		return p.res, nil
+	}
+	return p.res, p.powerOff()
+}
+
+func (p *powerOperation) powerOff() error {
+	if err := p.control(ControlPowerDown); err != nil {
+		return err
+	}
+	return p.wait(false, 0)
+}
+
+// SoftOff asks the OS to shut down through ACPI and waits until the power is off,
+// escalating to a hard power down once PowerOptions.SoftOffGrace has elapsed
+func (c *Client) SoftOff(ctx context.Context, opts *PowerOptions) (*PowerResult, error) {
+	p, err := c.powerOperation(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer p.cancel()
+
+	if !p.res.InitialOn {
+		return p.res, nil
+	}
+
+	if err := p.control(ControlPowerAcpiSoft); err != nil {
+		return p.res, err
+	}
+	err = p.wait(false, p.opts.SoftOffGrace)
+	if err != ErrPowerTimeout || p.ctx.Err() != nil {
+		return p.res, err
+	}
+
+	p.res.Escalated = true
+	return p.res, p.powerOff()
+}
+
+// PowerCycle powers the system off then on again and waits until the power is back on,
+// a system that is off is only powered on. The cycle is complete once the power is seen
+// off then on, or on with either a LastPowerEvent that changed or the chassis control
+// restart cause, as a fast cycle may fall between two polls
+func (c *Client) PowerCycle(ctx context.Context, opts *PowerOptions) (*PowerResult, error) {
+	p, err := c.powerOperation(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer p.cancel()
+
+	if !p.res.InitialOn {
+		return p.res, p.powerOn()
+	}
+
+	if err := p.control(ControlPowerCycle); err != nil {
+		return p.res, err
+	}
+	off := false
+	return p.res, p.waitFor(0, func(status *ChassisStatusResponse) bool {
+		if !p.res.FinalOn {
+			off = true
+			return false
+		}
+		if off || status.LastPowerEvent != p.event {
+			return true
+		}
+		// the last power on may already have been an IPMI command,
+		// Get System Restart Cause is optional so errors are not fatal
+		cause, err := p.c.SystemRestartCause()
+		return err == nil && cause.Cause == RestartCauseChassisControl
+	})
+}
+
+// PowerReset hard resets the system, a system that is off is only powered on.
+// The power stays on through a reset, which therefore cannot be verified by polling
+// the power state: PowerReset returns once the BMC accepted the control
+func (c *Client) PowerReset(ctx context.Context, opts *PowerOptions) (*PowerResult, error) {
+	p, err := c.powerOperation(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer p.cancel()
+
+	if !p.res.InitialOn {
+		return p.res, p.powerOn()
+	}
+
+	return p.res, p.control(ControlPowerHardReset)
+}
@@ -2,17 +2,8 @@ package ipmi
 
 import (
 	"bytes"
-	"math"
 )
 
-type SdrSensorInfo struct {
-	SensorType string
-	BaseUnit   string
-	Value      float64
-	DeviceId   string
-	avail      bool
-}
-
 // RepositoryInfo get the Repository Info of the SDR
 func (c *Client) RepositoryInfo() (*SDRRepositoryInfoResponse, error) {
 	req := &Request{
@@ -30,78 +21,223 @@ func (c *Client) GetReserveSDRRepoForReserveId() (*ReserveRepositoryResponse, er
 		&ReserveSDRRepositoryRequest{},
 	}
 	res := &ReserveRepositoryResponse{}
-	return res, c.send(req, res)
+	return res, c.Send(req, res)
 
 }
+// SDRRepositoryRecord is a record read from the SDR repository along with its raw bytes
+type SDRRepositoryRecord struct {
+	SDRRecord
+	Raw []byte
+}
+
 func (c *Client) GetSensorList(reservationID uint16) ([]SdrSensorInfo, error) {
+	records, err := c.readSDRRepository(reservationID)
+	if err != nil {
+		return nil, err
+	}
+	return c.ReadSensors(records)
+}
+
+// ReadSDRRepository reserves the SDR repository and reads all of its records, without reading any sensor
+func (c *Client) ReadSDRRepository() ([]*SDRRepositoryRecord, error) {
+	reserve, err := c.GetReserveSDRRepoForReserveId()
+	if err != nil {
+		return nil, err
+	}
+	return c.readSDRRepository(reserve.ReservationId)
+}
+
+func (c *Client) readSDRRepository(reservationID uint16) ([]*SDRRepositoryRecord, error) {
+	reader := c.newSDRReader(reservationID)
 	var recordId uint16 = 0
-	var sdrSensorInfolist = make([]SdrSensorInfo, 0, 30)
-	for recordId < 0xffff {
-		sdrRecordAndValue, nId, err := c.GetSDR(reservationID, recordId)
-		if err == nil {
-			if fullSensor, ok1 := sdrRecordAndValue.SDRRecord.(*SDRFullSensor); ok1 {
-				if fullSensor.BaseUnit >= 0 && fullSensor.BaseUnit < uint8(len(sdrRecordValueBasicUnit)) &&
-					fullSensor.SensorType >= 0 && uint8(fullSensor.SensorType) < uint8(len(sdrRecordValueSensorType)) {
-					sdrSensorInfolist = append(sdrSensorInfolist, SdrSensorInfo{
-						sdrRecordValueSensorType[fullSensor.SensorType],
-						sdrRecordValueBasicUnit[fullSensor.BaseUnit],
-						sdrRecordAndValue.value,
-						fullSensor.deviceId,
-						sdrRecordAndValue.avail,
-					})
-				}
-			} else if compactSensor, ok2 := sdrRecordAndValue.SDRRecord.(*SDRCompactSensor); ok2 {
-				if compactSensor.BaseUnit >= 0 && compactSensor.BaseUnit < uint8(len(sdrRecordValueBasicUnit)) &&
-					compactSensor.SensorType >= 0 && uint8(compactSensor.SensorType) < uint8(len(sdrRecordValueSensorType)) {
-					sdrSensorInfolist = append(sdrSensorInfolist, SdrSensorInfo{
-						sdrRecordValueSensorType[compactSensor.SensorType],
-						sdrRecordValueBasicUnit[compactSensor.BaseUnit],
-						sdrRecordAndValue.value,
-						compactSensor.deviceId,
-						sdrRecordAndValue.avail,
-					})
+	var records = make([]*SDRRepositoryRecord, 0, 30)
+	var seen = map[uint16]bool{}
+	for recordId < 0xffff && !seen[recordId] {
+		seen[recordId] = true
+		record, nId, err := reader.read(recordId)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+		recordId = nId
+	}
+	return records, nil
+}
+
+// ReadSensors gets the reading of every full and compact sensor in records, compact records shared by
+// several sensors give one SdrSensorInfo per sensor. Thresholds are the ones of the SDR, use GetSensorThresholds
+// for the current ones.
+// A sensor whose reading is unavailable is returned as such, only transport errors are returned.
+func (c *Client) ReadSensors(records []*SDRRepositoryRecord) ([]SdrSensorInfo, error) {
+	var sdrSensorInfolist = make([]SdrSensorInfo, 0, len(records))
+	for _, record := range records {
+		for _, info := range NewSdrSensorInfos(record.SDRRecord) {
+			var value float64
+			var avail bool
+			reading := SensorReading{Unavailable: true}
+			res, err := c.GetSensorReading(info.SensorNumber)
+			if err == nil {
+				reading = NewSensorReading(res, info.ReadingType, info.SensorTypeCode)
+			}
+			if err == nil && !reading.Unavailable {
+				switch r := record.SDRRecord.(type) {
+				case *SDRFullSensor:
+					factors := r.Factors()
+					if r.IsNonLinear() && r.ReadingType == SENSOR_READTYPE_THREADHOLD {
+						factors, err = c.GetSensorReadingFactors(info.SensorNumber, reading.Raw)
+					}
+					if err == nil {
+						value, avail = calFullSensorFactorsValue(r, factors, reading.Raw)
+					}
+				case *SDRCompactSensor:
+					value, avail = calCompactSensorValue(r, reading.Raw)
 				}
 			}
+			if _, ok := err.(CompletionCode); err != nil && !ok {
+				return nil, err
+			}
+
+			info.Value, info.Reading, info.avail = value, reading, avail
+			sdrSensorInfolist = append(sdrSensorInfolist, info)
 		}
-		recordId = nId
 	}
 	return sdrSensorInfolist, nil
 }
 
-//Get SDR Command  33.12
-func (c *Client) GetSDR(reservationID uint16, recordID uint16) (sdr *sDRRecordAndValue, next uint16, err error) {
-	req_step1 := &Request{
-		NetworkFunctionStorge,
-		CommandGetSDR,
-		&GetSDRCommandRequest{
-			ReservationID:    reservationID,
-			RecordID:         recordID,
-			OffsetIntoRecord: 0,
-			ByteToRead:       5,
-		},
+const (
+	sdrHeaderSize = 5
+	// ByteToRead asking for the entire record
+	sdrReadEntireRecord = 0xff
+	// initial size of partial reads, shrunk while the BMC answers ErrRequestData
+	sdrReadChunkSize = 32
+	// times the repository is reserved again for a single record before giving up
+	sdrMaxReservations = 5
+)
+
+// sdrReader reads records from the SDR repository, the entire record at once if the BMC allows it,
+// in partial reads otherwise. The repository is reserved again when the reservation gets cancelled.
+type sdrReader struct {
+	c             *Client
+	reservationID uint16
+	chunk         uint8
+	entire        bool
+}
+
+func (c *Client) newSDRReader(reservationID uint16) *sdrReader {
+	return &sdrReader{
+		c:             c,
+		reservationID: reservationID,
+		chunk:         sdrReadChunkSize,
+		entire:        true,
 	}
-	recordKeyBody_Data := new(bytes.Buffer)
-	res_step1 := &GetSDRCommandResponse{}
-	c.Send(req_step1, res_step1)
-	readData_step1 := res_step1.ReadData
-	recordType := readData_step1[3]
-	lenToRead_step2 := readData_step1[4]
-	recordKeyBody_Data.Write(readData_step1)
-	req_step2 := &Request{
+}
+
+func (r *sdrReader) get(recordID uint16, offset uint8, count uint8) (*GetSDRCommandResponse, error) {
+	req := &Request{
 		NetworkFunctionStorge,
 		CommandGetSDR,
 		&GetSDRCommandRequest{
-			ReservationID:    reservationID,
+			ReservationID:    r.reservationID,
 			RecordID:         recordID,
-			OffsetIntoRecord: 5,
-			ByteToRead:       uint8(lenToRead_step2),
+			OffsetIntoRecord: offset,
+			ByteToRead:       count,
 		},
 	}
-	res_step2 := &GetSDRCommandResponse{}
-	c.Send(req_step2, res_step2)
-	recordKeyBody_Data.Write(res_step2.ReadData)
-	sdrRecordAndValue, err := c.CalSdrRecordValue(recordType, recordKeyBody_Data)
-	return sdrRecordAndValue, res_step2.NextRecordID, err
+	res := &GetSDRCommandResponse{}
+	return res, r.c.Send(req, res)
+}
+
+// read reads a whole record, reserving the repository again if needed
+func (r *sdrReader) read(recordID uint16) (*SDRRepositoryRecord, uint16, error) {
+	for reservations := 0; ; reservations++ {
+		raw, next, err := r.readRaw(recordID)
+		if err == ErrInvalidResv && reservations < sdrMaxReservations {
+			reserve, err := r.c.GetReserveSDRRepoForReserveId()
+			if err != nil {
+				return nil, 0, err
+			}
+			r.reservationID = reserve.ReservationId
+			continue
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+
+		record, err := UnmarshalSDRRecord(raw)
+		if err != nil {
+			return nil, 0, err
+		}
+		return &SDRRepositoryRecord{record, raw}, next, nil
+	}
+}
+
+func (r *sdrReader) readRaw(recordID uint16) ([]byte, uint16, error) {
+	if r.entire {
+		res, err := r.get(recordID, 0, sdrReadEntireRecord)
+		switch err {
+		case nil:
+			data := res.ReadData
+			if len(data) >= sdrHeaderSize && len(data) >= sdrHeaderSize+int(data[4]) {
+				return data[:sdrHeaderSize+int(data[4])], res.NextRecordID, nil
+			}
+			// got less than the entire record, stick to partial reads
+			r.entire = false
+		case ErrRequestData, ErrLongPacket, ErrDataTruncated, ErrParamRange, ErrInvalidPacket:
+			r.entire = false
+		default:
+			return nil, 0, err
+		}
+	}
+
+	// the header first, which gives the length of the rest
+	res, err := r.get(recordID, 0, sdrHeaderSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(res.ReadData) < sdrHeaderSize {
+		return nil, 0, ErrSDRRecordTooShort
+	}
+	next := res.NextRecordID
+	length := sdrHeaderSize + int(res.ReadData[4])
+	data := make([]byte, sdrHeaderSize, length)
+	copy(data, res.ReadData)
+
+	for len(data) < length {
+		count := r.chunk
+		if remain := length - len(data); remain < int(count) {
+			count = uint8(remain)
+		}
+		res, err := r.get(recordID, uint8(len(data)), count)
+		if err == ErrRequestData && r.chunk > 1 {
+			r.chunk = r.chunk * 3 / 4
+			if r.chunk == 0 {
+				r.chunk = 1
+			}
+			continue
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(res.ReadData) == 0 {
+			return nil, 0, ErrSDRRecordTooShort
+		}
+		if len(res.ReadData) > int(count) {
+			res.ReadData = res.ReadData[:count]
+		}
+		data = append(data, res.ReadData...)
+	}
+
+	return data, next, nil
+}
+
+//Get SDR Command  33.12
+func (c *Client) GetSDR(reservationID uint16, recordID uint16) (sdr *sDRRecordAndValue, next uint16, err error) {
+	record, next, err := c.newSDRReader(reservationID).read(recordID)
+	if err != nil {
+		return nil, next, err
+	}
+	sdrRecordAndValue, err := c.CalSdrRecordValue(uint8(record.RecordType()), bytes.NewBuffer(record.Raw))
+	return sdrRecordAndValue, next, err
 }
 func (c *Client) CalSdrRecordValue(recordType uint8, recordKeyBody_Data *bytes.Buffer) (*sDRRecordAndValue, error) {
 	var sdrRecordAndValue = &sDRRecordAndValue{}
@@ -137,34 +273,32 @@ func (c *Client) CalSdrRecordValue(recordType uint8, recordKeyBody_Data *bytes.B
 		}
 		return sdrRecordAndValue, err
 	}
-	return nil, nil
+	// records without a reading, unknown types are kept as *SDRRawRecord
+	record, err := UnmarshalSDRRecord(recordKeyBody_Data.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	sdrRecordAndValue.SDRRecord = record
+	return sdrRecordAndValue, nil
 }
 func calFullSensorValue(sdrRecord SDRRecord, sensorReading uint8) (float64, bool) {
-	if fullSensor, err := sdrRecord.(*SDRFullSensor); err {
-		var result float64 = 0.0
-		var avail bool
-		//threshold type
-		if fullSensor.ReadingType == SENSOR_READTYPE_THREADHOLD {
-			// has analog value
-			if fullSensor.Unit&0xc0 != 0xc0 {
-				m, b, bexp, rexp := fullSensor.GetMBExp()
-				switch (fullSensor.Unit & 0xc0) >> 6 {
-				case 0:
-					result = (float64(m)*float64(sensorReading) + float64(b)*math.Pow(10, float64(bexp))) * math.Pow(10, float64(rexp))
-				case 1:
-				case 2:
-					result = (float64(int8(m)*int8(sensorReading)) + float64(b)*math.Pow(10, float64(rexp))) * math.Pow(10, float64(bexp))
-				}
-				avail = true
-			} else {
-				avail = false
-			}
-		}
-		return result, avail
+	if fullSensor, ok := sdrRecord.(*SDRFullSensor); ok {
+		return calFullSensorFactorsValue(fullSensor, fullSensor.Factors(), sensorReading)
 	}
-
 	return float64(0), false
 }
+
+// calFullSensorFactorsValue converts the reading of a threshold full sensor with the given factors
+func calFullSensorFactorsValue(fullSensor *SDRFullSensor, factors *SensorFactors, sensorReading uint8) (float64, bool) {
+	if fullSensor.ReadingType != SENSOR_READTYPE_THREADHOLD {
+		return float64(0), false
+	}
+	value, err := factors.ConvertReading(fullSensor.AnalogDataFormat(), fullSensor.Linearization, sensorReading)
+	if err != nil {
+		return float64(0), false
+	}
+	return value, true
+}
 func calCompactSensorValue(sdrRecord SDRRecord, sensorReading uint8) (float64, bool) {
 	var value float64 = 0.0
 	var avail bool = false
@@ -202,8 +336,27 @@ func calCompactSensorValue(sdrRecord SDRRecord, sensorReading uint8) (float64, b
 	return value, avail
 }
 
+//Get Sensor Reading Factors  35.5
+// GetSensorReadingFactors gets the conversion factors of a non-linear sensor for the given reading
+func (c *Client) GetSensorReadingFactors(sensorNum uint8, reading uint8) (*SensorFactors, error) {
+	req := &Request{
+		NetworkFunctionSensorEvent,
+		CommandGetSensorReadingFactors,
+		&GetSensorReadingFactorsRequest{
+			SensorNumber: sensorNum,
+			ReadingByte:  reading,
+		},
+	}
+	res := &GetSensorReadingFactorsResponse{}
+	if err := c.Send(req, res); err != nil {
+		return nil, err
+	}
+	return &res.SensorFactors, nil
+}
+
 //Get Sensor Reading  35.14
-func (c *Client) getSensorReading(sensorNum uint8) (sensorReading uint8, err error) {
+// GetSensorReading gets the reading and the threshold status or asserted states of a sensor
+func (c *Client) GetSensorReading(sensorNum uint8) (*GetSensorReadingResponse, error) {
 	req := &Request{
 		NetworkFunctionSensorEvent,
 		CommandGetSensorReading,
@@ -212,13 +365,19 @@ func (c *Client) getSensorReading(sensorNum uint8) (sensorReading uint8, err err
 		},
 	}
 	res := &GetSensorReadingResponse{}
-	c.Send(req, res)
-	if res == nil {
-		return uint8(0), ErrNotFoundTheSensorNum
+	if err := c.Send(req, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *Client) getSensorReading(sensorNum uint8) (sensorReading uint8, err error) {
+	res, err := c.GetSensorReading(sensorNum)
+	if err != nil {
+		return uint8(0), err
 	}
-	if (res.ReadingAvail & 0x20) == 0 {
-		readValue := res.SensorReading
-		return readValue, nil
+	if !res.ReadingUnavailable() {
+		return res.SensorReading, nil
 	}
 	return uint8(0), ErrSensorReadUnavail
 }
@@ -1,6 +1,7 @@
 package ipmi
 
 import (
+	"bytes"
 	"net"
 	"testing"
 
@@ -100,7 +101,7 @@ func TestGetSDR(t *testing.T) {
 		if err := m.Request(request); err != nil {
 			return err
 		}
-		response.ReadData = data1[request.OffsetIntoRecord : request.OffsetIntoRecord+request.ByteToRead]
+		response.ReadData, _ = sdrRecordSlice(data1, request)
 		return response
 	})
 	sdrRecordAndValue1, nextRecordId1, err1 := client.GetSDR(reserve.ReservationId, 0)
@@ -129,7 +130,7 @@ func TestGetSDR(t *testing.T) {
 		if err := m.Request(request); err != nil {
 			return err
 		}
-		response.ReadData = data2[request.OffsetIntoRecord : request.OffsetIntoRecord+request.ByteToRead]
+		response.ReadData, _ = sdrRecordSlice(data2, request)
 		return response
 	})
 	sdrRecordAndValue2, nextRecordId2, err2 := client.GetSDR(reserve.ReservationId, 0)
@@ -247,7 +248,7 @@ func TestGetSensorList(t *testing.T) {
 		if err := m.Request(request); err != nil {
 			return err
 		}
-		response.ReadData = data1[request.OffsetIntoRecord : request.OffsetIntoRecord+request.ByteToRead]
+		response.ReadData, _ = sdrRecordSlice(data1, request)
 		return response
 	})
 
@@ -274,3 +275,325 @@ func TestGetSensorList(t *testing.T) {
 	}
 
 }
+
+func TestCalSdrRecordValueNonSensor(t *testing.T) {
+	client := &Client{}
+
+	r1, _ := NewSDREventOnlySensor(3, "CPU0 Status")
+	data1, _ := r1.MarshalBinary()
+	sdr1, err := client.CalSdrRecordValue(SDR_RECORD_TYPE_EVENTONLY_SENSOR, bytes.NewBuffer(data1))
+	assert.NoError(t, err)
+	assert.Equal(t, r1, sdr1.SDRRecord)
+
+	data2 := []byte{0x04, 0x00, 0x51, 0xd0, 0x02, 0x12, 0x34}
+	sdr2, err := client.CalSdrRecordValue(0xd0, bytes.NewBuffer(data2))
+	assert.NoError(t, err)
+	assert.Equal(t, &SDRRawRecord{SDRRecordHeader{4, 0x51, 0xd0}, []byte{0x12, 0x34}}, sdr2.SDRRecord)
+}
+
+func TestReadSDRRepository(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	getSensorReading := s.getSensorReading
+	readings := 0
+	s.SetHandler(NetworkFunctionSensorEvent, CommandGetSensorReading, func(m *Message) Response {
+		readings++
+		return getSensorReading(m)
+	})
+
+	records, err := client.ReadSDRRepository()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, readings)
+	assert.Equal(t, 2, len(records))
+	if len(records) == 2 {
+		assert.Equal(t, "Ambient Temp", records[0].DeviceId())
+		assert.Equal(t, "CPU1 DTS", records[1].DeviceId())
+		data, _ := records[1].SDRRecord.(*SDRFullSensor).MarshalBinary()
+		assert.Equal(t, data, records[1].Raw)
+	}
+
+	// one sensor reading unavailable, the other one missing
+	unavail, _ := NewSDRFullSensor(3, "PCH Temp")
+	unavail.SensorNumber = 0x30
+	unavail.ReadingType = SENSOR_READTYPE_THREADHOLD
+	unavail.SetMBExp(1, 0, 0, 0)
+	data, _ := unavail.MarshalBinary()
+	records = append(records, &SDRRepositoryRecord{unavail, data})
+	s.SetHandler(NetworkFunctionSensorEvent, CommandGetSensorReading, func(m *Message) Response {
+		readings++
+		request := &GetSensorReadingRequest{}
+		if err := m.Request(request); err != nil {
+			return err
+		}
+		switch request.SensorNumber {
+		case 0x04:
+			return &GetSensorReadingResponse{CompletionCode: CommandCompleted, SensorReading: 0x29, ReadingAvail: 0xc0}
+		case 0x30:
+			return &GetSensorReadingResponse{CompletionCode: CommandCompleted, ReadingAvail: 0x20}
+		}
+		return ErrNoObj
+	})
+
+	sensors, err := client.ReadSensors(records)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, readings)
+	assert.Equal(t, 3, len(sensors))
+	if len(sensors) == 3 {
+		assert.Equal(t, float64(2583), sensors[0].Value)
+		assert.True(t, sensors[0].avail)
+		assert.False(t, sensors[1].avail)
+		assert.Equal(t, "PCH Temp", sensors[2].DeviceId)
+		assert.False(t, sensors[2].avail)
+	}
+
+	err = client.Close()
+	assert.NoError(t, err)
+	s.Stop()
+}
+
+func TestGetSDRErrors(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	s.SetHandler(NetworkFunctionStorge, CommandGetSDR, func(*Message) Response {
+		return ErrNoObj
+	})
+	_, _, err = client.GetSDR(0, 0)
+	assert.Equal(t, ErrNoObj, err)
+
+	_, err = client.GetSensorList(0)
+	assert.Equal(t, ErrNoObj, err)
+
+	s.SetHandler(NetworkFunctionStorge, CommandGetSDR, func(*Message) Response {
+		return &GetSDRCommandResponse{
+			CompletionCode: CommandCompleted,
+			NextRecordID:   0xffff,
+			ReadData:       []byte{0x01, 0x00, 0x51},
+		}
+	})
+	_, _, err = client.GetSDR(0, 0)
+	assert.Equal(t, ErrSDRRecordTooShort, err)
+
+	err = client.Close()
+	assert.NoError(t, err)
+	s.Stop()
+}
+
+func TestReadSDRRepositoryPartialReads(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	getSDR := s.getSDR
+	var requests []*GetSDRCommandRequest
+	s.SetHandler(NetworkFunctionStorge, CommandGetSDR, func(m *Message) Response {
+		request := &GetSDRCommandRequest{}
+		if err := m.Request(request); err != nil {
+			return err
+		}
+		requests = append(requests, request)
+		return getSDR(m)
+	})
+
+	// entire record reads
+	records, err := client.ReadSDRRepository()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(records))
+	assert.Equal(t, 2, len(requests))
+	for _, request := range requests {
+		assert.Equal(t, uint8(sdrReadEntireRecord), request.ByteToRead)
+	}
+
+	// the BMC caps reads at 16 bytes
+	s.sdrMaxRead = 16
+	requests = nil
+	capped, err := client.ReadSDRRepository()
+	assert.NoError(t, err)
+	assert.Equal(t, records[0].Raw, capped[0].Raw)
+	assert.Equal(t, records[1].Raw, capped[1].Raw)
+	for _, request := range requests[5:] {
+		assert.True(t, request.ByteToRead <= 16)
+	}
+
+	err = client.Close()
+	assert.NoError(t, err)
+	s.Stop()
+}
+
+func TestReadSDRRepositoryReservationCancelled(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	reserveRepository := s.reserveRepository
+	reservations := 0
+	s.SetHandler(NetworkFunctionStorge, CommandGetReserveSDRRepo, func(m *Message) Response {
+		reservations++
+		return reserveRepository(m)
+	})
+
+	getSDR := s.getSDR
+	cancel := 1
+	s.SetHandler(NetworkFunctionStorge, CommandGetSDR, func(m *Message) Response {
+		request := &GetSDRCommandRequest{}
+		if err := m.Request(request); err != nil {
+			return err
+		}
+		// the reservation gets cancelled while reading the second record
+		if request.RecordID == 2 && cancel > 0 {
+			cancel--
+			return ErrInvalidResv
+		}
+		return getSDR(m)
+	})
+
+	records, err := client.ReadSDRRepository()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(records))
+	assert.Equal(t, 2, reservations)
+
+	// cancelled over and over
+	cancel = 1000
+	reservations = 0
+	_, err = client.ReadSDRRepository()
+	assert.Equal(t, ErrInvalidResv, err)
+	assert.Equal(t, 1+sdrMaxReservations, reservations)
+
+	err = client.Close()
+	assert.NoError(t, err)
+	s.Stop()
+}
+
+func TestReadSensorsNonLinear(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	// the simulated Ambient Temp reads 0x29
+	var factorsRequest *GetSensorReadingFactorsRequest
+	s.SetHandler(NetworkFunctionSensorEvent, CommandGetSensorReadingFactors, func(m *Message) Response {
+		factorsRequest = &GetSensorReadingFactorsRequest{}
+		if err := m.Request(factorsRequest); err != nil {
+			return err
+		}
+		response := &GetSensorReadingFactorsResponse{}
+		response.SetMBExp(100, 0, 0, 0)
+		return response
+	})
+
+	r, _ := NewSDRFullSensor(1, "Ambient Temp")
+	r.SensorNumber = 0x04
+	r.ReadingType = SENSOR_READTYPE_THREADHOLD
+	r.Linearization = SDR_LINEARIZATION_NONLINEAR_L
+	r.SetMBExp(1, 0, 0, 0)
+
+	list, err := client.ReadSensors([]*SDRRepositoryRecord{{SDRRecord: r}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(list))
+	assert.Equal(t, float64(4100), list[0].Value)
+	assert.True(t, list[0].avail)
+	assert.Equal(t, uint8(0x04), factorsRequest.SensorNumber)
+	assert.Equal(t, uint8(0x29), factorsRequest.ReadingByte)
+
+	// the factors can't be had
+	s.SetHandler(NetworkFunctionSensorEvent, CommandGetSensorReadingFactors, func(m *Message) Response {
+		return ErrNoObj
+	})
+	list, err = client.ReadSensors([]*SDRRepositoryRecord{{SDRRecord: r}})
+	assert.NoError(t, err)
+	assert.False(t, list[0].avail)
+
+	err = client.Close()
+	assert.NoError(t, err)
+	s.Stop()
+}
+
+func TestReadSensorsStatus(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	getSensorReading := s.getSensorReading
+	s.SetHandler(NetworkFunctionSensorEvent, CommandGetSensorReading, func(m *Message) Response {
+		request := &GetSensorReadingRequest{}
+		if err := m.Request(request); err != nil {
+			return err
+		}
+		switch request.SensorNumber {
+		case 0x30: // failed PSU
+			return &GetSensorReadingResponse{ReadingAvail: 0xc0, ForThresDiscreStat: 0x03}
+		case 0x31: // healthy PSU
+			return &GetSensorReadingResponse{ReadingAvail: 0xc0, ForThresDiscreStat: 0x01}
+		case 0x32: // not scanned
+			return &GetSensorReadingResponse{ReadingAvail: 0x20}
+		}
+		return getSensorReading(m)
+	})
+
+	ambient, _ := NewSDRFullSensor(1, "Ambient Temp")
+	ambient.SensorNumber = 0x04
+	ambient.ReadingType = SENSOR_READTYPE_THREADHOLD
+	ambient.SetMBExp(63, 0, 0, 0)
+	records := []*SDRRepositoryRecord{{SDRRecord: ambient}}
+	for i, name := range []string{"PS1 Status", "PS2 Status", "PS3 Status"} {
+		psu, _ := NewSDRCompactSensor(uint16(i+2), name)
+		psu.SensorNumber = uint8(0x30 + i)
+		psu.SensorType = 0x08
+		psu.ReadingType = SENSOR_READTYPE_SENSORSPECIF
+		records = append(records, &SDRRepositoryRecord{SDRRecord: psu})
+	}
+
+	list, err := client.ReadSensors(records)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(list))
+	assert.Equal(t, float64(2583), list[0].Value)
+	assert.Equal(t, "ok", list[0].Reading.String())
+	assert.Equal(t, []string{"Presence detected", "Failure detected"}, list[1].Reading.StateNames())
+	assert.Equal(t, []string{"Presence detected"}, list[2].Reading.StateNames())
+	assert.True(t, list[3].Reading.Unavailable)
+	assert.True(t, list[3].Reading.ScanningDisabled)
+	assert.False(t, list[3].avail)
+
+	err = client.Close()
+	assert.NoError(t, err)
+	s.Stop()
+}
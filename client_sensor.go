@@ -0,0 +1,218 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import "errors"
+
+var (
+	ErrThresholdNotSettable  = errors.New("sensor threshold is not settable")
+	ErrHysteresisNotReadable = errors.New("sensor hysteresis is not readable")
+	ErrHysteresisNotSettable = errors.New("sensor hysteresis is not settable")
+)
+
+// SensorThresholds are the thresholds of a sensor in the sensor units, Mask tells which ones are present
+type SensorThresholds struct {
+	Mask                ThresholdStatus
+	LowerNonCritical    float64
+	LowerCritical       float64
+	LowerNonRecoverable float64
+	UpperNonCritical    float64
+	UpperCritical       float64
+	UpperNonRecoverable float64
+}
+
+// thresholds in the order of the ThresholdStatus bits
+func (t *SensorThresholds) values() []*float64 {
+	return []*float64{
+		&t.LowerNonCritical, &t.LowerCritical, &t.LowerNonRecoverable,
+		&t.UpperNonCritical, &t.UpperCritical, &t.UpperNonRecoverable,
+	}
+}
+
+// thresholds in the order of the ThresholdStatus bits
+func (t *SensorThresholdValues) values() []*uint8 {
+	return []*uint8{
+		&t.LowerNonCritical, &t.LowerCritical, &t.LowerNonRecoverable,
+		&t.UpperNonCritical, &t.UpperCritical, &t.UpperNonRecoverable,
+	}
+}
+
+// SensorEventEnable tells which events a sensor generates
+type SensorEventEnable struct {
+	EventMessages   bool
+	Scanning        bool
+	AssertionMask   uint16
+	DeassertionMask uint16
+}
+
+// GetSensorThresholds gets the readable thresholds of a threshold sensor, section 35.9
+func (c *Client) GetSensorThresholds(sensor *SDRFullSensor) (*SensorThresholds, error) {
+	req := &Request{
+		NetworkFunctionSensorEvent,
+		CommandGetSensorThresholds,
+		&GetSensorThresholdsRequest{
+			SensorNumber: sensor.SensorNumber,
+		},
+	}
+	res := &GetSensorThresholdsResponse{}
+	if err := c.Send(req, res); err != nil {
+		return nil, err
+	}
+
+	thresholds := &SensorThresholds{Mask: res.Readable & sensor.ReadableThresholds()}
+	raw := res.SensorThresholdValues.values()
+	for i, value := range thresholds.values() {
+		if thresholds.Mask&(1<<uint(i)) == 0 {
+			continue
+		}
+		v, err := sensor.ConvertReading(*raw[i])
+		if err != nil {
+			return nil, err
+		}
+		*value = v
+	}
+	return thresholds, nil
+}
+
+// SetSensorThresholds sets the thresholds in Mask of a threshold sensor, section 35.8
+func (c *Client) SetSensorThresholds(sensor *SDRFullSensor, thresholds *SensorThresholds) error {
+	if thresholds.Mask&^sensor.SettableThresholds() != 0 {
+		return ErrThresholdNotSettable
+	}
+
+	request := &SetSensorThresholdsRequest{
+		SensorNumber: sensor.SensorNumber,
+		Mask:         thresholds.Mask,
+	}
+	raw := request.SensorThresholdValues.values()
+	for i, value := range thresholds.values() {
+		if thresholds.Mask&(1<<uint(i)) == 0 {
+			continue
+		}
+		v, err := sensor.ConvertValue(*value)
+		if err != nil {
+			return err
+		}
+		*raw[i] = v
+	}
+
+	req := &Request{
+		NetworkFunctionSensorEvent,
+		CommandSetSensorThresholds,
+		request,
+	}
+	return c.Send(req, &SetSensorThresholdsResponse{})
+}
+
+// GetSensorHysteresis gets the positive and negative going hysteresis of a threshold sensor in its units, section 35.7
+func (c *Client) GetSensorHysteresis(sensor *SDRFullSensor) (positive float64, negative float64, err error) {
+	switch sensor.HysteresisAccess() {
+	case SENSOR_ACCESS_READABLE, SENSOR_ACCESS_SETTABLE:
+	default:
+		return 0, 0, ErrHysteresisNotReadable
+	}
+
+	req := &Request{
+		NetworkFunctionSensorEvent,
+		CommandGetSensorHysteresis,
+		&GetSensorHysteresisRequest{
+			SensorNumber: sensor.SensorNumber,
+			Reserved:     0xff,
+		},
+	}
+	res := &GetSensorHysteresisResponse{}
+	if err := c.Send(req, res); err != nil {
+		return 0, 0, err
+	}
+	factors := sensor.Factors()
+	return factors.ConvertHysteresis(res.PositiveHysteresis), factors.ConvertHysteresis(res.NegativeHysteresis), nil
+}
+
+// SetSensorHysteresis sets the positive and negative going hysteresis of a threshold sensor in its units, section 35.6
+func (c *Client) SetSensorHysteresis(sensor *SDRFullSensor, positive float64, negative float64) error {
+	if sensor.HysteresisAccess() != SENSOR_ACCESS_SETTABLE {
+		return ErrHysteresisNotSettable
+	}
+
+	factors := sensor.Factors()
+	request := &SetSensorHysteresisRequest{
+		SensorNumber: sensor.SensorNumber,
+		Reserved:     0xff,
+	}
+	var err error
+	if request.PositiveHysteresis, err = factors.HysteresisRaw(positive); err != nil {
+		return err
+	}
+	if request.NegativeHysteresis, err = factors.HysteresisRaw(negative); err != nil {
+		return err
+	}
+
+	req := &Request{
+		NetworkFunctionSensorEvent,
+		CommandSetSensorHysteresis,
+		request,
+	}
+	return c.Send(req, &SetSensorHysteresisResponse{})
+}
+
+// GetSensorEventEnable gets which events a sensor generates, section 35.11
+func (c *Client) GetSensorEventEnable(sensorNum uint8) (*SensorEventEnable, error) {
+	req := &Request{
+		NetworkFunctionSensorEvent,
+		CommandGetSensorEventEnable,
+		&GetSensorEventEnableRequest{
+			SensorNumber: sensorNum,
+		},
+	}
+	res := &GetSensorEventEnableResponse{}
+	if err := c.Send(req, res); err != nil {
+		return nil, err
+	}
+	return &SensorEventEnable{
+		EventMessages:   res.Flags&0x80 != 0,
+		Scanning:        res.Flags&0x40 != 0,
+		AssertionMask:   res.AssertionMask & 0x7fff,
+		DeassertionMask: res.DeassertionMask & 0x7fff,
+	}, nil
+}
+
+// SetSensorEventEnable sets which events a sensor generates, section 35.10.
+// The events in the masks are enabled and all the others disabled.
+func (c *Client) SetSensorEventEnable(sensorNum uint8, events *SensorEventEnable) error {
+	var flags uint8
+	if events.EventMessages {
+		flags |= 0x80
+	}
+	if events.Scanning {
+		flags |= 0x40
+	}
+
+	for _, request := range []*SetSensorEventEnableRequest{
+		{sensorNum, flags | SensorEventEnableSelected, events.AssertionMask & 0x7fff, events.DeassertionMask & 0x7fff},
+		{sensorNum, flags | SensorEventDisableSelected, ^events.AssertionMask & 0x7fff, ^events.DeassertionMask & 0x7fff},
+	} {
+		req := &Request{
+			NetworkFunctionSensorEvent,
+			CommandSetSensorEventEnable,
+			request,
+		}
+		if err := c.Send(req, &SetSensorEventEnableResponse{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
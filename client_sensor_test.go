@@ -0,0 +1,174 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSensorThresholds(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	records, err := client.ReadSDRRepository()
+	assert.NoError(t, err)
+	ambient := records[0].SDRRecord.(*SDRFullSensor)
+	cpu := records[1].SDRRecord.(*SDRFullSensor)
+
+	thresholds, err := client.GetSensorThresholds(ambient)
+	assert.NoError(t, err)
+	assert.Equal(t, &SensorThresholds{
+		Mask:                0x3f,
+		LowerNonRecoverable: 63,
+		LowerCritical:       315,
+		LowerNonCritical:    630,
+		UpperNonCritical:    3024,
+		UpperCritical:       3528,
+		UpperNonRecoverable: 4032,
+	}, thresholds)
+
+	res, err := client.GetSensorReading(ambient.SensorNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, ThresholdStatus(0), res.ThresholdStatus())
+
+	// the fan at 2583 RPM is now above its upper non-critical threshold
+	err = client.SetSensorThresholds(ambient, &SensorThresholds{
+		Mask:             ThresholdUpperNonCritical,
+		UpperNonCritical: 2520,
+	})
+	assert.NoError(t, err)
+	thresholds, err = client.GetSensorThresholds(ambient)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2520), thresholds.UpperNonCritical)
+	assert.Equal(t, float64(3528), thresholds.UpperCritical)
+	res, err = client.GetSensorReading(ambient.SensorNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, ThresholdUpperNonCritical, res.ThresholdStatus())
+
+	// only the lower thresholds are settable
+	lower := *ambient
+	lower.DiscreteReadingMask = 0x073f
+	err = client.SetSensorThresholds(&lower, &SensorThresholds{Mask: ThresholdUpperCritical})
+	assert.Equal(t, ErrThresholdNotSettable, err)
+
+	// the BMC checks the settable thresholds too
+	s.sensors[ambient.SensorNumber].settable = 0x07
+	err = client.SetSensorThresholds(ambient, &SensorThresholds{Mask: ThresholdUpperCritical})
+	assert.Equal(t, ErrInvalidPacket, err)
+
+	// the CPU sensor has no thresholds
+	_, err = client.GetSensorThresholds(cpu)
+	assert.Equal(t, ErrInvalidObjCommand, err)
+
+	err = client.Close()
+	assert.NoError(t, err)
+	s.Stop()
+}
+
+func TestSensorHysteresis(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	records, err := client.ReadSDRRepository()
+	assert.NoError(t, err)
+	ambient := records[0].SDRRecord.(*SDRFullSensor)
+	cpu := records[1].SDRRecord.(*SDRFullSensor)
+
+	positive, negative, err := client.GetSensorHysteresis(ambient)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(126), positive)
+	assert.Equal(t, float64(126), negative)
+
+	err = client.SetSensorHysteresis(ambient, 189, 200)
+	assert.NoError(t, err)
+	positive, negative, err = client.GetSensorHysteresis(ambient)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(189), positive)
+	assert.Equal(t, float64(189), negative)
+
+	_, _, err = client.GetSensorHysteresis(cpu)
+	assert.Equal(t, ErrHysteresisNotReadable, err)
+	err = client.SetSensorHysteresis(cpu, 1, 1)
+	assert.Equal(t, ErrHysteresisNotSettable, err)
+
+	err = client.Close()
+	assert.NoError(t, err)
+	s.Stop()
+}
+
+func TestSensorEventEnable(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	events, err := client.GetSensorEventEnable(0x04)
+	assert.NoError(t, err)
+	assert.Equal(t, &SensorEventEnable{EventMessages: true, Scanning: true}, events)
+
+	events = &SensorEventEnable{
+		Scanning:        true,
+		AssertionMask:   0x0201,
+		DeassertionMask: 0x0001,
+	}
+	err = client.SetSensorEventEnable(0x04, events)
+	assert.NoError(t, err)
+	result, err := client.GetSensorEventEnable(0x04)
+	assert.NoError(t, err)
+	assert.Equal(t, events, result)
+
+	events.AssertionMask = 0x0200
+	err = client.SetSensorEventEnable(0x04, events)
+	assert.NoError(t, err)
+	result, err = client.GetSensorEventEnable(0x04)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0x0200), result.AssertionMask)
+
+	res, err := client.GetSensorReading(0x04)
+	assert.NoError(t, err)
+	assert.True(t, res.EventMessagesDisabled())
+	assert.False(t, res.ScanningDisabled())
+
+	_, err = client.GetSensorEventEnable(0x42)
+	assert.Equal(t, ErrNoObj, err)
+
+	err = client.Close()
+	assert.NoError(t, err)
+	s.Stop()
+}
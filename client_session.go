@@ -0,0 +1,124 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+// GetSessionInfo gets the information of the session selected by the request per section 22.20
+func (c *Client) GetSessionInfo(req *SessionInfoRequest) (*SessionInfoResponse, error) {
+	r := &Request{
+		NetworkFunctionApp,
+		CommandGetSessionInfo,
+		req,
+	}
+	res := &SessionInfoResponse{}
+	return res, c.Send(r, res)
+}
+
+// CurrentSession gets the information of the session of the client
+func (c *Client) CurrentSession() (*SessionInfoResponse, error) {
+	return c.GetSessionInfo(&SessionInfoRequest{Index: SessionIndexCurrent})
+}
+
+// GetSessionInfoByIndex gets the information of the Nth active session, starting at 1
+func (c *Client) GetSessionInfoByIndex(index uint8) (*SessionInfoResponse, error) {
+	return c.GetSessionInfo(&SessionInfoRequest{Index: index})
+}
+
+// GetSessionInfoByHandle gets the information of the session with the given handle
+func (c *Client) GetSessionInfoByHandle(handle uint8) (*SessionInfoResponse, error) {
+	return c.GetSessionInfo(&SessionInfoRequest{Index: SessionIndexByHandle, Handle: handle})
+}
+
+// GetSessionInfoByID gets the information of the session with the given session ID
+func (c *Client) GetSessionInfoByID(id uint32) (*SessionInfoResponse, error) {
+	return c.GetSessionInfo(&SessionInfoRequest{Index: SessionIndexByID, ID: id})
+}
+
+// Sessions gets the information of all the active sessions, including the client session
+func (c *Client) Sessions() ([]*SessionInfoResponse, error) {
+	var sessions []*SessionInfoResponse
+	for index := uint8(1); ; index++ {
+		res, err := c.GetSessionInfoByIndex(index)
+		if err != nil {
+			if err == ErrInvalidPacket && index > 1 {
+				// past the session table of the BMC
+				return sessions, nil
+			}
+			return nil, err
+		}
+		if !res.IsActive() {
+			return sessions, nil
+		}
+		sessions = append(sessions, res)
+		if index >= res.MaxSessions {
+			return sessions, nil
+		}
+	}
+}
+
+// CloseSession closes the session with the given session ID,
+// closing a session other than the client session takes an administrator
+func (c *Client) CloseSession(id uint32) error {
+	if id == 0 {
+		return ErrSessionInvalidID
+	}
+	r := &Request{
+		NetworkFunctionApp,
+		CommandCloseSession,
+		&CloseSessionRequest{SessionID: id},
+	}
+	return c.Send(r, &CloseSessionResponse{})
+}
+
+// CloseSessionByHandle closes the session with the given handle
+func (c *Client) CloseSessionByHandle(handle uint8) error {
+	r := &Request{
+		NetworkFunctionApp,
+		CommandCloseSession,
+		&CloseSessionRequest{Handle: handle},
+	}
+	return c.Send(r, &CloseSessionResponse{})
+}
+
+// CloseSessions closes the active sessions for which match returns true,
+// never the client session, returning the number of sessions closed
+func (c *Client) CloseSessions(match func(*SessionInfoResponse) bool) (int, error) {
+	current, err := c.CurrentSession()
+	if err != nil {
+		return 0, err
+	}
+	sessions, err := c.Sessions()
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	for _, session := range sessions {
+		if session.Handle == current.Handle || !match(session) {
+			continue
+		}
+		err = c.CloseSessionByHandle(session.Handle)
+		if err == ErrSessionInvalidHandle {
+			// closed in the meantime
+			continue
+		}
+		if err != nil {
+			return closed, err
+		}
+		closed++
+	}
+	return closed, nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessions(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	s.SetUser(2, "admin", "secret", PrivLevelAdmin)
+	s.SetUser(3, "job", "password", PrivLevelAdmin)
+	err := s.Run()
+	assert.NoError(t, err)
+
+	c := s.NewConnection()
+	c.Username = "admin"
+	c.Password = "secret"
+	client, err := NewClient(c)
+	assert.NoError(t, err)
+	err = client.Open()
+	assert.NoError(t, err)
+
+	current, err := client.CurrentSession()
+	assert.NoError(t, err)
+	assert.True(t, current.IsActive())
+	assert.Equal(t, uint8(2), current.UserID)
+	assert.Equal(t, uint8(PrivLevelAdmin), current.Privilege)
+	assert.Equal(t, uint8(1), current.Channel)
+	assert.Equal(t, uint8(1), current.ActiveSessions)
+	assert.Equal(t, "127.0.0.1", current.RemoteIP.String())
+
+	id := client.transport.(*lan).SessionID
+	res, err := client.GetSessionInfoByID(id)
+	assert.NoError(t, err)
+	assert.Equal(t, current, res)
+	res, err = client.GetSessionInfoByHandle(current.Handle)
+	assert.NoError(t, err)
+	assert.Equal(t, current, res)
+
+	// crashed jobs leave their sessions behind
+	c = s.NewConnection()
+	c.Username = "job"
+	c.Password = "password"
+	for i := 0; i < 3; i++ {
+		job, err := NewClient(c)
+		assert.NoError(t, err)
+		err = job.Open()
+		assert.NoError(t, err)
+	}
+
+	sessions, err := client.Sessions()
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 4)
+	ports := map[uint16]bool{}
+	for _, session := range sessions {
+		ports[session.RemotePort] = true
+		assert.Equal(t, uint8(4), session.ActiveSessions)
+	}
+	assert.Len(t, ports, 4)
+
+	// the session table is full
+	job, err := NewClient(c)
+	assert.NoError(t, err)
+	err = job.Open()
+	assert.Equal(t, ErrSessionNoSlot, err)
+	job.Close()
+
+	closed, err := client.CloseSessions(func(session *SessionInfoResponse) bool {
+		return session.UserID == 3
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, closed)
+
+	sessions, err = client.Sessions()
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 1)
+	assert.Equal(t, current.Handle, sessions[0].Handle)
+
+	job, err = NewClient(c)
+	assert.NoError(t, err)
+	err = job.Open()
+	assert.NoError(t, err)
+
+	err = client.CloseSession(job.transport.(*lan).SessionID)
+	assert.NoError(t, err)
+	err = client.CloseSession(0x12345678)
+	assert.Equal(t, ErrSessionInvalidID, err)
+	err = client.CloseSessionByHandle(0x3f)
+	assert.Equal(t, ErrSessionInvalidHandle, err)
+
+	client.Close()
+
+	// the client closed its own session
+	client, err = NewClient(c)
+	assert.NoError(t, err)
+	err = client.Open()
+	assert.NoError(t, err)
+	sessions, err = client.Sessions()
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 1)
+	client.Close()
+
+	s.Stop()
+}
@@ -0,0 +1,155 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import "errors"
+
+// ErrSOLPrivilege is returned when the user cannot activate SOL on the channel
+var ErrSOLPrivilege = errors.New("user privilege is below the SOL privilege level")
+
+func (c *Client) setSOLParam(channel, param uint8, data ...uint8) error {
+	r := &Request{
+		NetworkFunctionTransport,
+		CommandSetSOLConfig,
+		&SetSOLConfigRequest{
+			Channel: channel,
+			Param:   param,
+			Data:    data,
+		},
+	}
+	return c.Send(r, &SetSOLConfigResponse{})
+}
+
+// setSOLParams writes the parameters within a single set-in-progress transaction
+func (c *Client) setSOLParams(channel uint8, params []configParamData) error {
+	return setConfigParams(func(param uint8, data ...uint8) error {
+		return c.setSOLParam(channel, param, data...)
+	}, params)
+}
+
+// GetSOLParam reads a SOL configuration parameter per section 26.3
+func (c *Client) GetSOLParam(channel, param uint8) (*SOLConfigResponse, error) {
+	r := &Request{
+		NetworkFunctionTransport,
+		CommandGetSOLConfig,
+		&SOLConfigRequest{
+			Channel: channel,
+			Param:   param,
+		},
+	}
+	res := &SOLConfigResponse{}
+	return res, c.Send(r, res)
+}
+
+// SetSOLParam writes a SOL configuration parameter, wrapped in the
+// set-in-progress protocol when the BMC implements it, per section 26.2
+func (c *Client) SetSOLParam(channel, param uint8, data ...uint8) error {
+	return c.setSOLParams(channel, []configParamData{{param, data}})
+}
+
+// GetSOLConfig reads the parameter p of the channel into p
+func (c *Client) GetSOLConfig(channel uint8, p SOLConfigParamDecoder) error {
+	res, err := c.GetSOLParam(channel, p.Param())
+	if err != nil {
+		return err
+	}
+	return p.UnmarshalBinary(res.Data)
+}
+
+// SetSOLConfig writes the parameters of the channel in order, as a single set-in-progress transaction
+func (c *Client) SetSOLConfig(channel uint8, params ...SOLConfigParam) error {
+	list := make([]configParamData, len(params))
+	for i, p := range params {
+		data, err := p.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		list[i] = configParamData{p.Param(), data}
+	}
+	return c.setSOLParams(channel, list)
+}
+
+// EnsureSOLEnabled configures the channel of the session so that the user can activate SOL
+// at the given baud rate: SOL is enabled with both bit rates set to baud, and the SOL payload
+// is enabled for the user. Only settings that differ are written. ErrSOLPrivilege is returned,
+// leaving the BMC unchanged, if the privilege of the user is below the SOL privilege level
+func (c *Client) EnsureSOLEnabled(user uint8, baud int) error {
+	rate, err := SOLBitRateFromBaud(baud)
+	if err != nil {
+		return err
+	}
+
+	info, err := c.GetChannelInfo(ChannelCurrent)
+	if err != nil {
+		return err
+	}
+	channel := info.Channel & 0x0f
+
+	auth := &SOLAuth{}
+	if err = c.GetSOLConfig(channel, auth); err != nil {
+		return err
+	}
+	access, err := c.GetUserAccess(channel, user)
+	if err != nil {
+		return err
+	}
+	priv := access.UserAccess().Privilege
+	if priv == PrivLevelNoAccess || priv < auth.Privilege {
+		return ErrSOLPrivilege
+	}
+
+	var params []SOLConfigParam
+
+	var enabled SOLEnable
+	if err = c.GetSOLConfig(channel, &enabled); err != nil {
+		return err
+	}
+	if !enabled {
+		params = append(params, SOLEnable(true))
+	}
+
+	nonVolatile := &SOLNonVolatileBitRate{}
+	if err = c.GetSOLConfig(channel, nonVolatile); err != nil {
+		return err
+	}
+	if nonVolatile.SOLBitRate != rate {
+		params = append(params, &SOLNonVolatileBitRate{rate})
+	}
+
+	volatile := &SOLVolatileBitRate{}
+	if err = c.GetSOLConfig(channel, volatile); err != nil {
+		return err
+	}
+	if volatile.SOLBitRate != rate {
+		params = append(params, &SOLVolatileBitRate{rate})
+	}
+
+	if len(params) != 0 {
+		if err = c.SetSOLConfig(channel, params...); err != nil {
+			return err
+		}
+	}
+
+	payloads, err := c.GetUserPayloadAccess(channel, user)
+	if err != nil {
+		return err
+	}
+	if !payloads.HasPayload(PayloadSOL) {
+		return c.SetUserPayloadAccess(channel, user, true, PayloadSOL)
+	}
+	return nil
+}
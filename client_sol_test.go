@@ -0,0 +1,89 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSOLConfig(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	s.SetUser(2, "console", "secret", PrivLevelUser)
+	s.SetUser(3, "callback", "secret", PrivLevelCallback)
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+	err = client.Open()
+	assert.NoError(t, err)
+
+	var port SOLPayloadPort
+	err = client.GetSOLConfig(1, &port)
+	assert.NoError(t, err)
+	assert.Equal(t, SOLPayloadPort(623), port)
+
+	err = client.SetSOLConfig(1, SOLPayloadChannel(2))
+	assert.Equal(t, ErrSOLParamReadOnly, err)
+	_, err = client.GetSOLParam(1, 0x42)
+	assert.Equal(t, ErrSOLParamNotSupported, err)
+
+	err = client.SetSOLConfig(1, SOLEnable(false), &SOLVolatileBitRate{SOLBitRate19200})
+	assert.NoError(t, err)
+
+	payloads, err := client.GetUserPayloadAccess(1, 2)
+	assert.NoError(t, err)
+	assert.False(t, payloads.HasPayload(PayloadSOL))
+
+	err = client.EnsureSOLEnabled(2, 115200)
+	assert.NoError(t, err)
+
+	var enabled SOLEnable
+	err = client.GetSOLConfig(1, &enabled)
+	assert.NoError(t, err)
+	assert.True(t, bool(enabled))
+
+	nonVolatile := &SOLNonVolatileBitRate{}
+	volatile := &SOLVolatileBitRate{}
+	assert.NoError(t, client.GetSOLConfig(1, nonVolatile))
+	assert.NoError(t, client.GetSOLConfig(1, volatile))
+	assert.Equal(t, 115200, nonVolatile.Baud())
+	assert.Equal(t, 115200, volatile.Baud())
+
+	payloads, err = client.GetUserPayloadAccess(1, 2)
+	assert.NoError(t, err)
+	assert.True(t, payloads.HasPayload(PayloadSOL))
+
+	// nothing left to change
+	err = client.EnsureSOLEnabled(2, 115200)
+	assert.NoError(t, err)
+
+	err = client.EnsureSOLEnabled(3, 115200)
+	assert.Equal(t, ErrSOLPrivilege, err)
+	payloads, err = client.GetUserPayloadAccess(1, 3)
+	assert.NoError(t, err)
+	assert.False(t, payloads.HasPayload(PayloadSOL))
+
+	err = client.EnsureSOLEnabled(2, 300)
+	assert.Equal(t, ErrSOLParamValue, err)
+
+	client.Close()
+	s.Stop()
+}
@@ -17,8 +17,10 @@ limitations under the License.
 package ipmi
 
 import (
+	"context"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -42,41 +44,47 @@ import (
 //	s.Stop()
 //}
 
-//func TestDeviceID(t *testing.T) {
-//	s := NewSimulator(net.UDPAddr{})
-//	err := s.Run()
-//	assert.NoError(t, err)
+func TestDeviceID(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
 
-//	client, err := NewClient(s.NewConnection())
-//	assert.NoError(t, err)
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
 
-//	err = client.Open()
-//	assert.NoError(t, err)
+	err = client.Open()
+	assert.NoError(t, err)
 
-//	tests := []OemID{
-//		OemDell, OemHP,
-//	}
+	tests := []OemID{
+		OemDell, OemHP, OemSupermicro47488,
+	}
 
-//	for _, test := range tests {
-//		s.SetHandler(NetworkFunctionApp, CommandGetDeviceID, func(*Message) Response {
-//			return &DeviceIDResponse{
-//				CompletionCode: CommandCompleted,
-//				ManufacturerID: test,
-//			}
-//		})
+	for _, test := range tests {
+		test := test // read by the simulator goroutine
+		s.SetHandler(NetworkFunctionApp, CommandGetDeviceID, func(*Message) Response {
+			return &DeviceIDResponse{
+				CompletionCode:          CommandCompleted,
+				AdditionalDeviceSupport: DeviceSupportSDRRepository,
+				ManufacturerID:          test,
+			}
+		})
 
-//		id, err := client.DeviceID()
-//		assert.NoError(t, err)
-//		assert.Equal(t, test, id.ManufacturerID)
-//		assert.Equal(t, test.String(), id.ManufacturerID.String())
-//	}
+		id, err := client.DeviceID()
+		assert.NoError(t, err)
+		assert.Equal(t, test, id.ManufacturerID)
+		assert.Equal(t, test.String(), id.ManufacturerID.String())
+	}
 
-//	err = client.Close()
-//	assert.NoError(t, err)
-//	s.Stop()
-//}
+	// cached from the last Device ID
+	caps, err := client.Capabilities()
+	assert.NoError(t, err)
+	assert.True(t, caps.SDRRepository)
+	assert.False(t, caps.Bridge)
 
-//
+	err = client.Close()
+	assert.NoError(t, err)
+	s.Stop()
+}
 
 func TestGetReserveSDRRepoForReserveId(t *testing.T) {
 	s := NewSimulator(net.UDPAddr{})
@@ -106,3 +114,362 @@ func TestGetReserveSDRRepoForReserveId(t *testing.T) {
 	assert.NoError(t, err)
 	s.Stop()
 }
+
+func TestChassis(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	status, err := client.ChassisStatus()
+	assert.NoError(t, err)
+	assert.True(t, status.IsSystemPowerOn())
+	assert.Equal(t, uint8(PowerRestorePolicyAlwaysOff), status.PowerRestorePolicy())
+	state, _ := status.IdentifyState()
+	assert.Equal(t, uint8(IdentifyOff), state)
+
+	err = client.ChassisIdentify(15, false)
+	assert.NoError(t, err)
+	status, _ = client.ChassisStatus()
+	state, _ = status.IdentifyState()
+	assert.Equal(t, uint8(IdentifyTemporary), state)
+	err = client.ChassisIdentify(0, true)
+	assert.NoError(t, err)
+	status, _ = client.ChassisStatus()
+	state, _ = status.IdentifyState()
+	assert.Equal(t, uint8(IdentifyIndefinite), state)
+	err = client.ChassisIdentify(0, false)
+	assert.NoError(t, err)
+	status, _ = client.ChassisStatus()
+	state, _ = status.IdentifyState()
+	assert.Equal(t, uint8(IdentifyOff), state)
+
+	policy, err := client.SetPowerRestorePolicy(PowerRestorePolicyPrevious)
+	assert.NoError(t, err)
+	assert.True(t, policy.Supports(PowerRestorePolicyAlwaysOn))
+	assert.False(t, policy.Supports(PowerRestorePolicyUnknown))
+	status, _ = client.ChassisStatus()
+	assert.Equal(t, uint8(PowerRestorePolicyPrevious), status.PowerRestorePolicy())
+	_, err = client.SetPowerRestorePolicy(0x05)
+	assert.Equal(t, ErrParamRange, err)
+
+	err = client.SetFrontPanelEnables(PowerButtonDisabled | SleepButtonDisabled)
+	assert.NoError(t, err)
+	status, _ = client.ChassisStatus()
+	_, disabled := status.FrontPanelButton(PowerButtonDisabled)
+	assert.True(t, disabled)
+	_, disabled = status.FrontPanelButton(ResetButtonDisabled)
+	assert.False(t, disabled)
+
+	err = client.SetPowerCycleInterval(10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(10), s.chassis.cycleInterval)
+
+	s.chassis.restartCause = RestartCauseWatchdog
+	cause, err := client.SystemRestartCause()
+	assert.NoError(t, err)
+	assert.Equal(t, "watchdog expired", cause.String())
+
+	s.chassis.poh = 1500 * 60
+	poh, err := client.POHCounter()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1500), poh.Hours())
+
+	err = client.Close()
+	assert.NoError(t, err)
+	s.Stop()
+}
+
+func TestBootOptions(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	err = client.SetBootDeviceEFI(BootDevicePxe)
+	assert.NoError(t, err)
+	flags, err := client.GetBootOptions()
+	assert.NoError(t, err)
+	assert.Equal(t, &BootFlags{Valid: true, EFI: true, BootDevice: BootDevicePxe}, flags)
+
+	set := &BootFlags{
+		Valid:              true,
+		Persistent:         true,
+		BootDevice:         BootDeviceDisk,
+		ConsoleRedirection: ConsoleRedirectionSuppress,
+		Verbosity:          BiosVerbosityQuiet,
+		DeviceInstance:     2,
+	}
+	err = client.SetBootOptions(set)
+	assert.NoError(t, err)
+	flags, err = client.GetBootOptions()
+	assert.NoError(t, err)
+	assert.Equal(t, set, flags)
+
+	res, err := client.GetBootParam(BootParamInfoAck, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint8{0x01, 0x01}, res.Data)
+
+	info := &BootInitiatorInfo{Channel: 1, SessionID: 0x1234, Timestamp: 0x5678}
+	err = client.SetBootInitiatorInfo(info)
+	assert.NoError(t, err)
+	rinfo, err := client.GetBootInitiatorInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, info, rinfo)
+
+	block := []byte{0xa3, 0x1a, 0x00, 'b', 'o', 'o', 't'}
+	err = client.SetBootInitiatorMailbox(0, block)
+	assert.NoError(t, err)
+	mbox, err := client.GetBootInitiatorMailbox(0)
+	assert.NoError(t, err)
+	assert.Equal(t, block, mbox)
+	_, err = client.GetBootInitiatorMailbox(1)
+	assert.Equal(t, ErrParamRange, err)
+	err = client.SetBootInitiatorMailbox(1, make([]byte, 17))
+	assert.Equal(t, ErrRequestData, err)
+
+	_, err = client.GetBootParam(0x20, 0)
+	assert.Equal(t, ErrParamRange, err)
+
+	client.Close()
+	s.Stop()
+}
+
+func TestBootOptionsInvalid(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	// the BIOS locked the boot flags
+	s.SetHandler(NetworkFunctionChassis, CommandGetSystemBootOptions, func(*Message) Response {
+		return &SystemBootOptionsResponse{
+			CompletionCode: CommandCompleted,
+			Version:        0x01,
+			Param:          BootParamBootFlags | 0x80,
+			Data:           []uint8{0x80, 0x04, 0x00, 0x00, 0x00},
+		}
+	})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	_, err = client.GetBootOptions()
+	assert.Equal(t, ErrBootParamInvalid, err)
+
+	client.Close()
+	s.Stop()
+}
+
+func TestPowerOperations(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	s.SetPowerDelays(SimulatorPowerDelays{
+		PowerOn:  20 * time.Millisecond,
+		PowerOff: 20 * time.Millisecond,
+		SoftOff:  20 * time.Millisecond,
+	})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	opts := &PowerOptions{PollInterval: 5 * time.Millisecond}
+
+	// already on, nothing to do
+	res, err := client.PowerOn(ctx, opts)
+	assert.NoError(t, err)
+	assert.True(t, res.InitialOn)
+	assert.Empty(t, res.Controls)
+
+	res, err = client.PowerCycle(ctx, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []ChassisControl{ControlPowerCycle}, res.Controls)
+	assert.Len(t, res.Transitions, 2)
+	assert.False(t, res.Transitions[0].On)
+	assert.True(t, res.Transitions[1].On)
+	assert.True(t, res.FinalOn)
+
+	res, err = client.SoftOff(ctx, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []ChassisControl{ControlPowerAcpiSoft}, res.Controls)
+	assert.False(t, res.Escalated)
+	assert.False(t, res.FinalOn)
+
+	// a system that is off is powered on rather than cycled or reset
+	res, err = client.PowerCycle(ctx, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []ChassisControl{ControlPowerUp}, res.Controls)
+	assert.True(t, res.FinalOn)
+
+	res, err = client.PowerReset(ctx, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []ChassisControl{ControlPowerHardReset}, res.Controls)
+	assert.Empty(t, res.Transitions)
+	cause, err := client.SystemRestartCause()
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(RestartCauseChassisControl), cause.Cause)
+
+	res, err = client.PowerOff(ctx, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []ChassisControl{ControlPowerDown}, res.Controls)
+	assert.False(t, res.FinalOn)
+
+	res, err = client.PowerOff(ctx, opts)
+	assert.NoError(t, err)
+	assert.Empty(t, res.Controls)
+
+	res, err = client.PowerOn(ctx, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []ChassisControl{ControlPowerUp}, res.Controls)
+	assert.Equal(t, []bool{true}, []bool{res.Transitions[0].On})
+
+	client.Close()
+	s.Stop()
+}
+
+func TestPowerCycleFast(t *testing.T) {
+	// no delays, the cycle completes before the first poll
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	opts := &PowerOptions{PollInterval: 5 * time.Millisecond, Timeout: time.Second}
+	// the second time the last power event is already an IPMI command
+	for i := 0; i < 2; i++ {
+		res, err := client.PowerCycle(context.Background(), opts)
+		assert.NoError(t, err)
+		assert.Equal(t, []ChassisControl{ControlPowerCycle}, res.Controls)
+		assert.Empty(t, res.Transitions)
+		assert.True(t, res.FinalOn)
+	}
+
+	// the timeout applies from the start
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.PowerCycle(ctx, opts)
+	assert.Equal(t, context.Canceled, err)
+
+	client.Close()
+	s.Stop()
+}
+
+func TestPowerSoftOffEscalation(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	s.SetPowerDelays(SimulatorPowerDelays{IgnoreSoftOff: true})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	opts := &PowerOptions{
+		PollInterval: 5 * time.Millisecond,
+		SoftOffGrace: 20 * time.Millisecond,
+	}
+	res, err := client.SoftOff(context.Background(), opts)
+	assert.NoError(t, err)
+	assert.True(t, res.Escalated)
+	assert.Equal(t, []ChassisControl{ControlPowerAcpiSoft, ControlPowerDown}, res.Controls)
+	assert.False(t, res.FinalOn)
+
+	client.Close()
+	s.Stop()
+
+	// the BMC never reports the power off
+	s = NewSimulator(net.UDPAddr{})
+	s.SetHandler(NetworkFunctionChassis, CommandChassisControl, func(*Message) Response {
+		return CommandCompleted
+	})
+	err = s.Run()
+	assert.NoError(t, err)
+
+	client, err = NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	opts.Timeout = 20 * time.Millisecond
+	_, err = client.PowerOff(context.Background(), opts)
+	assert.Equal(t, ErrPowerTimeout, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	opts.Timeout = 0
+	_, err = client.PowerOff(ctx, opts)
+	assert.Equal(t, context.Canceled, err)
+
+	client.Close()
+	s.Stop()
+}
+
+func TestDeviceManagement(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	selfTest, err := client.SelfTestResults()
+	assert.NoError(t, err)
+	assert.True(t, selfTest.Passed())
+
+	guid, err := client.SystemGUID()
+	assert.NoError(t, err)
+	assert.Equal(t, "4c4c4544-0042-3510-8056-b4c04f4d4e31", guid.UUID().String())
+	guid, err = client.DeviceGUID()
+	assert.NoError(t, err)
+	assert.Equal(t, "3e5a1288-1d6f-e411-93bd-1b9e0a27a4ab", guid.UUID().String())
+
+	err = client.SetACPIPowerState(ACPISystemS3, ACPINoChange)
+	assert.NoError(t, err)
+	acpi, err := client.ACPIPowerState()
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(ACPISystemS3), acpi.System())
+	assert.Equal(t, uint8(ACPIDeviceD0), acpi.Device())
+
+	err = client.WarmReset()
+	assert.NoError(t, err)
+
+	err = client.SetWatchdog(&WatchdogConfig{Use: WatchdogUseSMSOS, Countdown: time.Minute})
+	assert.NoError(t, err)
+	start := time.Now()
+	err = client.ColdReset(context.Background(), 50*time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, time.Since(start) >= simulatorResetTime)
+	// the BMC forgot the watchdog configuration
+	err = client.ResetWatchdog()
+	assert.Equal(t, ErrWatchdogNotInitialized, err)
+
+	client.Close()
+	s.Stop()
+}
@@ -0,0 +1,236 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import "errors"
+
+var (
+	ErrUserNameLength     = errors.New("user name longer than 16 bytes")
+	ErrUserPasswordLength = errors.New("password longer than the password size")
+	ErrUserPasswordSize   = errors.New("password size is neither 16 nor 20 bytes")
+	ErrUserNotFound       = errors.New("user not found")
+)
+
+// User is a BMC user account and its access to one channel
+type User struct {
+	ID        uint8
+	Name      string
+	Enabled   bool
+	FixedName bool // the name cannot be changed
+	Channel   uint8
+	Access    UserAccess
+}
+
+// GetUserAccess gets the access of a user to a channel, with the user ID counts
+func (c *Client) GetUserAccess(channel, id uint8) (*UserAccessResponse, error) {
+	r := &Request{
+		NetworkFunctionApp,
+		CommandGetUserAccess,
+		&UserAccessRequest{
+			Channel: channel & 0x0f,
+			UserID:  id & 0x3f,
+		},
+	}
+	res := &UserAccessResponse{}
+	return res, c.Send(r, res)
+}
+
+// SetUserAccess sets the access of a user to a channel
+func (c *Client) SetUserAccess(channel, id uint8, access *UserAccess) error {
+	bits := access.bits()
+	r := &Request{
+		NetworkFunctionApp,
+		CommandSetUserAccess,
+		&SetUserAccessRequest{
+			Channel:   0x80 | bits&0x70 | channel&0x0f,
+			UserID:    id & 0x3f,
+			Privilege: bits & 0x0f,
+		},
+	}
+	return c.Send(r, &SetUserAccessResponse{})
+}
+
+// GetUserName gets the name of a user
+func (c *Client) GetUserName(id uint8) (string, error) {
+	r := &Request{
+		NetworkFunctionApp,
+		CommandGetUserName,
+		&UserNameRequest{id & 0x3f},
+	}
+	res := &UserNameResponse{}
+	if err := c.Send(r, res); err != nil {
+		return "", err
+	}
+	return userName(res.Name), nil
+}
+
+// SetUserName sets the name of a user
+func (c *Client) SetUserName(id uint8, name string) error {
+	if len(name) > UserNameSize {
+		return ErrUserNameLength
+	}
+	req := &SetUserNameRequest{UserID: id & 0x3f}
+	copy(req.Name[:], name)
+	r := &Request{
+		NetworkFunctionApp,
+		CommandSetUserName,
+		req,
+	}
+	return c.Send(r, &SetUserNameResponse{})
+}
+
+func (c *Client) setUserPassword(id, op uint8, password string, size int) error {
+	req := &SetUserPasswordRequest{
+		UserID:    id & 0x3f,
+		Operation: op,
+	}
+	if op == UserPasswordSet || op == UserPasswordTest {
+		if size != UserPassword16 && size != UserPassword20 {
+			return ErrUserPasswordSize
+		}
+		if len(password) > size {
+			return ErrUserPasswordLength
+		}
+		if size == UserPassword20 {
+			req.UserID |= 0x80
+		}
+		req.Password = make([]uint8, size)
+		copy(req.Password, password)
+	}
+	r := &Request{
+		NetworkFunctionApp,
+		CommandSetUserPassword,
+		req,
+	}
+	return c.Send(r, &SetUserPasswordResponse{})
+}
+
+// SetUserPassword sets the password of a user, size is either UserPassword16 or UserPassword20
+func (c *Client) SetUserPassword(id uint8, password string, size int) error {
+	return c.setUserPassword(id, UserPasswordSet, password, size)
+}
+
+// TestUserPassword tells whether the password of a user is the given one and of the given size
+func (c *Client) TestUserPassword(id uint8, password string, size int) (bool, error) {
+	err := c.setUserPassword(id, UserPasswordTest, password, size)
+	switch err {
+	case nil:
+		return true, nil
+	case ErrPasswordMismatch, ErrPasswordSize:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// EnableUser enables a user
+func (c *Client) EnableUser(id uint8) error {
+	return c.setUserPassword(id, UserPasswordEnable, "", 0)
+}
+
+// DisableUser disables a user
+func (c *Client) DisableUser(id uint8) error {
+	return c.setUserPassword(id, UserPasswordDisable, "", 0)
+}
+
+// GetUserPayloadAccess gets the payload types a user may activate on a channel
+func (c *Client) GetUserPayloadAccess(channel, id uint8) (*UserPayloadAccessResponse, error) {
+	r := &Request{
+		NetworkFunctionApp,
+		CommandGetUserPayloadAccess,
+		&UserPayloadAccessRequest{
+			Channel: channel & 0x0f,
+			UserID:  id & 0x3f,
+		},
+	}
+	res := &UserPayloadAccessResponse{}
+	return res, c.Send(r, res)
+}
+
+// SetUserPayloadAccess enables or disables the given payload types for a user on a channel
+func (c *Client) SetUserPayloadAccess(channel, id uint8, enable bool, payloads ...uint8) error {
+	req := &SetUserPayloadAccessRequest{
+		Channel: channel & 0x0f,
+		UserID:  id & 0x3f,
+	}
+	if !enable {
+		req.UserID |= 0x40
+	}
+	for _, payload := range payloads {
+		req.Payloads |= payloadBit(payload)
+	}
+	r := &Request{
+		NetworkFunctionApp,
+		CommandSetUserPayloadAccess,
+		req,
+	}
+	return c.Send(r, &SetUserPayloadAccessResponse{})
+}
+
+// GetUser gets a user and its access to a channel
+func (c *Client) GetUser(channel, id uint8) (*User, error) {
+	access, err := c.GetUserAccess(channel, id)
+	if err != nil {
+		return nil, err
+	}
+	name, err := c.GetUserName(id)
+	if err != nil {
+		return nil, err
+	}
+	return &User{
+		ID:        id,
+		Name:      name,
+		Enabled:   access.Status() == UserStatusEnabled,
+		FixedName: id <= access.FixedNames&0x3f,
+		Channel:   channel,
+		Access:    access.UserAccess(),
+	}, nil
+}
+
+// Users enumerates the user IDs up to the maximum the BMC supports,
+// with their access to a channel
+func (c *Client) Users(channel uint8) ([]*User, error) {
+	access, err := c.GetUserAccess(channel, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	max := access.MaxUsers & 0x3f
+	users := make([]*User, 0, max)
+	for id := uint8(1); id <= max; id++ {
+		user, err := c.GetUser(channel, id)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// FindUser looks a user up by name, returning ErrUserNotFound if there is none
+func (c *Client) FindUser(channel uint8, name string) (*User, error) {
+	users, err := c.Users(channel)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		if user.Name == name {
+			return user, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
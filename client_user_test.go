@@ -0,0 +1,176 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsers(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	s.SetUser(2, "admin", "secret", PrivLevelAdmin)
+	// out of range ids are ignored
+	s.SetUser(0, "zero", "secret", PrivLevelAdmin)
+	s.SetUser(simulatorMaxUsers+1, "eleven", "secret", PrivLevelAdmin)
+	err := s.Run()
+	assert.NoError(t, err)
+
+	c := s.NewConnection()
+	c.Username = "admin"
+	c.Password = "secret"
+	client, err := NewClient(c)
+	assert.NoError(t, err)
+	err = client.Open()
+	assert.NoError(t, err)
+
+	users, err := client.Users(ChannelCurrent)
+	assert.NoError(t, err)
+	assert.Len(t, users, simulatorMaxUsers)
+	assert.Equal(t, &User{
+		ID:        1,
+		Enabled:   true,
+		FixedName: true,
+		Channel:   ChannelCurrent,
+		Access:    UserAccess{Privilege: PrivLevelAdmin, IPMIMessaging: true},
+	}, users[0])
+	assert.Equal(t, "admin", users[1].Name)
+	assert.False(t, users[2].Enabled)
+	assert.Equal(t, uint8(PrivLevelNoAccess), users[2].Access.Privilege)
+
+	// add an operator
+	err = client.SetUserName(3, "operator")
+	assert.NoError(t, err)
+	err = client.SetUserName(4, "operator")
+	assert.Equal(t, ErrInvalidPacket, err)
+	err = client.SetUserName(1, "null")
+	assert.Equal(t, ErrInvalidPacket, err)
+	err = client.SetUserName(3, "a name longer than 16 bytes")
+	assert.Equal(t, ErrUserNameLength, err)
+	err = client.SetUserPassword(3, "a twenty byte secret", UserPassword20)
+	assert.NoError(t, err)
+	err = client.SetUserAccess(1, 3, &UserAccess{Privilege: PrivLevelOperator, IPMIMessaging: true})
+	assert.NoError(t, err)
+	err = client.EnableUser(3)
+	assert.NoError(t, err)
+	err = client.SetUserPayloadAccess(1, 3, true, PayloadSOL)
+	assert.NoError(t, err)
+
+	user, err := client.FindUser(ChannelCurrent, "operator")
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(3), user.ID)
+	assert.True(t, user.Enabled)
+	assert.False(t, user.FixedName)
+	assert.Equal(t, uint8(PrivLevelOperator), user.Access.Privilege)
+	_, err = client.FindUser(ChannelCurrent, "nobody")
+	assert.Equal(t, ErrUserNotFound, err)
+
+	payloads, err := client.GetUserPayloadAccess(1, 3)
+	assert.NoError(t, err)
+	assert.True(t, payloads.HasPayload(PayloadSOL))
+	err = client.SetUserPayloadAccess(1, 3, false, PayloadSOL)
+	assert.NoError(t, err)
+	payloads, err = client.GetUserPayloadAccess(1, 3)
+	assert.NoError(t, err)
+	assert.False(t, payloads.HasPayload(PayloadSOL))
+
+	ok, err := client.TestUserPassword(3, "a twenty byte secret", UserPassword20)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	// wrong size
+	ok, err = client.TestUserPassword(3, "a twenty byte", UserPassword16)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	_, err = client.TestUserPassword(3, "a password longer than 20 bytes", UserPassword20)
+	assert.Equal(t, ErrUserPasswordLength, err)
+	err = client.SetUserPassword(3, "secret", 8)
+	assert.Equal(t, ErrUserPasswordSize, err)
+
+	// rotate the admin password
+	err = client.SetUserPassword(2, "rotated", UserPassword16)
+	assert.NoError(t, err)
+	ok, err = client.TestUserPassword(2, "secret", UserPassword16)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = client.TestUserPassword(2, "rotated", UserPassword16)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = client.GetUserAccess(1, 11)
+	assert.Equal(t, ErrParamRange, err)
+
+	client.Close()
+
+	// the old password no longer opens a session
+	client, err = NewClient(c)
+	assert.NoError(t, err)
+	err = client.Open()
+	assert.Error(t, err)
+	client.Close()
+
+	c.Password = "rotated"
+	client, err = NewClient(c)
+	assert.NoError(t, err)
+	err = client.Open()
+	assert.NoError(t, err)
+	client.Close()
+
+	s.Stop()
+}
+
+func TestUserSessionAuthentication(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	s.SetUser(2, "operator", "secret", PrivLevelOperator)
+	err := s.Run()
+	assert.NoError(t, err)
+
+	c := s.NewConnection()
+	c.Username = "unknown"
+	client, err := NewClient(c)
+	assert.NoError(t, err)
+	err = client.Open()
+	assert.Equal(t, ErrSessionInvalidUser, err)
+	client.Close()
+
+	// the client asks for an administrator session
+	c.Username = "operator"
+	c.Password = "secret"
+	client, err = NewClient(c)
+	assert.NoError(t, err)
+	err = client.Open()
+	assert.Equal(t, ErrSessionPrivLimit, err)
+	client.Close()
+
+	// disable the null user
+	client, err = NewClient(s.NewConnection())
+	assert.NoError(t, err)
+	err = client.Open()
+	assert.NoError(t, err)
+	err = client.DisableUser(1)
+	assert.NoError(t, err)
+	client.Close()
+
+	client, err = NewClient(s.NewConnection())
+	assert.NoError(t, err)
+	err = client.Open()
+	assert.Equal(t, ErrSessionNullUserDisable, err)
+	client.Close()
+
+	s.Stop()
+}
@@ -0,0 +1,138 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+var (
+	ErrWatchdogCountdownRange = errors.New("watchdog countdown out of range")
+)
+
+// WatchdogConfig configures the BMC watchdog timer
+type WatchdogConfig struct {
+	Use        WatchdogTimerUse
+	Action     WatchdogAction
+	PreTimeout WatchdogPreTimeout
+	// PreTimeoutInterval is how long before the timeout the pre-timeout interrupt is raised
+	PreTimeoutInterval time.Duration
+	// Countdown is the timeout, in WatchdogCountdownUnit increments up to 6553.5 seconds
+	Countdown time.Duration
+	// DontStop keeps a running timer running with the new countdown instead of stopping it
+	DontStop bool
+	// DontLog disables logging the expiration to the SEL
+	DontLog bool
+	// ClearFlags are the timer use expiration flags to clear, see WatchdogExpirationFlag
+	ClearFlags uint8
+}
+
+func (w *WatchdogConfig) request() (*SetWatchdogRequest, error) {
+	countdown := w.Countdown / WatchdogCountdownUnit
+	interval := w.PreTimeoutInterval / time.Second
+	if countdown < 0 || countdown > math.MaxUint16 || interval < 0 || interval > math.MaxUint8 {
+		return nil, ErrWatchdogCountdownRange
+	}
+
+	r := &SetWatchdogRequest{
+		TimerUse:           uint8(w.Use) & 0x07,
+		TimerActions:       uint8(w.PreTimeout&0x07)<<4 | uint8(w.Action&0x07),
+		PreTimeoutInterval: uint8(interval),
+		ExpirationFlags:    w.ClearFlags & 0x3e,
+		InitialCountdown:   uint16(countdown),
+	}
+	if w.DontStop {
+		r.TimerUse |= WatchdogDontStop
+	}
+	if w.DontLog {
+		r.TimerUse |= WatchdogDontLog
+	}
+	return r, nil
+}
+
+// SetWatchdog configures the watchdog timer, the timer is stopped unless
+// WatchdogConfig.DontStop is set, ResetWatchdog starts it
+func (c *Client) SetWatchdog(w *WatchdogConfig) error {
+	data, err := w.request()
+	if err != nil {
+		return err
+	}
+	r := &Request{
+		NetworkFunctionApp,
+		CommandSetWatchdog,
+		data,
+	}
+	return c.Send(r, &SetWatchdogResponse{})
+}
+
+// GetWatchdog gets the watchdog timer configuration, expiration flags and countdown
+func (c *Client) GetWatchdog() (*GetWatchdogResponse, error) {
+	r := &Request{
+		NetworkFunctionApp,
+		CommandGetWatchdog,
+		&GetWatchdogRequest{},
+	}
+	res := &GetWatchdogResponse{}
+	return res, c.Send(r, res)
+}
+
+// ResetWatchdog starts the watchdog timer, or restarts its countdown if it is running.
+// ErrWatchdogNotInitialized is returned if the timer was never set
+func (c *Client) ResetWatchdog() error {
+	r := &Request{
+		NetworkFunctionApp,
+		CommandResetWatchdog,
+		&ResetWatchdogRequest{},
+	}
+	return c.Send(r, &ResetWatchdogResponse{})
+}
+
+// PetWatchdog resets the watchdog timer every interval from a goroutine until ctx is done.
+// The interval must be well under the countdown so that a lost packet does not let the
+// timer expire. Reset errors are reported on the returned channel, dropping those the
+// caller does not keep up with, and the channel is closed once the goroutine returns.
+// The timer keeps running after ctx is done, call SetWatchdog to stop it on a clean exit.
+func (c *Client) PetWatchdog(ctx context.Context, interval time.Duration) <-chan error {
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := c.ResetWatchdog(); err != nil {
+				select {
+				case errs <- err:
+				default:
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return errs
+}
@@ -0,0 +1,127 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchdog(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	err = client.ResetWatchdog()
+	assert.Equal(t, ErrWatchdogNotInitialized, err)
+
+	w := &WatchdogConfig{
+		Use:       WatchdogUseSMSOS,
+		Action:    WatchdogActionPowerDown,
+		Countdown: 300 * time.Millisecond,
+	}
+	err = client.SetWatchdog(w)
+	assert.NoError(t, err)
+	res, err := client.GetWatchdog()
+	assert.NoError(t, err)
+	assert.False(t, res.IsRunning())
+	assert.Equal(t, WatchdogActionPowerDown, res.Action())
+	assert.Equal(t, w.Countdown, res.Initial())
+	assert.Equal(t, w.Countdown, res.Present())
+
+	// petting keeps the system up past the countdown
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := client.PetWatchdog(ctx, 50*time.Millisecond)
+	time.Sleep(600 * time.Millisecond)
+	res, err = client.GetWatchdog()
+	assert.NoError(t, err)
+	assert.True(t, res.IsRunning())
+	assert.False(t, res.Expired(WatchdogUseSMSOS))
+	status, err := client.ChassisStatus()
+	assert.NoError(t, err)
+	assert.True(t, status.IsSystemPowerOn())
+
+	// a hung OS stops petting
+	cancel()
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	time.Sleep(500 * time.Millisecond)
+	status, err = client.ChassisStatus()
+	assert.NoError(t, err)
+	assert.False(t, status.IsSystemPowerOn())
+	res, err = client.GetWatchdog()
+	assert.NoError(t, err)
+	assert.False(t, res.IsRunning())
+	assert.True(t, res.Expired(WatchdogUseSMSOS))
+	assert.Equal(t, time.Duration(0), res.Present())
+
+	w.ClearFlags = WatchdogExpirationFlag(WatchdogUseSMSOS)
+	err = client.SetWatchdog(w)
+	assert.NoError(t, err)
+	res, err = client.GetWatchdog()
+	assert.NoError(t, err)
+	assert.False(t, res.Expired(WatchdogUseSMSOS))
+
+	client.Close()
+	s.Stop()
+}
+
+func TestWatchdogHardReset(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	err = client.SetWatchdog(&WatchdogConfig{
+		Use:       WatchdogUseOSLoad,
+		Action:    WatchdogActionHardReset,
+		Countdown: 100 * time.Millisecond,
+	})
+	assert.NoError(t, err)
+	err = client.ResetWatchdog()
+	assert.NoError(t, err)
+	time.Sleep(300 * time.Millisecond)
+
+	cause, err := client.SystemRestartCause()
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(RestartCauseWatchdog), cause.Cause)
+	status, err := client.ChassisStatus()
+	assert.NoError(t, err)
+	assert.True(t, status.IsSystemPowerOn())
+	res, err := client.GetWatchdog()
+	assert.NoError(t, err)
+	assert.True(t, res.Expired(WatchdogUseOSLoad))
+
+	client.Close()
+	s.Stop()
+}
@@ -22,13 +22,41 @@ type Command uint8
 // Command Number Assignments (table G-1)
 const (
 	CommandGetDeviceID              = Command(0x01)
+	CommandColdReset                = Command(0x02)
+	CommandWarmReset                = Command(0x03)
+	CommandGetSelfTestResults       = Command(0x04)
+	CommandSetACPIPowerState        = Command(0x06)
+	CommandGetACPIPowerState        = Command(0x07)
+	CommandGetDeviceGUID            = Command(0x08)
+	CommandGetSystemGUID            = Command(0x37)
+	CommandSetChannelAccess         = Command(0x40)
+	CommandGetChannelAccess         = Command(0x41)
+	CommandGetChannelInfo           = Command(0x42)
+	CommandGetChannelCipherSuites   = Command(0x54)
+	CommandSetUserAccess            = Command(0x43)
+	CommandGetUserAccess            = Command(0x44)
+	CommandSetUserName              = Command(0x45)
+	CommandGetUserName              = Command(0x46)
+	CommandSetUserPassword          = Command(0x47)
+	CommandSetUserPayloadAccess     = Command(0x4c)
+	CommandGetUserPayloadAccess     = Command(0x4d)
 	CommandGetAuthCapabilities      = Command(0x38)
 	CommandGetSessionChallenge      = Command(0x39)
 	CommandActivateSession          = Command(0x3a)
 	CommandSetSessionPrivilegeLevel = Command(0x3b)
 	CommandCloseSession             = Command(0x3c)
+	CommandGetSessionInfo           = Command(0x3d)
+	CommandResetWatchdog            = Command(0x22)
+	CommandSetWatchdog              = Command(0x24)
+	CommandGetWatchdog              = Command(0x25)
 	CommandChassisControl           = Command(0x02)
 	CommandChassisStatus            = Command(0x01)
+	CommandChassisIdentify          = Command(0x04)
+	CommandSetPowerRestorePolicy    = Command(0x06)
+	CommandGetSystemRestartCause    = Command(0x07)
+	CommandSetFrontPanelEnables     = Command(0x0a)
+	CommandSetPowerCycleInterval    = Command(0x0b)
+	CommandGetPOHCounter            = Command(0x0f)
 	CommandSetSystemBootOptions     = Command(0x08)
 	CommandGetSystemBootOptions     = Command(0x09)
 	// CommandGetSDRRepositoryInfo     = Command(0x20)
@@ -60,8 +88,9 @@ type DeviceIDResponse struct {
 	FirmwareRevision2       uint8
 	IPMIVersion             uint8
 	AdditionalDeviceSupport uint8
-	ManufacturerID          OemID
+	ManufacturerID          OemID // 3 bytes on the wire
 	ProductID               uint16
+	AuxFirmwareRevision     []uint8 // optional, 4 bytes when present
 }
 
 // DeviceIDRequest per section 33.9
@@ -80,7 +109,7 @@ type SDRRepositoryInfoResponse struct {
 
 // AuthCapabilitiesRequest per section 22.13
 type AuthCapabilitiesRequest struct {
-	ChannelNumber uint8
+	ChannelNumber uint8 // [7] get the IPMI v2.0 extended data, [3:0] channel
 	PrivLevel     uint8
 }
 
@@ -88,10 +117,10 @@ type AuthCapabilitiesRequest struct {
 type AuthCapabilitiesResponse struct {
 	CompletionCode
 	ChannelNumber   uint8
-	AuthTypeSupport uint8
+	AuthTypeSupport uint8 // [7] IPMI v2.0 extended capabilities, [5:0] auth types
 	Status          uint8
-	Reserved        uint8
-	OEMID           uint16
+	ExtCapabilities uint8
+	OEMID           OemID // 3 bytes on the wire
 	OEMAux          uint8
 }
 
@@ -113,8 +142,13 @@ const (
 	PrivLevelOperator
 	PrivLevelAdmin
 	PrivLevelOEM
+	// PrivLevelNoAccess is the user privilege limit of a user without access to a channel
+	PrivLevelNoAccess = 0x0f
 )
 
+// ChannelCurrent selects the channel the request is received on
+const ChannelCurrent = 0x0e
+
 // SessionChallengeRequest per section 22.16
 type SessionChallengeRequest struct {
 	AuthType uint8
@@ -156,9 +190,10 @@ type SessionPrivilegeLevelResponse struct {
 	NewPrivilegeLevel uint8
 }
 
-// CloseSessionRequest per section 22.19
+// CloseSessionRequest per section 22.19, Handle selects the session when SessionID is 0
 type CloseSessionRequest struct {
 	SessionID uint32
+	Handle    uint8
 }
 
 // CloseSessionResponse per section 22.19
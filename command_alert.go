@@ -0,0 +1,136 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+// section 30.7 and 30.8, alerting commands on NetworkFunctionSensorEvent
+const (
+	CommandAlertImmediate = Command(0x16)
+	CommandPETAcknowledge = Command(0x17)
+)
+
+// Alert Immediate operations
+const (
+	AlertImmediateInitiate    = 0x0
+	AlertImmediateGetStatus   = 0x1
+	AlertImmediateClearStatus = 0x2
+)
+
+// Alert Immediate status
+const (
+	AlertStatusNone            = 0x00
+	AlertStatusNormalEnd       = 0x01
+	AlertStatusCallRetryFailed = 0x02
+	AlertStatusAckTimeout      = 0x03
+	AlertStatusInProgress      = 0xff
+)
+
+// Alert Immediate completion codes
+const (
+	ErrAlertInProgress        = CompletionCode(0x81)
+	ErrAlertSessionActive     = CompletionCode(0x82) // an IPMI messaging session is active on the channel
+	ErrAlertEventNotSupported = CompletionCode(0x83) // the platform event parameters are not supported
+)
+
+// alertStringSend is the Alert Immediate string selector bit to send the alert string
+const alertStringSend = 0x80
+
+// AlertImmediateRequest per section 30.7, Event is the optional platform event of the alert
+type AlertImmediateRequest struct {
+	Channel     uint8
+	Operation   uint8 // AlertImmediate*
+	Destination uint8
+	String      uint8 // alert string selector, with alertStringSend
+	Event       *SELEvent
+}
+
+// AlertImmediateResponse per section 30.7, Status is set by the get status operation only
+type AlertImmediateResponse struct {
+	CompletionCode
+	Status uint8 // AlertStatus*
+}
+
+// PETAcknowledgeRequest per section 30.8, the fields of the trap being acknowledged, LS byte first
+type PETAcknowledgeRequest struct {
+	Sequence     uint16
+	Timestamp    uint32 // local time of the system in seconds since 1998
+	EventSource  uint8
+	SensorDevice uint8
+	SensorNumber uint8
+	EventData    [3]uint8
+}
+
+// PETAcknowledgeResponse per section 30.8
+type PETAcknowledgeResponse struct {
+	CompletionCode
+}
+
+// MarshalBinary implementation to handle the optional platform event
+func (r *AlertImmediateRequest) MarshalBinary() ([]byte, error) {
+	buf := []byte{r.Channel & 0x0f, r.Operation<<6 | r.Destination&0x0f, r.String}
+	if r.Event != nil {
+		buf = append(buf, uint8(r.Event.GeneratorID))
+		buf = append(buf, marshalEventMessage(r.Event)...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implementation to handle the optional platform event
+func (r *AlertImmediateRequest) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 3 {
+		return ErrShortPacket
+	}
+	r.Channel = buf[0] & 0x0f
+	r.Operation = buf[1] >> 6
+	r.Destination = buf[1] & 0x0f
+	r.String = buf[2]
+	r.Event = nil
+	if len(buf) >= 4+eventMessageSize {
+		r.Event = unmarshalEventMessage(buf[4:])
+		r.Event.GeneratorID = uint16(buf[3])
+	}
+	return nil
+}
+
+// MarshalBinary implementation to handle the optional Status
+func (r *AlertImmediateResponse) MarshalBinary() ([]byte, error) {
+	return []byte{uint8(r.CompletionCode), r.Status}, nil
+}
+
+// UnmarshalBinary implementation to handle the optional Status
+func (r *AlertImmediateResponse) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1 {
+		return ErrShortPacket
+	}
+	r.CompletionCode = CompletionCode(buf[0])
+	r.Status = AlertStatusNone
+	if len(buf) > 1 {
+		r.Status = buf[1]
+	}
+	return nil
+}
+
+// NewPETAcknowledgeRequest constructs the acknowledgement of a received trap
+func NewPETAcknowledgeRequest(e *PETEvent) *PETAcknowledgeRequest {
+	return &PETAcknowledgeRequest{
+		Sequence:     e.Sequence,
+		Timestamp:    e.localTimestamp(),
+		EventSource:  e.EventSource,
+		SensorDevice: e.SensorDevice,
+		SensorNumber: e.SensorNumber,
+		EventData:    e.EventData,
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertImmediate(t *testing.T) {
+	req := &AlertImmediateRequest{
+		Channel:     1,
+		Operation:   AlertImmediateInitiate,
+		Destination: 2,
+		String:      alertStringSend | 1,
+		Event: &SELEvent{
+			RecordType:   SELRecordSystemEvent,
+			GeneratorID:  bmcSlaveAddr,
+			EvMRev:       SELEvMRev,
+			SensorType:   SDR_SENSOR_TYPECODES_FAN,
+			SensorNumber: 0x40,
+			EventType:    SENSOR_READTYPE_THREADHOLD,
+			Deassertion:  true,
+			EventData:    [3]uint8{0x52, 0xff, 0xff},
+		},
+	}
+
+	data, err := req.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, rawDecode("01 02 81 20 04 04 40 81 52 ff ff"), data)
+
+	decoded := &AlertImmediateRequest{}
+	err = decoded.UnmarshalBinary(data)
+	assert.NoError(t, err)
+	assert.Equal(t, req, decoded)
+
+	// get status, without platform event
+	req = &AlertImmediateRequest{Channel: 1, Operation: AlertImmediateGetStatus, Destination: 2}
+	data, err = req.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, rawDecode("01 42 00"), data)
+	err = decoded.UnmarshalBinary(data)
+	assert.NoError(t, err)
+	assert.Equal(t, req, decoded)
+
+	res := &AlertImmediateResponse{}
+	err = responseFromString("ff", res)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(AlertStatusInProgress), res.Status)
+	err = responseFromString("", res)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(AlertStatusNone), res.Status)
+}
+
+func TestPETAcknowledge(t *testing.T) {
+	event := &PETEvent{}
+	err := event.UnmarshalBinary(testPET)
+	assert.NoError(t, err)
+
+	req := NewPETAcknowledgeRequest(event)
+	assert.Equal(t, &PETAcknowledgeRequest{
+		Sequence:     5,
+		Timestamp:    0x29610480,
+		EventSource:  0x20,
+		SensorDevice: 0x20,
+		SensorNumber: 0x30,
+		EventData:    [3]uint8{0x51, 0x00, 0x00},
+	}, req)
+
+	decoded := &PETAcknowledgeRequest{}
+	err = messageDataFromBytes(rawDecode("05 00 80 04 61 29 20 20 30 51 00 00"), decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, req, decoded)
+}
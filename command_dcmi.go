@@ -0,0 +1,492 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// DCMI commands on NetworkFunctionGroupExtension per the DCMI v1.5 specification
+const (
+	CommandGetDCMICapabilities    = Command(0x01)
+	CommandGetPowerReading        = Command(0x02)
+	CommandGetPowerLimit          = Command(0x03)
+	CommandSetPowerLimit          = Command(0x04)
+	CommandActivatePowerLimit     = Command(0x05)
+	CommandGetAssetTag            = Command(0x06)
+	CommandGetDCMISensorInfo      = Command(0x07)
+	CommandSetAssetTag            = Command(0x08)
+	CommandGetMCIDString          = Command(0x09)
+	CommandSetMCIDString          = Command(0x0a)
+	CommandGetTemperatureReadings = Command(0x10)
+)
+
+// DCMIGroup is the group extension ID, the first byte of the DCMI requests and responses
+type DCMIGroup uint8
+
+// DCMIGroupExtension is the group extension ID of DCMI
+const DCMIGroupExtension = DCMIGroup(0xdc)
+
+// Get DCMI Capabilities Info parameters
+const (
+	DCMICapParamSupported       = 1
+	DCMICapParamMandatoryAttrs  = 2
+	DCMICapParamOptionalAttrs   = 3
+	DCMICapParamAccessAttrs     = 4
+	DCMICapParamPowerStatsAttrs = 5
+)
+
+// Get Power Reading modes
+const (
+	DCMIPowerStatistics         = 0x01
+	DCMIPowerEnhancedStatistics = 0x02 // over a rolling average time period
+)
+
+// Power limit exception actions
+const (
+	DCMIExceptionNone     = 0x00
+	DCMIExceptionPowerOff = 0x01 // hard power off and log to the SEL
+	DCMIExceptionLogSEL   = 0x11
+)
+
+// DCMI entity IDs of the temperature sensors
+const (
+	DCMIEntityInlet     = 0x40
+	DCMIEntityCPU       = 0x41
+	DCMIEntityBaseboard = 0x42
+)
+
+// DCMISensorTypeTemperature is the only sensor type of Get DCMI Sensor Info and Get Temperature Readings
+const DCMISensorTypeTemperature = 0x01
+
+// DCMI completion codes
+const (
+	ErrDCMINoPowerLimit        = CompletionCode(0x80) // no power limit set
+	ErrDCMIPowerLimitRange     = CompletionCode(0x84)
+	ErrDCMICorrectionTimeRange = CompletionCode(0x85)
+	ErrDCMISamplingPeriodRange = CompletionCode(0x89)
+)
+
+// ErrDCMIValue is returned when a DCMI value cannot be encoded
+var ErrDCMIValue = errors.New("value out of range for DCMI")
+
+// dcmiStringBlockSize is the largest block of an asset tag or management controller ID string read or write
+const dcmiStringBlockSize = 16
+
+// sizes of the asset tag and of the management controller ID string, including its null terminator
+const (
+	dcmiAssetTagSize = 63
+	dcmiMCIDSize     = 64
+)
+
+// dcmiRecordsMax is the largest number of sensor records or temperatures of a response
+const dcmiRecordsMax = 8
+
+// DCMIResponse is the response of the DCMI commands that have no response data
+type DCMIResponse struct {
+	CompletionCode
+	DCMIGroup
+}
+
+// DCMICapabilitiesRequest per section 6.1.1
+type DCMICapabilitiesRequest struct {
+	DCMIGroup
+	Param uint8
+}
+
+// DCMICapabilitiesResponse per section 6.1.1
+type DCMICapabilitiesResponse struct {
+	CompletionCode
+	DCMIGroup
+	MajorVersion uint8
+	MinorVersion uint8
+	Revision     uint8
+	Data         []uint8
+}
+
+// MarshalBinary implementation to handle variable length Data
+func (r *DCMICapabilitiesResponse) MarshalBinary() ([]byte, error) {
+	buf := []byte{uint8(r.CompletionCode), uint8(r.DCMIGroup), r.MajorVersion, r.MinorVersion, r.Revision}
+	return append(buf, r.Data...), nil
+}
+
+// UnmarshalBinary implementation to handle variable length Data
+func (r *DCMICapabilitiesResponse) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 5 {
+		return ErrShortPacket
+	}
+	r.CompletionCode = CompletionCode(buf[0])
+	r.DCMIGroup = DCMIGroup(buf[1])
+	r.MajorVersion = buf[2]
+	r.MinorVersion = buf[3]
+	r.Revision = buf[4]
+	r.Data = buf[5:]
+	return nil
+}
+
+// DCMISupportedCapabilities is the capabilities parameter 1
+type DCMISupportedCapabilities struct {
+	PowerManagement bool
+	InBandKCS       bool // in-band system interface channel
+	SerialTMode     bool // out-of-band serial TMODE
+	SecondaryLAN    bool // out-of-band secondary LAN channel
+}
+
+// MarshalBinary encodes the parameter data
+func (c *DCMISupportedCapabilities) MarshalBinary() ([]byte, error) {
+	return []byte{
+		0x00,
+		boolBit(c.PowerManagement, 0x01),
+		boolBit(c.InBandKCS, 0x04) | boolBit(c.SerialTMode, 0x02) | boolBit(c.SecondaryLAN, 0x01),
+	}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (c *DCMISupportedCapabilities) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 3 {
+		return ErrShortPacket
+	}
+	c.PowerManagement = buf[1]&0x01 != 0
+	c.InBandKCS = buf[2]&0x04 != 0
+	c.SerialTMode = buf[2]&0x02 != 0
+	c.SecondaryLAN = buf[2]&0x01 != 0
+	return nil
+}
+
+// DCMIPlatformAttributes is the mandatory platform attributes parameter 2, per table 6-3
+type DCMIPlatformAttributes struct {
+	SELEntries           uint16 // up to 4096
+	SELRecordFlush       bool   // record level flush upon rollover
+	SELEntireFlush       bool   // entire SEL flush upon rollover
+	SELRollover          bool   // automatic rollover enabled
+	AssetTag             bool   // identification attributes
+	DHCPHostName         bool
+	GUID                 bool
+	InletTemperature     bool
+	CPUTemperature       bool
+	BaseboardTemperature bool
+	TemperatureSampling  uint8 // seconds
+}
+
+// MarshalBinary encodes the parameter data
+func (a *DCMIPlatformAttributes) MarshalBinary() ([]byte, error) {
+	if a.SELEntries > 0x0fff {
+		return nil, ErrDCMIValue
+	}
+	flags := boolBit(a.SELRecordFlush, 0x20) | boolBit(a.SELEntireFlush, 0x40) | boolBit(a.SELRollover, 0x80)
+	sel := a.SELEntries | uint16(flags)<<8
+	return []byte{
+		uint8(sel),
+		uint8(sel >> 8),
+		boolBit(a.AssetTag, 0x01) | boolBit(a.DHCPHostName, 0x02) | boolBit(a.GUID, 0x04),
+		boolBit(a.InletTemperature, 0x01) | boolBit(a.CPUTemperature, 0x02) | boolBit(a.BaseboardTemperature, 0x04),
+		a.TemperatureSampling,
+	}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (a *DCMIPlatformAttributes) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 5 {
+		return ErrShortPacket
+	}
+	sel := binary.LittleEndian.Uint16(buf)
+	a.SELEntries = sel & 0x0fff
+	a.SELRecordFlush = sel&0x2000 != 0
+	a.SELEntireFlush = sel&0x4000 != 0
+	a.SELRollover = sel&0x8000 != 0
+	a.AssetTag = buf[2]&0x01 != 0
+	a.DHCPHostName = buf[2]&0x02 != 0
+	a.GUID = buf[2]&0x04 != 0
+	a.InletTemperature = buf[3]&0x01 != 0
+	a.CPUTemperature = buf[3]&0x02 != 0
+	a.BaseboardTemperature = buf[3]&0x04 != 0
+	a.TemperatureSampling = buf[4]
+	return nil
+}
+
+// DCMIPowerStatisticsAttributes is the enhanced system power statistics attributes parameter 5,
+// the rolling average time periods of Get Power Reading
+type DCMIPowerStatisticsAttributes struct {
+	Periods []time.Duration
+}
+
+// units of the rolling average time periods
+var dcmiPeriodUnits = []time.Duration{time.Second, time.Minute, time.Hour, 24 * time.Hour}
+
+// dcmiPeriod encodes a rolling average time period, in the largest unit it is a multiple of
+func dcmiPeriod(period time.Duration) (uint8, error) {
+	for unit := len(dcmiPeriodUnits) - 1; unit >= 0; unit-- {
+		n := period / dcmiPeriodUnits[unit]
+		if period%dcmiPeriodUnits[unit] == 0 && n > 0 && n <= 0x3f {
+			return uint8(unit)<<6 | uint8(n), nil
+		}
+	}
+	return 0, ErrDCMIValue
+}
+
+// dcmiPeriodDuration decodes a rolling average time period
+func dcmiPeriodDuration(period uint8) time.Duration {
+	return time.Duration(period&0x3f) * dcmiPeriodUnits[period>>6]
+}
+
+// MarshalBinary encodes the parameter data
+func (a *DCMIPowerStatisticsAttributes) MarshalBinary() ([]byte, error) {
+	buf := []byte{uint8(len(a.Periods))}
+	for _, period := range a.Periods {
+		p, err := dcmiPeriod(period)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, p)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (a *DCMIPowerStatisticsAttributes) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1 || len(buf) < 1+int(buf[0]) {
+		return ErrShortPacket
+	}
+	a.Periods = nil
+	for _, p := range buf[1 : 1+buf[0]] {
+		a.Periods = append(a.Periods, dcmiPeriodDuration(p))
+	}
+	return nil
+}
+
+// PowerReadingRequest per section 6.6.1, Period is the encoded rolling average time period
+// of the enhanced statistics mode
+type PowerReadingRequest struct {
+	DCMIGroup
+	Mode     uint8
+	Period   uint8
+	Reserved uint8
+}
+
+// PowerReadingResponse per section 6.6.1, the power in watts
+type PowerReadingResponse struct {
+	CompletionCode
+	DCMIGroup
+	Current   uint16
+	Minimum   uint16
+	Maximum   uint16
+	Average   uint16
+	Timestamp uint32 // SEL timestamp of the reading
+	Period    uint32 // statistics reporting period in milliseconds
+	State     uint8
+}
+
+// IsActive tells whether power measurement is active
+func (r *PowerReadingResponse) IsActive() bool {
+	return r.State&0x40 != 0
+}
+
+// Time returns the time of the reading
+func (r *PowerReadingResponse) Time() time.Time {
+	return selTime(r.Timestamp)
+}
+
+// StatisticsPeriod returns the period the minimum, maximum and average are over
+func (r *PowerReadingResponse) StatisticsPeriod() time.Duration {
+	return time.Duration(r.Period) * time.Millisecond
+}
+
+// DCMIPowerLimit is the power limit of Get and Set Power Limit
+type DCMIPowerLimit struct {
+	ExceptionAction uint8  // DCMIException* or OEM action
+	Limit           uint16 // watts
+	CorrectionTime  uint32 // milliseconds
+	_               uint16
+	SamplingPeriod  uint16 // statistics sampling period in seconds
+}
+
+// PowerLimitRequest per section 6.6.2
+type PowerLimitRequest struct {
+	DCMIGroup
+	_ uint16
+}
+
+// PowerLimitResponse per section 6.6.2
+type PowerLimitResponse struct {
+	CompletionCode
+	DCMIGroup
+	_ uint16
+	DCMIPowerLimit
+}
+
+// SetPowerLimitRequest per section 6.6.3
+type SetPowerLimitRequest struct {
+	DCMIGroup
+	_ [3]uint8
+	DCMIPowerLimit
+}
+
+// ActivatePowerLimitRequest per section 6.6.4
+type ActivatePowerLimitRequest struct {
+	DCMIGroup
+	Activate uint8
+	_        uint16
+}
+
+// DCMIStringRequest is the Get Asset Tag and Get Management Controller Identifier String request
+// per section 6.4.2 and 6.4.6
+type DCMIStringRequest struct {
+	DCMIGroup
+	Offset uint8
+	Length uint8
+}
+
+// DCMIStringResponse is the Get/Set Asset Tag and Get/Set Management Controller Identifier String response,
+// Data is empty for the set commands
+type DCMIStringResponse struct {
+	CompletionCode
+	DCMIGroup
+	Total uint8 // length of the whole string
+	Data  []uint8
+}
+
+// SetDCMIStringRequest is the Set Asset Tag and Set Management Controller Identifier String request
+// per section 6.4.3 and 6.4.7
+type SetDCMIStringRequest struct {
+	DCMIGroup
+	Offset uint8
+	Data   []uint8
+}
+
+// MarshalBinary implementation to handle variable length Data
+func (r *DCMIStringResponse) MarshalBinary() ([]byte, error) {
+	return append([]byte{uint8(r.CompletionCode), uint8(r.DCMIGroup), r.Total}, r.Data...), nil
+}
+
+// UnmarshalBinary implementation to handle variable length Data
+func (r *DCMIStringResponse) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 3 {
+		return ErrShortPacket
+	}
+	r.CompletionCode = CompletionCode(buf[0])
+	r.DCMIGroup = DCMIGroup(buf[1])
+	r.Total = buf[2]
+	r.Data = buf[3:]
+	return nil
+}
+
+// MarshalBinary implementation to handle variable length Data
+func (r *SetDCMIStringRequest) MarshalBinary() ([]byte, error) {
+	return append([]byte{uint8(r.DCMIGroup), r.Offset, uint8(len(r.Data))}, r.Data...), nil
+}
+
+// UnmarshalBinary implementation to handle variable length Data
+func (r *SetDCMIStringRequest) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 3 || len(buf) < 3+int(buf[2]) {
+		return ErrShortPacket
+	}
+	r.DCMIGroup = DCMIGroup(buf[0])
+	r.Offset = buf[1]
+	r.Data = buf[3 : 3+buf[2]]
+	return nil
+}
+
+// DCMISensorRequest is the Get DCMI Sensor Info and Get Temperature Readings request per section 6.5.2 and 6.7.3,
+// EntityInstance 0 selecting all the instances from the 1-based InstanceStart
+type DCMISensorRequest struct {
+	DCMIGroup
+	SensorType     uint8
+	EntityID       uint8
+	EntityInstance uint8
+	InstanceStart  uint8
+}
+
+// DCMISensorInfoResponse per section 6.5.2
+type DCMISensorInfoResponse struct {
+	CompletionCode
+	DCMIGroup
+	Total     uint8 // number of instances of the entity
+	RecordIDs []uint16
+}
+
+// MarshalBinary implementation to handle variable length RecordIDs
+func (r *DCMISensorInfoResponse) MarshalBinary() ([]byte, error) {
+	buf := []byte{uint8(r.CompletionCode), uint8(r.DCMIGroup), r.Total, uint8(len(r.RecordIDs))}
+	for _, id := range r.RecordIDs {
+		buf = append(buf, uint8(id), uint8(id>>8))
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implementation to handle variable length RecordIDs
+func (r *DCMISensorInfoResponse) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 4 || len(buf) < 4+2*int(buf[3]) {
+		return ErrShortPacket
+	}
+	r.CompletionCode = CompletionCode(buf[0])
+	r.DCMIGroup = DCMIGroup(buf[1])
+	r.Total = buf[2]
+	r.RecordIDs = make([]uint16, buf[3])
+	for i := range r.RecordIDs {
+		r.RecordIDs[i] = binary.LittleEndian.Uint16(buf[4+2*i:])
+	}
+	return nil
+}
+
+// DCMITemperature is a temperature reading of an entity instance
+type DCMITemperature struct {
+	Temperature int8 // degrees Celsius
+	Instance    uint8
+}
+
+// TemperatureReadingsResponse per section 6.7.3
+type TemperatureReadingsResponse struct {
+	CompletionCode
+	DCMIGroup
+	Total    uint8 // number of instances of the entity
+	Readings []DCMITemperature
+}
+
+// MarshalBinary implementation to handle variable length Readings, temperatures being sign and magnitude
+func (r *TemperatureReadingsResponse) MarshalBinary() ([]byte, error) {
+	buf := []byte{uint8(r.CompletionCode), uint8(r.DCMIGroup), r.Total, uint8(len(r.Readings))}
+	for _, t := range r.Readings {
+		temp := uint8(t.Temperature)
+		if t.Temperature < 0 {
+			temp = 0x80 | uint8(-int(t.Temperature))&0x7f
+		}
+		buf = append(buf, temp, t.Instance)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implementation to handle variable length Readings, temperatures being sign and magnitude
+func (r *TemperatureReadingsResponse) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 4 || len(buf) < 4+2*int(buf[3]) {
+		return ErrShortPacket
+	}
+	r.CompletionCode = CompletionCode(buf[0])
+	r.DCMIGroup = DCMIGroup(buf[1])
+	r.Total = buf[2]
+	r.Readings = make([]DCMITemperature, buf[3])
+	for i := range r.Readings {
+		temp := buf[4+2*i]
+		r.Readings[i].Temperature = int8(temp & 0x7f)
+		if temp&0x80 != 0 {
+			r.Readings[i].Temperature = -int8(temp & 0x7f)
+		}
+		r.Readings[i].Instance = buf[5+2*i]
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDCMICapabilities(t *testing.T) {
+	res := &DCMICapabilitiesResponse{}
+	err := responseFromString("dc 01 05 02 00 01 05", res)
+	assert.NoError(t, err)
+	assert.Equal(t, DCMIGroupExtension, res.DCMIGroup)
+	assert.Equal(t, uint8(0x05), res.MinorVersion)
+
+	caps := &DCMISupportedCapabilities{}
+	assert.NoError(t, caps.UnmarshalBinary(res.Data))
+	assert.Equal(t, &DCMISupportedCapabilities{PowerManagement: true, InBandKCS: true, SecondaryLAN: true}, caps)
+
+	attrs := &DCMIPlatformAttributes{
+		SELEntries:       0x400,
+		SELEntireFlush:   true,
+		AssetTag:         true,
+		InletTemperature: true,
+		CPUTemperature:   true,
+	}
+	data, err := attrs.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, rawDecode("00 44 01 03 00"), data)
+	decoded := &DCMIPlatformAttributes{}
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, attrs, decoded)
+
+	// rollover with both flush modes, all identification attributes
+	decoded = &DCMIPlatformAttributes{}
+	assert.NoError(t, decoded.UnmarshalBinary(rawDecode("ff ef 07 04 0a")))
+	assert.Equal(t, &DCMIPlatformAttributes{
+		SELEntries:           0xfff,
+		SELRecordFlush:       true,
+		SELEntireFlush:       true,
+		SELRollover:          true,
+		AssetTag:             true,
+		DHCPHostName:         true,
+		GUID:                 true,
+		BaseboardTemperature: true,
+		TemperatureSampling:  10,
+	}, decoded)
+
+	stats := &DCMIPowerStatisticsAttributes{Periods: []time.Duration{30 * time.Second, 5 * time.Minute, 2 * time.Hour, 24 * time.Hour}}
+	data, err = stats.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, rawDecode("04 1e 45 82 c1"), data)
+	decodedStats := &DCMIPowerStatisticsAttributes{}
+	assert.NoError(t, decodedStats.UnmarshalBinary(data))
+	assert.Equal(t, stats, decodedStats)
+
+	// the largest unit, 90 seconds fit neither seconds nor minutes
+	period, err := dcmiPeriod(60 * time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(0x41), period)
+	_, err = dcmiPeriod(90 * time.Second)
+	assert.Equal(t, ErrDCMIValue, err)
+	_, err = dcmiPeriod(0)
+	assert.Equal(t, ErrDCMIValue, err)
+}
+
+func TestPowerReading(t *testing.T) {
+	res := &PowerReadingResponse{}
+	err := responseFromString("dc 96 00 64 00 2c 01 a0 00 00 e1 0b 5e 60 ea 00 00 40", res)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(150), res.Current)
+	assert.Equal(t, uint16(100), res.Minimum)
+	assert.Equal(t, uint16(300), res.Maximum)
+	assert.Equal(t, uint16(160), res.Average)
+	assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), res.Time())
+	assert.Equal(t, time.Minute, res.StatisticsPeriod())
+	assert.True(t, res.IsActive())
+}
+
+func TestPowerLimit(t *testing.T) {
+	req := &SetPowerLimitRequest{
+		DCMIGroup: DCMIGroupExtension,
+		DCMIPowerLimit: DCMIPowerLimit{
+			ExceptionAction: DCMIExceptionPowerOff,
+			Limit:           400,
+			CorrectionTime:  5000,
+			SamplingPeriod:  10,
+		},
+	}
+	assert.Equal(t, rawDecode("dc 00 00 00 01 90 01 88 13 00 00 00 00 0a 00"), messageDataToBytes(req))
+
+	res := &PowerLimitResponse{}
+	err := responseFromString("dc 00 00 01 90 01 88 13 00 00 00 00 0a 00", res)
+	assert.NoError(t, err)
+	assert.Equal(t, req.DCMIPowerLimit, res.DCMIPowerLimit)
+}
+
+func TestDCMIStringAndSensors(t *testing.T) {
+	req := &SetDCMIStringRequest{DCMIGroupExtension, 16, []byte("rack")}
+	data, err := req.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, rawDecode("dc 10 04 72 61 63 6b"), data)
+	decoded := &SetDCMIStringRequest{}
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, req, decoded)
+	assert.Equal(t, ErrShortPacket, decoded.UnmarshalBinary(data[:5]))
+
+	info := &DCMISensorInfoResponse{}
+	err = responseFromString("dc 03 02 01 40 02 40", info)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(3), info.Total)
+	assert.Equal(t, []uint16{0x4001, 0x4002}, info.RecordIDs)
+
+	temps := &TemperatureReadingsResponse{}
+	err = responseFromString("dc 02 02 16 01 85 02", temps)
+	assert.NoError(t, err)
+	assert.Equal(t, []DCMITemperature{{22, 1}, {-5, 2}}, temps.Readings)
+	temps.CompletionCode = CommandCompleted
+	data, err = temps.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, rawDecode("00 dc 02 02 16 01 85 02"), data)
+}
@@ -0,0 +1,169 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+// section 29.1 to 29.3, event commands on NetworkFunctionSensorEvent
+const (
+	CommandSetEventReceiver = Command(0x00)
+	CommandGetEventReceiver = Command(0x01)
+	CommandPlatformEvent    = Command(0x02)
+)
+
+// section 22.1, 22.2 and 22.8, BMC message buffer commands on NetworkFunctionApp
+const (
+	CommandSetBMCGlobalEnables    = Command(0x2e)
+	CommandGetBMCGlobalEnables    = Command(0x2f)
+	CommandReadEventMessageBuffer = Command(0x35)
+)
+
+// section 31.5, Get SEL Entry on NetworkFunctionStorge
+const CommandGetSELEntry = Command(0x43)
+
+// EventReceiverDisabled is the event receiver slave address that disables event message generation
+const EventReceiverDisabled = 0xff
+
+// BMC global enables bits
+const (
+	BMCGlobalReceiveMessageInterrupt  = 0x01
+	BMCGlobalEventBufferFullInterrupt = 0x02
+	BMCGlobalEventBuffer              = 0x04
+	BMCGlobalSystemEventLogging       = 0x08
+	BMCGlobalOEM0                     = 0x20
+	BMCGlobalOEM1                     = 0x40
+	BMCGlobalOEM2                     = 0x80
+)
+
+// ErrEventBufferEmpty is the Read Event Message Buffer completion code when there is no message
+const ErrEventBufferEmpty = CompletionCode(0x80)
+
+// Get SEL Entry record IDs
+const (
+	SELEntryFirst = 0x0000
+	SELEntryLast  = 0xffff
+)
+
+// selReadEntireRecord is the Get SEL Entry length that reads the entire record
+const selReadEntireRecord = 0xff
+
+// SetEventReceiverRequest per section 29.1
+type SetEventReceiverRequest struct {
+	Address uint8 // slave address, EventReceiverDisabled to disable event message generation
+	LUN     uint8
+}
+
+// SetEventReceiverResponse per section 29.1
+type SetEventReceiverResponse struct {
+	CompletionCode
+}
+
+// EventReceiverRequest per section 29.2
+type EventReceiverRequest struct{}
+
+// EventReceiverResponse per section 29.2
+type EventReceiverResponse struct {
+	CompletionCode
+	Address uint8
+	LUN     uint8
+}
+
+// PlatformEventRequest per section 29.3, the event message of the IPMB and LAN format,
+// which starts at EvMRev. The BMC takes the generator ID from the requester address
+type PlatformEventRequest struct {
+	Event *SELEvent
+}
+
+// PlatformEventResponse per section 29.3
+type PlatformEventResponse struct {
+	CompletionCode
+}
+
+// SetBMCGlobalEnablesRequest per section 22.1
+type SetBMCGlobalEnablesRequest struct {
+	Enables uint8 // BMCGlobal* bits
+}
+
+// SetBMCGlobalEnablesResponse per section 22.1
+type SetBMCGlobalEnablesResponse struct {
+	CompletionCode
+}
+
+// BMCGlobalEnablesRequest per section 22.2
+type BMCGlobalEnablesRequest struct{}
+
+// BMCGlobalEnablesResponse per section 22.2
+type BMCGlobalEnablesResponse struct {
+	CompletionCode
+	Enables uint8 // BMCGlobal* bits
+}
+
+// ReadEventMessageBufferRequest per section 22.8
+type ReadEventMessageBufferRequest struct{}
+
+// ReadEventMessageBufferResponse per section 22.8, the message being a SEL record
+type ReadEventMessageBufferResponse struct {
+	CompletionCode
+	Data [selRecordSize]uint8
+}
+
+// SELEntryRequest per section 31.5
+type SELEntryRequest struct {
+	ReservationID uint16 // only needed for partial reads
+	RecordID      uint16
+	Offset        uint8
+	Length        uint8 // selReadEntireRecord for the entire record
+}
+
+// SELEntryResponse per section 31.5
+type SELEntryResponse struct {
+	CompletionCode
+	NextRecordID uint16
+	Data         []uint8
+}
+
+// MarshalBinary implementation to encode the event message
+func (r *PlatformEventRequest) MarshalBinary() ([]byte, error) {
+	return marshalEventMessage(r.Event), nil
+}
+
+// UnmarshalBinary implementation to decode the event message
+func (r *PlatformEventRequest) UnmarshalBinary(buf []byte) error {
+	if len(buf) < eventMessageSize {
+		return ErrShortPacket
+	}
+	if len(buf) > eventMessageSize {
+		return ErrLongPacket // the system interface format, with the generator ID
+	}
+	r.Event = unmarshalEventMessage(buf)
+	return nil
+}
+
+// MarshalBinary implementation to handle variable length Data
+func (r *SELEntryResponse) MarshalBinary() ([]byte, error) {
+	buf := []byte{uint8(r.CompletionCode), uint8(r.NextRecordID), uint8(r.NextRecordID >> 8)}
+	return append(buf, r.Data...), nil
+}
+
+// UnmarshalBinary implementation to handle variable length Data
+func (r *SELEntryResponse) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 3 {
+		return ErrShortPacket
+	}
+	r.CompletionCode = CompletionCode(buf[0])
+	r.NextRecordID = uint16(buf[1]) | uint16(buf[2])<<8
+	r.Data = buf[3:]
+	return nil
+}
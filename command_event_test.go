@@ -0,0 +1,69 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlatformEventRequest(t *testing.T) {
+	req := &PlatformEventRequest{&SELEvent{
+		RecordType:   SELRecordSystemEvent,
+		EvMRev:       SELEvMRev,
+		SensorType:   SDR_SENSOR_TYPECODES_TEMPERATURE,
+		SensorNumber: 0x30,
+		EventType:    SENSOR_READTYPE_THREADHOLD,
+		EventData:    [3]uint8{0x59, 0x40, 0x50},
+	}}
+
+	data, err := req.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, rawDecode("04 01 30 01 59 40 50"), data)
+
+	decoded := &PlatformEventRequest{}
+	err = decoded.UnmarshalBinary(data)
+	assert.NoError(t, err)
+	assert.Equal(t, req, decoded)
+
+	err = decoded.UnmarshalBinary(data[:6])
+	assert.Equal(t, ErrShortPacket, err)
+
+	// the system interface format is not accepted
+	err = decoded.UnmarshalBinary(append([]byte{0x41}, data...))
+	assert.Equal(t, ErrLongPacket, err)
+}
+
+func TestSELEntryResponse(t *testing.T) {
+	res := &SELEntryResponse{}
+	err := responseFromString("ff ff 02 00 02 00 00 00 00 20 00 04 01 30 01 59 40 50", res)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(SELEntryLast), res.NextRecordID)
+
+	event := &SELEvent{}
+	err = event.UnmarshalBinary(res.Data)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(2), event.RecordID)
+	assert.Equal(t, uint16(bmcSlaveAddr), event.GeneratorID)
+	assert.True(t, event.IsRelativeTime())
+
+	buffer := &ReadEventMessageBufferResponse{}
+	err = responseFromString("02 00 02 00 00 00 00 20 00 04 01 30 01 59 40 50", buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, res.Data, buffer.Data[:])
+}
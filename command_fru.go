@@ -0,0 +1,134 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+const (
+	CommandGetFRUInventoryAreaInfo = Command(0x10)
+	CommandReadFRUData             = Command(0x11)
+	CommandMasterWriteRead         = Command(0x52)
+)
+
+const bmcSlaveAddr = 0x20
+
+// FRUInventoryAreaInfoRequest per section 34.1
+type FRUInventoryAreaInfoRequest struct {
+	FRUDeviceID uint8
+}
+
+// FRUInventoryAreaInfoResponse per section 34.1
+type FRUInventoryAreaInfoResponse struct {
+	CompletionCode
+	AreaSize   uint16
+	AccessType uint8
+}
+
+// ReadFRUDataRequest per section 34.2
+type ReadFRUDataRequest struct {
+	FRUDeviceID uint8
+	Offset      uint16
+	CountToRead uint8
+}
+
+// ReadFRUDataResponse per section 34.2
+type ReadFRUDataResponse struct {
+	CompletionCode
+	CountReturned uint8
+	Data          []byte
+}
+
+// MasterWriteReadRequest per section 22.11
+type MasterWriteReadRequest struct {
+	BusID     uint8 // [7:4] channel, [3:1] bus ID, [0] bus type (1 = private)
+	SlaveAddr uint8
+	ReadCount uint8
+	Data      []byte
+}
+
+// MasterWriteReadResponse per section 22.11
+type MasterWriteReadResponse struct {
+	CompletionCode
+	Data []byte
+}
+
+// WordAccess reports whether the FRU device is accessed by words rather than bytes
+func (r *FRUInventoryAreaInfoResponse) WordAccess() bool {
+	return r.AccessType&0x01 != 0
+}
+
+// MarshalBinary implementation to handle variable length Data
+func (r *ReadFRUDataResponse) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 2+len(r.Data))
+	buf[0] = byte(r.CompletionCode)
+	buf[1] = r.CountReturned
+	copy(buf[2:], r.Data)
+	return buf, nil
+}
+
+// UnmarshalBinary implementation to handle variable length Data
+func (r *ReadFRUDataResponse) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 2 {
+		return ErrShortPacket
+	}
+	r.CompletionCode = CompletionCode(buf[0])
+	r.CountReturned = buf[1]
+	r.Data = buf[2:]
+	if len(r.Data) < int(r.CountReturned) {
+		return ErrShortPacket
+	}
+	r.Data = r.Data[:r.CountReturned]
+	return nil
+}
+
+// MarshalBinary implementation to handle variable length Data
+func (r *MasterWriteReadRequest) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 3+len(r.Data))
+	buf[0] = r.BusID
+	buf[1] = r.SlaveAddr
+	buf[2] = r.ReadCount
+	copy(buf[3:], r.Data)
+	return buf, nil
+}
+
+// UnmarshalBinary implementation to handle variable length Data
+func (r *MasterWriteReadRequest) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 3 {
+		return ErrShortPacket
+	}
+	r.BusID = buf[0]
+	r.SlaveAddr = buf[1]
+	r.ReadCount = buf[2]
+	r.Data = buf[3:]
+	return nil
+}
+
+// MarshalBinary implementation to handle variable length Data
+func (r *MasterWriteReadResponse) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 1+len(r.Data))
+	buf[0] = byte(r.CompletionCode)
+	copy(buf[1:], r.Data)
+	return buf, nil
+}
+
+// UnmarshalBinary implementation to handle variable length Data
+func (r *MasterWriteReadResponse) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1 {
+		return ErrShortPacket
+	}
+	r.CompletionCode = CompletionCode(buf[0])
+	r.Data = buf[1:]
+	return nil
+}
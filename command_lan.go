@@ -0,0 +1,928 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// section 23.1 and 23.2, LAN configuration commands on NetworkFunctionTransport
+const (
+	CommandSetLANConfig = Command(0x01)
+	CommandGetLANConfig = Command(0x02)
+)
+
+// LAN configuration parameters per section 23.2 - table 23-4
+const (
+	LANParamSetInProgress         = 0
+	LANParamAuthTypeSupport       = 1
+	LANParamAuthTypeEnables       = 2
+	LANParamIPAddress             = 3
+	LANParamIPAddressSource       = 4
+	LANParamMACAddress            = 5
+	LANParamSubnetMask            = 6
+	LANParamARPControl            = 10
+	LANParamDefaultGateway        = 12
+	LANParamDefaultGatewayMAC     = 13
+	LANParamBackupGateway         = 14
+	LANParamCommunityString       = 16
+	LANParamAlertDestinationCount = 17
+	LANParamAlertDestinationType  = 18
+	LANParamAlertDestinationAddr  = 19
+	LANParamVLANID                = 20
+	LANParamVLANPriority          = 21
+	LANParamCipherSuiteEntryCount = 22
+	LANParamCipherSuiteEntries    = 23
+	LANParamCipherSuitePrivLevels = 24
+	LANParamIPv6Support           = 50
+	LANParamIPv6AddressingEnables = 51
+	LANParamIPv6Status            = 55
+	LANParamIPv6StaticAddress     = 56
+	LANParamIPv6DynamicAddress    = 59
+	LANParamIPv6RouterConfig      = 64
+	LANParamIPv6StaticRouterIP    = 65
+)
+
+// Get/Set LAN Configuration Parameters completion codes
+const (
+	ErrLANParamNotSupported = CompletionCode(0x80)
+	ErrLANSetInProgress     = ErrSetInProgress
+	ErrLANParamReadOnly     = CompletionCode(0x82)
+	ErrLANParamWriteOnly    = CompletionCode(0x83)
+)
+
+// ErrLANParamValue is returned when a LAN parameter value cannot be encoded
+var ErrLANParamValue = errors.New("value out of range for the LAN parameter")
+
+// lanCommunityStringSize is the size of the PET community string
+const lanCommunityStringSize = 18
+
+// lanCipherSuiteEntriesMax is the number of cipher suite entries of a LAN channel
+const lanCipherSuiteEntriesMax = 16
+
+// SetLANConfigRequest per section 23.1
+type SetLANConfigRequest struct {
+	Channel uint8
+	Param   uint8
+	Data    []uint8
+}
+
+// SetLANConfigResponse per section 23.1
+type SetLANConfigResponse struct {
+	CompletionCode
+}
+
+// LANConfigRequest per section 23.2, bit 7 of Channel asks for the parameter revision only
+type LANConfigRequest struct {
+	Channel uint8
+	Param   uint8
+	Set     uint8
+	Block   uint8
+}
+
+// LANConfigResponse per section 23.2
+type LANConfigResponse struct {
+	CompletionCode
+	Revision uint8
+	Data     []uint8
+}
+
+// MarshalBinary implementation to handle variable length Data
+func (r *SetLANConfigRequest) MarshalBinary() ([]byte, error) {
+	return append([]byte{r.Channel, r.Param}, r.Data...), nil
+}
+
+// UnmarshalBinary implementation to handle variable length Data
+func (r *SetLANConfigRequest) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 3 {
+		return ErrShortPacket
+	}
+	r.Channel = buf[0]
+	r.Param = buf[1]
+	r.Data = buf[2:]
+	return nil
+}
+
+// MarshalBinary implementation to handle variable length Data
+func (r *LANConfigResponse) MarshalBinary() ([]byte, error) {
+	return append([]byte{byte(r.CompletionCode), r.Revision}, r.Data...), nil
+}
+
+// UnmarshalBinary implementation to handle variable length Data
+func (r *LANConfigResponse) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 2 {
+		return ErrShortPacket
+	}
+	r.CompletionCode = CompletionCode(buf[0])
+	r.Revision = buf[1]
+	r.Data = buf[2:]
+	return nil
+}
+
+// LANConfigParam is a typed LAN configuration parameter, the binary form
+// being the parameter data of the Get/Set LAN Configuration Parameters commands
+type LANConfigParam interface {
+	Param() uint8
+	MarshalBinary() ([]byte, error)
+}
+
+// LANConfigParamDecoder is a pointer to a LAN configuration parameter,
+// which the parameter data can be read into
+type LANConfigParamDecoder interface {
+	LANConfigParam
+	UnmarshalBinary([]byte) error
+}
+
+// lanConfigSetParam is implemented by parameters that have a set selector
+type lanConfigSetParam interface {
+	setSelector() uint8
+}
+
+// LANIPSource is the IP address source, parameter 4
+type LANIPSource uint8
+
+// LANIPSource values
+const (
+	LANIPSourceUnspecified = LANIPSource(0)
+	LANIPSourceStatic      = LANIPSource(1)
+	LANIPSourceDHCP        = LANIPSource(2)
+	LANIPSourceBIOS        = LANIPSource(3) // loaded by the BIOS or system software
+	LANIPSourceOther       = LANIPSource(4)
+)
+
+var lanIPSourceNames = map[LANIPSource]string{
+	LANIPSourceUnspecified: "unspecified",
+	LANIPSourceStatic:      "static",
+	LANIPSourceDHCP:        "DHCP",
+	LANIPSourceBIOS:        "BIOS",
+	LANIPSourceOther:       "other",
+}
+
+func (s LANIPSource) String() string {
+	if name, ok := lanIPSourceNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("reserved (0x%02x)", uint8(s))
+}
+
+// Param is the parameter selector
+func (LANIPSource) Param() uint8 { return LANParamIPAddressSource }
+
+// MarshalBinary encodes the parameter data
+func (s LANIPSource) MarshalBinary() ([]byte, error) {
+	return []byte{uint8(s) & 0x0f}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (s *LANIPSource) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1 {
+		return ErrShortPacket
+	}
+	*s = LANIPSource(buf[0] & 0x0f)
+	return nil
+}
+
+func marshalIPv4(ip net.IP) ([]byte, error) {
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return nil, ErrLANParamValue
+	}
+	return []byte(ip4), nil
+}
+
+func unmarshalIPv4(buf []byte) (net.IP, error) {
+	if len(buf) < net.IPv4len {
+		return nil, ErrShortPacket
+	}
+	return net.IPv4(buf[0], buf[1], buf[2], buf[3]), nil
+}
+
+func marshalMAC(mac net.HardwareAddr) ([]byte, error) {
+	if len(mac) != 6 {
+		return nil, ErrLANParamValue
+	}
+	return []byte(mac), nil
+}
+
+func unmarshalMAC(buf []byte) (net.HardwareAddr, error) {
+	if len(buf) < 6 {
+		return nil, ErrShortPacket
+	}
+	return net.HardwareAddr(append([]byte(nil), buf[:6]...)), nil
+}
+
+// LANIPAddress is the station IP address, parameter 3
+type LANIPAddress struct {
+	IP net.IP
+}
+
+// Param is the parameter selector
+func (*LANIPAddress) Param() uint8 { return LANParamIPAddress }
+
+// MarshalBinary encodes the parameter data
+func (p *LANIPAddress) MarshalBinary() ([]byte, error) { return marshalIPv4(p.IP) }
+
+// UnmarshalBinary decodes the parameter data
+func (p *LANIPAddress) UnmarshalBinary(buf []byte) (err error) {
+	p.IP, err = unmarshalIPv4(buf)
+	return err
+}
+
+// LANSubnetMask is the subnet mask, parameter 6
+type LANSubnetMask struct {
+	Mask net.IPMask
+}
+
+// Param is the parameter selector
+func (*LANSubnetMask) Param() uint8 { return LANParamSubnetMask }
+
+// MarshalBinary encodes the parameter data
+func (p *LANSubnetMask) MarshalBinary() ([]byte, error) {
+	if len(p.Mask) != net.IPv4len {
+		return nil, ErrLANParamValue
+	}
+	return []byte(p.Mask), nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (p *LANSubnetMask) UnmarshalBinary(buf []byte) error {
+	if len(buf) < net.IPv4len {
+		return ErrShortPacket
+	}
+	p.Mask = net.IPv4Mask(buf[0], buf[1], buf[2], buf[3])
+	return nil
+}
+
+// LANMACAddress is the MAC address of the BMC, parameter 5
+type LANMACAddress struct {
+	MAC net.HardwareAddr
+}
+
+// Param is the parameter selector
+func (*LANMACAddress) Param() uint8 { return LANParamMACAddress }
+
+// MarshalBinary encodes the parameter data
+func (p *LANMACAddress) MarshalBinary() ([]byte, error) { return marshalMAC(p.MAC) }
+
+// UnmarshalBinary decodes the parameter data
+func (p *LANMACAddress) UnmarshalBinary(buf []byte) (err error) {
+	p.MAC, err = unmarshalMAC(buf)
+	return err
+}
+
+// LANDefaultGateway is the default gateway IP address, parameter 12
+type LANDefaultGateway struct {
+	IP net.IP
+}
+
+// Param is the parameter selector
+func (*LANDefaultGateway) Param() uint8 { return LANParamDefaultGateway }
+
+// MarshalBinary encodes the parameter data
+func (p *LANDefaultGateway) MarshalBinary() ([]byte, error) { return marshalIPv4(p.IP) }
+
+// UnmarshalBinary decodes the parameter data
+func (p *LANDefaultGateway) UnmarshalBinary(buf []byte) (err error) {
+	p.IP, err = unmarshalIPv4(buf)
+	return err
+}
+
+// LANDefaultGatewayMAC is the default gateway MAC address, parameter 13
+type LANDefaultGatewayMAC struct {
+	MAC net.HardwareAddr
+}
+
+// Param is the parameter selector
+func (*LANDefaultGatewayMAC) Param() uint8 { return LANParamDefaultGatewayMAC }
+
+// MarshalBinary encodes the parameter data
+func (p *LANDefaultGatewayMAC) MarshalBinary() ([]byte, error) { return marshalMAC(p.MAC) }
+
+// UnmarshalBinary decodes the parameter data
+func (p *LANDefaultGatewayMAC) UnmarshalBinary(buf []byte) (err error) {
+	p.MAC, err = unmarshalMAC(buf)
+	return err
+}
+
+// LANBackupGateway is the backup gateway IP address, parameter 14
+type LANBackupGateway struct {
+	IP net.IP
+}
+
+// Param is the parameter selector
+func (*LANBackupGateway) Param() uint8 { return LANParamBackupGateway }
+
+// MarshalBinary encodes the parameter data
+func (p *LANBackupGateway) MarshalBinary() ([]byte, error) { return marshalIPv4(p.IP) }
+
+// UnmarshalBinary decodes the parameter data
+func (p *LANBackupGateway) UnmarshalBinary(buf []byte) (err error) {
+	p.IP, err = unmarshalIPv4(buf)
+	return err
+}
+
+// LANAuthTypeSupport is the read only mask of the supported AuthType bits, parameter 1
+type LANAuthTypeSupport uint8
+
+// Param is the parameter selector
+func (LANAuthTypeSupport) Param() uint8 { return LANParamAuthTypeSupport }
+
+// MarshalBinary encodes the parameter data
+func (s LANAuthTypeSupport) MarshalBinary() ([]byte, error) {
+	return []byte{uint8(s) & 0x3f}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (s *LANAuthTypeSupport) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1 {
+		return ErrShortPacket
+	}
+	*s = LANAuthTypeSupport(buf[0] & 0x3f)
+	return nil
+}
+
+// LANAuthTypeEnables are the masks of the AuthType bits enabled for each
+// requested maximum privilege level, parameter 2
+type LANAuthTypeEnables struct {
+	Callback uint8
+	User     uint8
+	Operator uint8
+	Admin    uint8
+	OEM      uint8
+}
+
+// Param is the parameter selector
+func (*LANAuthTypeEnables) Param() uint8 { return LANParamAuthTypeEnables }
+
+// MarshalBinary encodes the parameter data
+func (p *LANAuthTypeEnables) MarshalBinary() ([]byte, error) {
+	return []byte{
+		p.Callback & 0x3f,
+		p.User & 0x3f,
+		p.Operator & 0x3f,
+		p.Admin & 0x3f,
+		p.OEM & 0x3f,
+	}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (p *LANAuthTypeEnables) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 5 {
+		return ErrShortPacket
+	}
+	p.Callback = buf[0] & 0x3f
+	p.User = buf[1] & 0x3f
+	p.Operator = buf[2] & 0x3f
+	p.Admin = buf[3] & 0x3f
+	p.OEM = buf[4] & 0x3f
+	return nil
+}
+
+// LANCommunityString is the community string of PET traps, parameter 16
+type LANCommunityString string
+
+// Param is the parameter selector
+func (LANCommunityString) Param() uint8 { return LANParamCommunityString }
+
+// MarshalBinary encodes the parameter data
+func (s LANCommunityString) MarshalBinary() ([]byte, error) {
+	if len(s) > lanCommunityStringSize {
+		return nil, ErrLANParamValue
+	}
+	buf := make([]byte, lanCommunityStringSize)
+	copy(buf, s)
+	return buf, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (s *LANCommunityString) UnmarshalBinary(buf []byte) error {
+	if len(buf) < lanCommunityStringSize {
+		return ErrShortPacket
+	}
+	n := 0
+	for n < lanCommunityStringSize && buf[n] != 0 {
+		n++
+	}
+	*s = LANCommunityString(buf[:n])
+	return nil
+}
+
+// LANAlertDestinationCount is the number of non-volatile alert destinations, parameter 17,
+// destination set 0 being the volatile destination
+type LANAlertDestinationCount uint8
+
+// Param is the parameter selector
+func (LANAlertDestinationCount) Param() uint8 { return LANParamAlertDestinationCount }
+
+// MarshalBinary encodes the parameter data
+func (n LANAlertDestinationCount) MarshalBinary() ([]byte, error) {
+	if n > 0x0f {
+		return nil, ErrLANParamValue
+	}
+	return []byte{uint8(n)}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (n *LANAlertDestinationCount) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1 {
+		return ErrShortPacket
+	}
+	*n = LANAlertDestinationCount(buf[0] & 0x0f)
+	return nil
+}
+
+// Alert destination types
+const (
+	LANAlertPET  = 0x0
+	LANAlertOEM1 = 0x6
+	LANAlertOEM2 = 0x7
+)
+
+// lanAlertTimeoutMax is the longest alert acknowledge timeout
+const lanAlertTimeoutMax = 256 * time.Second
+
+// LANAlertDestinationType is the type of an alert destination set, parameter 18
+type LANAlertDestinationType struct {
+	Set         uint8
+	Type        uint8 // LANAlert*
+	AckRequired bool
+	Timeout     time.Duration // acknowledge timeout and retry interval, 1 to 256 seconds
+	Retries     uint8
+}
+
+func (p *LANAlertDestinationType) setSelector() uint8 { return p.Set }
+
+// Param is the parameter selector
+func (*LANAlertDestinationType) Param() uint8 { return LANParamAlertDestinationType }
+
+// MarshalBinary encodes the parameter data
+func (p *LANAlertDestinationType) MarshalBinary() ([]byte, error) {
+	if p.Set > 0x0f || p.Type > 0x07 || p.Retries > 0x07 ||
+		p.Timeout < time.Second || p.Timeout > lanAlertTimeoutMax {
+		return nil, ErrLANParamValue
+	}
+	return []byte{
+		p.Set,
+		boolBit(p.AckRequired, 0x80) | p.Type,
+		uint8(p.Timeout/time.Second - 1), // 0-based
+		p.Retries,
+	}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (p *LANAlertDestinationType) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 4 {
+		return ErrShortPacket
+	}
+	p.Set = buf[0] & 0x0f
+	p.AckRequired = buf[1]&0x80 != 0
+	p.Type = buf[1] & 0x07
+	p.Timeout = (time.Duration(buf[2]) + 1) * time.Second
+	p.Retries = buf[3] & 0x07
+	return nil
+}
+
+// Alert destination address formats
+const (
+	lanAlertAddrIPv4 = 0x0 // IPv4 address followed by a MAC address
+	lanAlertAddrIPv6 = 0x1
+)
+
+// sizes of the alert destination addresses parameter data
+const (
+	lanAlertAddrIPv4Size = 13
+	lanAlertAddrIPv6Size = 18
+)
+
+// LANAlertDestinationAddr is the address of an alert destination set, parameter 19.
+// An IPv6 IP selects the IPv6 address format, which has no MAC and gateway
+type LANAlertDestinationAddr struct {
+	Set           uint8
+	BackupGateway bool // use the backup gateway rather than the default gateway
+	IP            net.IP
+	MAC           net.HardwareAddr
+}
+
+func (p *LANAlertDestinationAddr) setSelector() uint8 { return p.Set }
+
+// Param is the parameter selector
+func (*LANAlertDestinationAddr) Param() uint8 { return LANParamAlertDestinationAddr }
+
+// MarshalBinary encodes the parameter data
+func (p *LANAlertDestinationAddr) MarshalBinary() ([]byte, error) {
+	if p.Set > 0x0f {
+		return nil, ErrLANParamValue
+	}
+
+	if p.IP.To4() == nil {
+		ip := p.IP.To16()
+		if ip == nil {
+			return nil, ErrLANParamValue
+		}
+		return append([]byte{p.Set, lanAlertAddrIPv6 << 4}, ip...), nil
+	}
+
+	mac := p.MAC
+	if mac == nil {
+		mac = make(net.HardwareAddr, 6)
+	}
+	macData, err := marshalMAC(mac)
+	if err != nil {
+		return nil, err
+	}
+	ip, _ := marshalIPv4(p.IP)
+	buf := []byte{p.Set, lanAlertAddrIPv4 << 4, boolBit(p.BackupGateway, 0x01)}
+	return append(append(buf, ip...), macData...), nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (p *LANAlertDestinationAddr) UnmarshalBinary(buf []byte) (err error) {
+	if len(buf) < 2 {
+		return ErrShortPacket
+	}
+	p.Set = buf[0] & 0x0f
+
+	switch buf[1] >> 4 {
+	case lanAlertAddrIPv4:
+		if len(buf) < lanAlertAddrIPv4Size {
+			return ErrShortPacket
+		}
+		p.BackupGateway = buf[2]&0x01 != 0
+		if p.IP, err = unmarshalIPv4(buf[3:]); err != nil {
+			return err
+		}
+		p.MAC, err = unmarshalMAC(buf[7:])
+		return err
+	case lanAlertAddrIPv6:
+		if len(buf) < lanAlertAddrIPv6Size {
+			return ErrShortPacket
+		}
+		p.BackupGateway = false
+		p.IP = append(net.IP(nil), buf[2:18]...)
+		p.MAC = nil
+		return nil
+	default:
+		return ErrLANParamValue
+	}
+}
+
+// LANARPControl is the BMC-generated ARP control, parameter 10
+type LANARPControl struct {
+	Responses  bool // BMC answers ARP requests
+	Gratuitous bool // BMC sends gratuitous ARPs
+}
+
+// Param is the parameter selector
+func (*LANARPControl) Param() uint8 { return LANParamARPControl }
+
+// MarshalBinary encodes the parameter data
+func (p *LANARPControl) MarshalBinary() ([]byte, error) {
+	return []byte{boolBit(p.Responses, 0x02) | boolBit(p.Gratuitous, 0x01)}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (p *LANARPControl) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1 {
+		return ErrShortPacket
+	}
+	p.Responses = buf[0]&0x02 != 0
+	p.Gratuitous = buf[0]&0x01 != 0
+	return nil
+}
+
+// LANVLAN is the 802.1q VLAN ID, parameter 20
+type LANVLAN struct {
+	Enabled bool
+	ID      uint16 // 12 bits
+}
+
+// Param is the parameter selector
+func (*LANVLAN) Param() uint8 { return LANParamVLANID }
+
+// MarshalBinary encodes the parameter data
+func (p *LANVLAN) MarshalBinary() ([]byte, error) {
+	if p.ID > 0x0fff {
+		return nil, ErrLANParamValue
+	}
+	return []byte{uint8(p.ID), boolBit(p.Enabled, 0x80) | uint8(p.ID>>8)}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (p *LANVLAN) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 2 {
+		return ErrShortPacket
+	}
+	p.Enabled = buf[1]&0x80 != 0
+	p.ID = uint16(buf[1]&0x0f)<<8 | uint16(buf[0])
+	return nil
+}
+
+// LANVLANPriority is the 802.1q VLAN priority, parameter 21
+type LANVLANPriority uint8
+
+// Param is the parameter selector
+func (LANVLANPriority) Param() uint8 { return LANParamVLANPriority }
+
+// MarshalBinary encodes the parameter data
+func (p LANVLANPriority) MarshalBinary() ([]byte, error) {
+	if p > 7 {
+		return nil, ErrLANParamValue
+	}
+	return []byte{uint8(p)}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (p *LANVLANPriority) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1 {
+		return ErrShortPacket
+	}
+	*p = LANVLANPriority(buf[0] & 0x07)
+	return nil
+}
+
+// LANCipherSuiteEntries are the IDs of the cipher suites supported by the channel,
+// in the order of the LANCipherSuitePrivLevels entries, parameters 22 and 23
+type LANCipherSuiteEntries []uint8
+
+// Param is the parameter selector
+func (LANCipherSuiteEntries) Param() uint8 { return LANParamCipherSuiteEntries }
+
+// MarshalBinary encodes the parameter data
+func (e LANCipherSuiteEntries) MarshalBinary() ([]byte, error) {
+	if len(e) > lanCipherSuiteEntriesMax {
+		return nil, ErrLANParamValue
+	}
+	buf := make([]byte, 1+len(e))
+	copy(buf[1:], e)
+	return buf, nil
+}
+
+// UnmarshalBinary decodes the parameter data, the BMC returns the 16
+// entries, of which only the entry count of parameter 22 are valid
+func (e *LANCipherSuiteEntries) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1 {
+		return ErrShortPacket
+	}
+	*e = append(LANCipherSuiteEntries(nil), buf[1:]...)
+	return nil
+}
+
+// LANCipherSuitePrivLevels are the maximum privilege levels of the cipher
+// suite entries, PrivLevelNone for an unused entry, parameter 24
+type LANCipherSuitePrivLevels [lanCipherSuiteEntriesMax]uint8
+
+// Param is the parameter selector
+func (*LANCipherSuitePrivLevels) Param() uint8 { return LANParamCipherSuitePrivLevels }
+
+// MarshalBinary encodes the parameter data
+func (l *LANCipherSuitePrivLevels) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 1+len(l)/2)
+	for i, priv := range l {
+		buf[1+i/2] |= (priv & 0x0f) << (4 * uint(i%2))
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (l *LANCipherSuitePrivLevels) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1+len(l)/2 {
+		return ErrShortPacket
+	}
+	for i := range l {
+		l[i] = buf[1+i/2] >> (4 * uint(i%2)) & 0x0f
+	}
+	return nil
+}
+
+// LANIPv6Support bits, parameter 50
+const (
+	LANIPv6SupportIPv6Only  = 0x01
+	LANIPv6SupportDualStack = 0x02
+	LANIPv6SupportAlerting  = 0x04 // IPv6 alert destinations
+)
+
+// LANIPv6Support is the read only IPv6 support of the channel, parameter 50
+type LANIPv6Support uint8
+
+// Param is the parameter selector
+func (LANIPv6Support) Param() uint8 { return LANParamIPv6Support }
+
+// MarshalBinary encodes the parameter data
+func (s LANIPv6Support) MarshalBinary() ([]byte, error) {
+	return []byte{uint8(s)}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (s *LANIPv6Support) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1 {
+		return ErrShortPacket
+	}
+	*s = LANIPv6Support(buf[0] & 0x07)
+	return nil
+}
+
+// LANIPAddressing enables IPv4 and IPv6 addressing, parameter 51
+type LANIPAddressing uint8
+
+// LANIPAddressing values
+const (
+	LANIPv4Only    = LANIPAddressing(0)
+	LANIPv6Only    = LANIPAddressing(1)
+	LANIPv4AndIPv6 = LANIPAddressing(2)
+)
+
+// Param is the parameter selector
+func (LANIPAddressing) Param() uint8 { return LANParamIPv6AddressingEnables }
+
+// MarshalBinary encodes the parameter data
+func (a LANIPAddressing) MarshalBinary() ([]byte, error) {
+	if a > LANIPv4AndIPv6 {
+		return nil, ErrLANParamValue
+	}
+	return []byte{uint8(a)}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (a *LANIPAddressing) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1 {
+		return ErrShortPacket
+	}
+	*a = LANIPAddressing(buf[0])
+	return nil
+}
+
+// LANIPv6Status is the read only IPv6 address capacity of the channel, parameter 55
+type LANIPv6Status struct {
+	StaticAddresses  uint8 // number of static address sets
+	DynamicAddresses uint8 // number of dynamic address sets
+	DHCPv6           bool
+	SLAAC            bool
+}
+
+// Param is the parameter selector
+func (*LANIPv6Status) Param() uint8 { return LANParamIPv6Status }
+
+// MarshalBinary encodes the parameter data
+func (p *LANIPv6Status) MarshalBinary() ([]byte, error) {
+	return []byte{
+		p.StaticAddresses,
+		p.DynamicAddresses,
+		boolBit(p.SLAAC, 0x02) | boolBit(p.DHCPv6, 0x01),
+	}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (p *LANIPv6Status) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 3 {
+		return ErrShortPacket
+	}
+	p.StaticAddresses = buf[0]
+	p.DynamicAddresses = buf[1]
+	p.SLAAC = buf[2]&0x02 != 0
+	p.DHCPv6 = buf[2]&0x01 != 0
+	return nil
+}
+
+// IPv6 address sources
+const (
+	LANIPv6SourceStatic = 0
+	LANIPv6SourceSLAAC  = 1
+	LANIPv6SourceDHCPv6 = 2
+)
+
+// IPv6 address status
+const (
+	LANIPv6StatusActive     = 0
+	LANIPv6StatusDisabled   = 1
+	LANIPv6StatusPending    = 2
+	LANIPv6StatusFailed     = 3
+	LANIPv6StatusDeprecated = 4
+	LANIPv6StatusInvalid    = 5
+)
+
+// lanIPv6AddressSize is the size of the IPv6 address parameters data
+const lanIPv6AddressSize = 20
+
+// LANIPv6Address is an IPv6 address set, Status is read only
+type LANIPv6Address struct {
+	Set          uint8
+	Enabled      bool // static addresses only
+	Source       uint8
+	Address      net.IP
+	PrefixLength uint8
+	Status       uint8
+}
+
+func (a *LANIPv6Address) setSelector() uint8 { return a.Set }
+
+// MarshalBinary encodes the parameter data
+func (a *LANIPv6Address) MarshalBinary() ([]byte, error) {
+	addr := a.Address.To16()
+	if addr == nil || a.PrefixLength > 128 {
+		return nil, ErrLANParamValue
+	}
+	buf := make([]byte, lanIPv6AddressSize)
+	buf[0] = a.Set
+	buf[1] = boolBit(a.Enabled, 0x80) | a.Source&0x0f
+	copy(buf[2:18], addr)
+	buf[18] = a.PrefixLength
+	buf[19] = a.Status
+	return buf, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (a *LANIPv6Address) UnmarshalBinary(buf []byte) error {
+	if len(buf) < lanIPv6AddressSize {
+		return ErrShortPacket
+	}
+	a.Set = buf[0]
+	a.Enabled = buf[1]&0x80 != 0
+	a.Source = buf[1] & 0x0f
+	a.Address = append(net.IP(nil), buf[2:18]...)
+	a.PrefixLength = buf[18]
+	a.Status = buf[19]
+	return nil
+}
+
+// LANIPv6StaticAddress is a static IPv6 address set, parameter 56
+type LANIPv6StaticAddress struct {
+	LANIPv6Address
+}
+
+// Param is the parameter selector
+func (*LANIPv6StaticAddress) Param() uint8 { return LANParamIPv6StaticAddress }
+
+// LANIPv6DynamicAddress is a read only SLAAC or DHCPv6 address set, parameter 59
+type LANIPv6DynamicAddress struct {
+	LANIPv6Address
+}
+
+// Param is the parameter selector
+func (*LANIPv6DynamicAddress) Param() uint8 { return LANParamIPv6DynamicAddress }
+
+// LANIPv6RouterConfig enables static and dynamic router addresses, parameter 64
+type LANIPv6RouterConfig struct {
+	Static  bool
+	Dynamic bool
+}
+
+// Param is the parameter selector
+func (*LANIPv6RouterConfig) Param() uint8 { return LANParamIPv6RouterConfig }
+
+// MarshalBinary encodes the parameter data
+func (p *LANIPv6RouterConfig) MarshalBinary() ([]byte, error) {
+	return []byte{boolBit(p.Dynamic, 0x02) | boolBit(p.Static, 0x01)}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (p *LANIPv6RouterConfig) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1 {
+		return ErrShortPacket
+	}
+	p.Dynamic = buf[0]&0x02 != 0
+	p.Static = buf[0]&0x01 != 0
+	return nil
+}
+
+// LANIPv6StaticRouter is the static router 1 IPv6 address, parameter 65
+type LANIPv6StaticRouter struct {
+	IP net.IP
+}
+
+// Param is the parameter selector
+func (*LANIPv6StaticRouter) Param() uint8 { return LANParamIPv6StaticRouterIP }
+
+// MarshalBinary encodes the parameter data
+func (p *LANIPv6StaticRouter) MarshalBinary() ([]byte, error) {
+	ip := p.IP.To16()
+	if ip == nil {
+		return nil, ErrLANParamValue
+	}
+	return append([]byte(nil), ip...), nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (p *LANIPv6StaticRouter) UnmarshalBinary(buf []byte) error {
+	if len(buf) < net.IPv6len {
+		return ErrShortPacket
+	}
+	p.IP = append(net.IP(nil), buf[:net.IPv6len]...)
+	return nil
+}
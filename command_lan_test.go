@@ -0,0 +1,161 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLANConfigParams(t *testing.T) {
+	tests := []struct {
+		param   LANConfigParamDecoder
+		decoded LANConfigParamDecoder
+		data    []byte
+	}{
+		{
+			&LANIPAddress{net.IPv4(10, 1, 2, 3)},
+			&LANIPAddress{},
+			[]byte{10, 1, 2, 3},
+		},
+		{
+			&LANSubnetMask{net.IPv4Mask(255, 255, 240, 0)},
+			&LANSubnetMask{},
+			[]byte{255, 255, 240, 0},
+		},
+		{
+			&LANVLAN{Enabled: true, ID: 0x123},
+			&LANVLAN{},
+			[]byte{0x23, 0x81},
+		},
+		{
+			&LANARPControl{Responses: true},
+			&LANARPControl{},
+			[]byte{0x02},
+		},
+		{
+			&LANAuthTypeEnables{Admin: 1 << AuthTypeMD5, User: 1<<AuthTypeMD5 | 1<<AuthTypePassword},
+			&LANAuthTypeEnables{},
+			[]byte{0x00, 0x14, 0x00, 0x04, 0x00},
+		},
+		{
+			&LANCipherSuitePrivLevels{PrivLevelAdmin, PrivLevelUser, 0, PrivLevelOperator},
+			&LANCipherSuitePrivLevels{},
+			[]byte{0x00, 0x24, 0x30, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			&LANIPv6StaticAddress{LANIPv6Address{
+				Set:          1,
+				Enabled:      true,
+				Address:      net.ParseIP("2001:db8::10"),
+				PrefixLength: 64,
+			}},
+			&LANIPv6StaticAddress{},
+			[]byte{
+				0x01, 0x80,
+				0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10,
+				0x40, 0x00,
+			},
+		},
+		{
+			&LANAlertDestinationType{Set: 1, Type: LANAlertPET, AckRequired: true, Timeout: 5 * time.Second, Retries: 2},
+			&LANAlertDestinationType{},
+			[]byte{0x01, 0x80, 0x04, 0x02},
+		},
+		{
+			&LANAlertDestinationAddr{
+				Set:           2,
+				BackupGateway: true,
+				IP:            net.IPv4(10, 0, 0, 9),
+				MAC:           net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x09},
+			},
+			&LANAlertDestinationAddr{},
+			[]byte{0x02, 0x00, 0x01, 10, 0, 0, 9, 0x02, 0x00, 0x00, 0x00, 0x00, 0x09},
+		},
+		{
+			&LANAlertDestinationAddr{Set: 3, IP: net.ParseIP("2001:db8::9")},
+			&LANAlertDestinationAddr{},
+			[]byte{
+				0x03, 0x10,
+				0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x09,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		data, err := test.param.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, test.data, data)
+
+		err = test.decoded.UnmarshalBinary(data)
+		assert.NoError(t, err)
+		assert.Equal(t, test.param.Param(), test.decoded.Param())
+		if _, ok := test.param.(lanConfigSetParam); ok {
+			assert.Equal(t, test.param, test.decoded)
+		}
+	}
+
+	community := LANCommunityString("public")
+	data, err := community.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Len(t, data, 18)
+	community = ""
+	assert.NoError(t, community.UnmarshalBinary(data))
+	assert.Equal(t, LANCommunityString("public"), community)
+
+	_, err = (&LANIPAddress{net.ParseIP("2001:db8::1")}).MarshalBinary()
+	assert.Equal(t, ErrLANParamValue, err)
+	_, err = (&LANVLAN{ID: 4096}).MarshalBinary()
+	assert.Equal(t, ErrLANParamValue, err)
+	_, err = LANCommunityString("a community string too long").MarshalBinary()
+	assert.Equal(t, ErrLANParamValue, err)
+	_, err = (&LANMACAddress{net.HardwareAddr{1, 2, 3}}).MarshalBinary()
+	assert.Equal(t, ErrLANParamValue, err)
+	_, err = (&LANAlertDestinationType{Timeout: 300 * time.Second}).MarshalBinary()
+	assert.Equal(t, ErrLANParamValue, err)
+	_, err = (&LANAlertDestinationType{Timeout: time.Second, Retries: 8}).MarshalBinary()
+	assert.Equal(t, ErrLANParamValue, err)
+	_, err = (&LANAlertDestinationAddr{}).MarshalBinary()
+	assert.Equal(t, ErrLANParamValue, err)
+
+	dest := &LANAlertDestinationType{}
+	assert.NoError(t, dest.UnmarshalBinary([]byte{0x00, 0x00, 0xff, 0x00}))
+	assert.Equal(t, 256*time.Second, dest.Timeout)
+
+	var source LANIPSource
+	assert.NoError(t, source.UnmarshalBinary([]byte{0x02}))
+	assert.Equal(t, LANIPSourceDHCP, source)
+	assert.Equal(t, "DHCP", source.String())
+}
+
+func TestLANConfigParse(t *testing.T) {
+	res := &LANConfigResponse{}
+	err := responseFromString("11 c0 a8 01 78", res)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(0x11), res.Revision)
+	ip := &LANIPAddress{}
+	assert.NoError(t, ip.UnmarshalBinary(res.Data))
+	assert.Equal(t, "192.168.1.120", ip.IP.String())
+
+	req := &SetLANConfigRequest{}
+	err = messageDataFromBytes([]byte{0x01, 0x04, 0x01}, req)
+	assert.NoError(t, err)
+	assert.Equal(t, &SetLANConfigRequest{Channel: 1, Param: LANParamIPAddressSource, Data: []uint8{0x01}}, req)
+}
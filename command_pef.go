@@ -0,0 +1,445 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// section 30.1 to 30.4, PEF commands on NetworkFunctionSensorEvent
+const (
+	CommandGetPEFCapabilities  = Command(0x10)
+	CommandArmPEFPostponeTimer = Command(0x11)
+	CommandSetPEFConfig        = Command(0x12)
+	CommandGetPEFConfig        = Command(0x13)
+)
+
+// PEF configuration parameters per section 30.4 - table 30-6
+const (
+	PEFParamSetInProgress     = 0
+	PEFParamControl           = 1
+	PEFParamActionControl     = 2
+	PEFParamStartupDelay      = 3
+	PEFParamAlertStartupDelay = 4
+	PEFParamEventFilterCount  = 5
+	PEFParamEventFilter       = 6
+	PEFParamEventFilterData1  = 7
+	PEFParamAlertPolicyCount  = 8
+	PEFParamAlertPolicy       = 9
+	PEFParamSystemGUID        = 10
+	PEFParamAlertStringCount  = 11
+	PEFParamAlertStringKey    = 12
+	PEFParamAlertString       = 13
+)
+
+// Get/Set PEF Configuration Parameters completion codes
+const (
+	ErrPEFParamNotSupported = CompletionCode(0x80)
+	ErrPEFSetInProgress     = ErrSetInProgress
+	ErrPEFParamReadOnly     = CompletionCode(0x82)
+)
+
+// ErrPEFParamValue is returned when a PEF parameter value cannot be encoded
+var ErrPEFParamValue = errors.New("value out of range for the PEF parameter")
+
+// PEF action bits, of the PEF capabilities, the global action control and the event filter actions
+const (
+	PEFActionAlert               = 0x01
+	PEFActionPowerDown           = 0x02
+	PEFActionReset               = 0x04
+	PEFActionPowerCycle          = 0x08
+	PEFActionOEM                 = 0x10
+	PEFActionDiagnosticInterrupt = 0x20
+	PEFActionGroupControl        = 0x40 // event filter actions only
+)
+
+// Event severities of an event filter
+const (
+	PEFSeverityUnspecified    = 0x00
+	PEFSeverityMonitor        = 0x01
+	PEFSeverityInformation    = 0x02
+	PEFSeverityOK             = 0x04
+	PEFSeverityNonCritical    = 0x08
+	PEFSeverityCritical       = 0x10
+	PEFSeverityNonRecoverable = 0x20
+)
+
+// PEFMatchAny in an event filter generator ID, sensor type, sensor number or event trigger matches any value
+const PEFMatchAny = 0xff
+
+// Event filter configuration types
+const (
+	PEFFilterSoftware     = 0x00 // software configurable
+	PEFFilterManufacturer = 0x02 // manufacturer pre-configured
+)
+
+// Alert policies of an alert policy entry
+const (
+	PEFPolicyAlways          = 0x0 // always send to this destination
+	PEFPolicyNextOnSuccess   = 0x1 // skip this destination if the previous alert succeeded
+	PEFPolicyStopOnSuccess   = 0x2 // stop processing the policy set if the previous alert succeeded
+	PEFPolicyNextChannel     = 0x3 // skip to the next entry on a different channel if the previous alert succeeded
+	PEFPolicyNextDestination = 0x4 // skip to the next entry of a different destination type if the previous alert succeeded
+)
+
+// Arm PEF Postpone Timer values other than a timeout in seconds
+const (
+	PEFPostponeDisable          = 0x00
+	PEFPostponeTemporaryDisable = 0xfe
+	PEFPostponeGet              = 0xff
+)
+
+// pefAlertStringBlockSize is the size of an alert string block
+const pefAlertStringBlockSize = 16
+
+// PEFCapabilitiesRequest per section 30.1
+type PEFCapabilitiesRequest struct{}
+
+// PEFCapabilitiesResponse per section 30.1
+type PEFCapabilitiesResponse struct {
+	CompletionCode
+	Version          uint8 // BCD, 0x51 for v1.5
+	ActionSupport    uint8 // PEFAction* bits
+	EventFilterCount uint8
+}
+
+// ArmPEFPostponeTimerRequest per section 30.2
+type ArmPEFPostponeTimerRequest struct {
+	Timeout uint8
+}
+
+// ArmPEFPostponeTimerResponse per section 30.2
+type ArmPEFPostponeTimerResponse struct {
+	CompletionCode
+	Countdown uint8
+}
+
+// SetPEFConfigRequest per section 30.3
+type SetPEFConfigRequest struct {
+	Param uint8
+	Data  []uint8
+}
+
+// SetPEFConfigResponse per section 30.3
+type SetPEFConfigResponse struct {
+	CompletionCode
+}
+
+// PEFConfigRequest per section 30.4, bit 7 of Param asks for the parameter revision only
+type PEFConfigRequest struct {
+	Param uint8
+	Set   uint8
+	Block uint8
+}
+
+// PEFConfigResponse per section 30.4
+type PEFConfigResponse struct {
+	CompletionCode
+	Revision uint8
+	Data     []uint8
+}
+
+// MarshalBinary implementation to handle variable length Data
+func (r *SetPEFConfigRequest) MarshalBinary() ([]byte, error) {
+	return append([]byte{r.Param}, r.Data...), nil
+}
+
+// UnmarshalBinary implementation to handle variable length Data
+func (r *SetPEFConfigRequest) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 2 {
+		return ErrShortPacket
+	}
+	r.Param = buf[0] & 0x7f
+	r.Data = buf[1:]
+	return nil
+}
+
+// MarshalBinary implementation to handle variable length Data
+func (r *PEFConfigResponse) MarshalBinary() ([]byte, error) {
+	return append([]byte{byte(r.CompletionCode), r.Revision}, r.Data...), nil
+}
+
+// UnmarshalBinary implementation to handle variable length Data
+func (r *PEFConfigResponse) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 2 {
+		return ErrShortPacket
+	}
+	r.CompletionCode = CompletionCode(buf[0])
+	r.Revision = buf[1]
+	r.Data = buf[2:]
+	return nil
+}
+
+// PEFConfigParam is a typed PEF configuration parameter, the binary form
+// being the parameter data of the Get/Set PEF Configuration Parameters commands
+type PEFConfigParam interface {
+	Param() uint8
+	MarshalBinary() ([]byte, error)
+}
+
+// PEFConfigParamDecoder is a pointer to a PEF configuration parameter,
+// which the parameter data can be read into
+type PEFConfigParamDecoder interface {
+	PEFConfigParam
+	UnmarshalBinary([]byte) error
+}
+
+// pefConfigSetParam is implemented by parameters that have a set selector
+type pefConfigSetParam interface {
+	setSelector() uint8
+}
+
+// PEFControl enables PEF, parameter 1
+type PEFControl struct {
+	Enabled           bool
+	EventMessages     bool // generate event messages for PEF actions
+	StartupDelay      bool
+	AlertStartupDelay bool
+}
+
+// Param is the parameter selector
+func (*PEFControl) Param() uint8 { return PEFParamControl }
+
+// MarshalBinary encodes the parameter data
+func (p *PEFControl) MarshalBinary() ([]byte, error) {
+	return []byte{
+		boolBit(p.AlertStartupDelay, 0x08) | boolBit(p.StartupDelay, 0x04) |
+			boolBit(p.EventMessages, 0x02) | boolBit(p.Enabled, 0x01),
+	}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (p *PEFControl) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1 {
+		return ErrShortPacket
+	}
+	p.AlertStartupDelay = buf[0]&0x08 != 0
+	p.StartupDelay = buf[0]&0x04 != 0
+	p.EventMessages = buf[0]&0x02 != 0
+	p.Enabled = buf[0]&0x01 != 0
+	return nil
+}
+
+// PEFActionControl are the PEFAction* bits of the actions enabled globally, parameter 2
+type PEFActionControl uint8
+
+// Param is the parameter selector
+func (PEFActionControl) Param() uint8 { return PEFParamActionControl }
+
+// MarshalBinary encodes the parameter data
+func (a PEFActionControl) MarshalBinary() ([]byte, error) {
+	return []byte{uint8(a) & 0x3f}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (a *PEFActionControl) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1 {
+		return ErrShortPacket
+	}
+	*a = PEFActionControl(buf[0] & 0x3f)
+	return nil
+}
+
+// PEFEventDataMask matches an event data byte: the byte ANDed with AND is matched against
+// the compare values, where Compare1 selects the bits to compare exactly to Compare2, see section 17.7
+type PEFEventDataMask struct {
+	AND      uint8
+	Compare1 uint8
+	Compare2 uint8
+}
+
+// pefEventFilterSize is the size of an event filter table entry
+const pefEventFilterSize = 20
+
+// PEFEventFilter is an event filter table entry, parameter 6. ID is the
+// entry number, from 1 to the PEFCapabilitiesResponse EventFilterCount
+type PEFEventFilter struct {
+	ID           uint8
+	Enabled      bool
+	Type         uint8 // PEFFilterSoftware or PEFFilterManufacturer
+	Action       uint8 // PEFAction* bits
+	PolicyNumber uint8 // alert policy number for PEFActionAlert
+	GroupControl uint8 // group control selector for PEFActionGroupControl
+	Severity     uint8
+	GeneratorID  uint16 // slave address or software ID, then channel and LUN; 0xffff for any
+	SensorType   SDRSensorType
+	SensorNumber uint8
+	EventTrigger SDRSensorReadingType // event/reading type code
+	OffsetMask   uint16               // event offsets of event data 1 to match, 0xffff for any
+	EventData1   PEFEventDataMask
+	EventData2   PEFEventDataMask
+	EventData3   PEFEventDataMask
+}
+
+// Param is the parameter selector
+func (*PEFEventFilter) Param() uint8 { return PEFParamEventFilter }
+
+func (f *PEFEventFilter) setSelector() uint8 { return f.ID }
+
+// MarshalBinary encodes the parameter data
+func (f *PEFEventFilter) MarshalBinary() ([]byte, error) {
+	if f.ID == 0 || f.ID > 0x7f || f.PolicyNumber > 0x0f || f.GroupControl > 0x07 {
+		return nil, ErrPEFParamValue
+	}
+	buf := make([]byte, 1+pefEventFilterSize)
+	buf[0] = f.ID
+	buf[1] = boolBit(f.Enabled, 0x80) | (f.Type&0x03)<<5
+	buf[2] = f.Action & 0x7f
+	buf[3] = f.GroupControl<<4 | f.PolicyNumber
+	buf[4] = f.Severity
+	buf[5] = uint8(f.GeneratorID)
+	buf[6] = uint8(f.GeneratorID >> 8)
+	buf[7] = uint8(f.SensorType)
+	buf[8] = f.SensorNumber
+	buf[9] = uint8(f.EventTrigger)
+	binary.LittleEndian.PutUint16(buf[10:], f.OffsetMask)
+	for i, mask := range []PEFEventDataMask{f.EventData1, f.EventData2, f.EventData3} {
+		copy(buf[12+3*i:], []byte{mask.AND, mask.Compare1, mask.Compare2})
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (f *PEFEventFilter) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1+pefEventFilterSize {
+		return ErrShortPacket
+	}
+	f.ID = buf[0] & 0x7f
+	f.Enabled = buf[1]&0x80 != 0
+	f.Type = buf[1] >> 5 & 0x03
+	f.Action = buf[2] & 0x7f
+	f.GroupControl = buf[3] >> 4 & 0x07
+	f.PolicyNumber = buf[3] & 0x0f
+	f.Severity = buf[4]
+	f.GeneratorID = uint16(buf[6])<<8 | uint16(buf[5])
+	f.SensorType = SDRSensorType(buf[7])
+	f.SensorNumber = buf[8]
+	f.EventTrigger = SDRSensorReadingType(buf[9])
+	f.OffsetMask = binary.LittleEndian.Uint16(buf[10:])
+	for i, mask := range []*PEFEventDataMask{&f.EventData1, &f.EventData2, &f.EventData3} {
+		mask.AND = buf[12+3*i]
+		mask.Compare1 = buf[13+3*i]
+		mask.Compare2 = buf[14+3*i]
+	}
+	return nil
+}
+
+// PEFAlertPolicy is an alert policy table entry, parameter 9. ID is the entry number,
+// from 1, the entries of a policy set share the PolicyNumber of the event filters
+type PEFAlertPolicy struct {
+	ID             uint8
+	PolicyNumber   uint8
+	Enabled        bool
+	Policy         uint8 // PEFPolicy*
+	Channel        uint8
+	Destination    uint8 // destination selector of the LAN alert destinations
+	EventSpecific  bool  // look the alert string up in the alert string keys
+	AlertStringSet uint8 // alert string set, or the alert string selector when not EventSpecific
+}
+
+// Param is the parameter selector
+func (*PEFAlertPolicy) Param() uint8 { return PEFParamAlertPolicy }
+
+func (p *PEFAlertPolicy) setSelector() uint8 { return p.ID }
+
+// MarshalBinary encodes the parameter data
+func (p *PEFAlertPolicy) MarshalBinary() ([]byte, error) {
+	if p.ID == 0 || p.ID > 0x7f || p.PolicyNumber > 0x0f || p.Policy > 0x07 ||
+		p.Channel > 0x0f || p.Destination > 0x0f || p.AlertStringSet > 0x7f {
+		return nil, ErrPEFParamValue
+	}
+	return []byte{
+		p.ID,
+		p.PolicyNumber<<4 | boolBit(p.Enabled, 0x08) | p.Policy,
+		p.Channel<<4 | p.Destination,
+		boolBit(p.EventSpecific, 0x80) | p.AlertStringSet,
+	}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (p *PEFAlertPolicy) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 4 {
+		return ErrShortPacket
+	}
+	p.ID = buf[0] & 0x7f
+	p.PolicyNumber = buf[1] >> 4
+	p.Enabled = buf[1]&0x08 != 0
+	p.Policy = buf[1] & 0x07
+	p.Channel = buf[2] >> 4
+	p.Destination = buf[2] & 0x0f
+	p.EventSpecific = buf[3]&0x80 != 0
+	p.AlertStringSet = buf[3] & 0x7f
+	return nil
+}
+
+// PEFAlertStringKey maps an event filter to an alert string set, parameter 12.
+// ID is the alert string selector, 0 being the volatile string
+type PEFAlertStringKey struct {
+	ID             uint8
+	EventFilter    uint8
+	AlertStringSet uint8
+}
+
+// Param is the parameter selector
+func (*PEFAlertStringKey) Param() uint8 { return PEFParamAlertStringKey }
+
+func (k *PEFAlertStringKey) setSelector() uint8 { return k.ID }
+
+// MarshalBinary encodes the parameter data
+func (k *PEFAlertStringKey) MarshalBinary() ([]byte, error) {
+	if k.ID > 0x7f || k.EventFilter > 0x7f || k.AlertStringSet > 0x7f {
+		return nil, ErrPEFParamValue
+	}
+	return []byte{k.ID, k.EventFilter, k.AlertStringSet}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (k *PEFAlertStringKey) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 3 {
+		return ErrShortPacket
+	}
+	k.ID = buf[0] & 0x7f
+	k.EventFilter = buf[1] & 0x7f
+	k.AlertStringSet = buf[2] & 0x7f
+	return nil
+}
+
+// PEFSystemGUID is the GUID sent in PET traps, parameter 10.
+// The Get System GUID value is sent when UseGUID is false
+type PEFSystemGUID struct {
+	UseGUID bool
+	GUID    [16]byte
+}
+
+// Param is the parameter selector
+func (*PEFSystemGUID) Param() uint8 { return PEFParamSystemGUID }
+
+// MarshalBinary encodes the parameter data
+func (g *PEFSystemGUID) MarshalBinary() ([]byte, error) {
+	return append([]byte{boolBit(g.UseGUID, 0x01)}, g.GUID[:]...), nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (g *PEFSystemGUID) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 17 {
+		return ErrShortPacket
+	}
+	g.UseGUID = buf[0]&0x01 != 0
+	copy(g.GUID[:], buf[1:17])
+	return nil
+}
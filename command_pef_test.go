@@ -0,0 +1,102 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPEFEventFilter(t *testing.T) {
+	filter := &PEFEventFilter{
+		ID:           3,
+		Enabled:      true,
+		Action:       PEFActionAlert,
+		PolicyNumber: 1,
+		Severity:     PEFSeverityCritical,
+		GeneratorID:  0xffff,
+		SensorType:   SDR_SENSOR_TYPECODES_FAN,
+		SensorNumber: PEFMatchAny,
+		EventTrigger: SENSOR_READTYPE_THREADHOLD,
+		OffsetMask:   0x0014,
+		EventData1:   PEFEventDataMask{AND: 0x0f},
+		EventData2:   PEFEventDataMask{AND: 0xff, Compare1: 0xff, Compare2: 0x02},
+	}
+
+	data, err := filter.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, rawDecode("03 80 01 01 10 ff ff 04 ff 01 14 00 0f 00 00 ff ff 02 00 00 00"), data)
+
+	decoded := &PEFEventFilter{}
+	err = decoded.UnmarshalBinary(data)
+	assert.NoError(t, err)
+	assert.Equal(t, filter, decoded)
+
+	// manufacturer pre-configured, disabled
+	err = decoded.UnmarshalBinary(rawDecode("01 40 02 00 20 20 00 01 ff 6f ff ff 00 00 00 00 00 00 00 00 00"))
+	assert.NoError(t, err)
+	assert.False(t, decoded.Enabled)
+	assert.Equal(t, uint8(PEFFilterManufacturer), decoded.Type)
+	assert.Equal(t, uint8(PEFActionPowerDown), decoded.Action)
+	assert.Equal(t, uint16(0x0020), decoded.GeneratorID)
+	assert.Equal(t, SDRSensorReadingType(SENSOR_READTYPE_SENSORSPECIF), decoded.EventTrigger)
+
+	_, err = (&PEFEventFilter{}).MarshalBinary()
+	assert.Equal(t, ErrPEFParamValue, err)
+}
+
+func TestPEFAlertPolicy(t *testing.T) {
+	policy := &PEFAlertPolicy{
+		ID:             2,
+		PolicyNumber:   1,
+		Enabled:        true,
+		Policy:         PEFPolicyStopOnSuccess,
+		Channel:        1,
+		Destination:    3,
+		AlertStringSet: 5,
+	}
+
+	data, err := policy.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x02, 0x1a, 0x13, 0x05}, data)
+
+	decoded := &PEFAlertPolicy{}
+	err = decoded.UnmarshalBinary(data)
+	assert.NoError(t, err)
+	assert.Equal(t, policy, decoded)
+
+	policy.Destination = 0x10
+	_, err = policy.MarshalBinary()
+	assert.Equal(t, ErrPEFParamValue, err)
+}
+
+func TestPEFConfigParse(t *testing.T) {
+	caps := &PEFCapabilitiesResponse{}
+	err := responseFromString("51 0f 10", caps)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(0x51), caps.Version)
+	assert.Equal(t, uint8(PEFActionAlert|PEFActionPowerDown|PEFActionReset|PEFActionPowerCycle), caps.ActionSupport)
+	assert.Equal(t, uint8(16), caps.EventFilterCount)
+
+	res := &PEFConfigResponse{}
+	err = responseFromString("11 03", res)
+	assert.NoError(t, err)
+	control := &PEFControl{}
+	assert.NoError(t, control.UnmarshalBinary(res.Data))
+	assert.Equal(t, &PEFControl{Enabled: true, EventMessages: true}, control)
+}
@@ -22,10 +22,11 @@ import (
 )
 
 const (
-	CommandGetSDRRepositoryInfo = Command(0x20)
-	CommandGetReserveSDRRepo    = Command(0x22)
-	CommandGetSDR               = Command(0x23)
-	CommandGetSensorReading     = Command(0x2d)
+	CommandGetSDRRepositoryInfo    = Command(0x20)
+	CommandGetReserveSDRRepo       = Command(0x22)
+	CommandGetSDR                  = Command(0x23)
+	CommandGetSensorReadingFactors = Command(0x23)
+	CommandGetSensorReading        = Command(0x2d)
 )
 
 type ReserveSDRRepositoryRequest struct{}
@@ -46,6 +47,19 @@ type GetSDRCommandResponse struct {
 	NextRecordID uint16
 	ReadData     []byte
 }
+
+// section 35.5
+type GetSensorReadingFactorsRequest struct {
+	SensorNumber uint8
+	ReadingByte  uint8
+}
+
+type GetSensorReadingFactorsResponse struct {
+	CompletionCode
+	NextReading uint8
+	SensorFactors
+}
+
 type GetSensorReadingRequest struct {
 	SensorNumber uint8
 }
@@ -81,3 +95,46 @@ func (r *GetSDRCommandResponse) UnmarshalBinary(data []byte) error {
 	r.ReadData = data[3:]
 	return nil
 }
+
+// UnmarshalBinary implementation to handle the optional state bytes
+func (r *GetSensorReadingResponse) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 3 {
+		return ErrShortPacket
+	}
+	r.CompletionCode = CompletionCode(buf[0])
+	r.SensorReading = buf[1]
+	r.ReadingAvail = buf[2]
+	r.ForThresDiscreStat, r.ForDiscreteState = 0, 0
+	if len(buf) > 3 {
+		r.ForThresDiscreStat = buf[3]
+	}
+	if len(buf) > 4 {
+		r.ForDiscreteState = buf[4]
+	}
+	return nil
+}
+
+// EventMessagesDisabled reports whether all event messages from the sensor are disabled
+func (r *GetSensorReadingResponse) EventMessagesDisabled() bool {
+	return r.ReadingAvail&0x80 == 0
+}
+
+// ScanningDisabled reports whether the sensor scanning is disabled
+func (r *GetSensorReadingResponse) ScanningDisabled() bool {
+	return r.ReadingAvail&0x40 == 0
+}
+
+// ReadingUnavailable reports whether the reading or state is unavailable
+func (r *GetSensorReadingResponse) ReadingUnavailable() bool {
+	return r.ReadingAvail&0x20 != 0
+}
+
+// ThresholdStatus returns the threshold comparison status of a threshold sensor
+func (r *GetSensorReadingResponse) ThresholdStatus() ThresholdStatus {
+	return ThresholdStatus(r.ForThresDiscreStat & 0x3f)
+}
+
+// StateMask returns the asserted states 0 to 14 of a discrete sensor
+func (r *GetSensorReadingResponse) StateMask() uint16 {
+	return uint16(r.ForThresDiscreStat) | uint16(r.ForDiscreteState&0x7f)<<8
+}
@@ -0,0 +1,160 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+// section 35, sensor threshold, hysteresis and event enable commands
+const (
+	CommandSetSensorHysteresis  = Command(0x24)
+	CommandGetSensorHysteresis  = Command(0x25)
+	CommandSetSensorThresholds  = Command(0x26)
+	CommandGetSensorThresholds  = Command(0x27)
+	CommandSetSensorEventEnable = Command(0x28)
+	CommandGetSensorEventEnable = Command(0x29)
+)
+
+// Set Sensor Event Enable actions on the individual event enables
+const (
+	SensorEventEnableKeep      = 0x00 // do not change the individual enables
+	SensorEventEnableSelected  = 0x10 // enable the selected event messages
+	SensorEventDisableSelected = 0x20 // disable the selected event messages
+)
+
+// SensorThresholdValues are the raw threshold values of a sensor
+type SensorThresholdValues struct {
+	LowerNonCritical    uint8
+	LowerCritical       uint8
+	LowerNonRecoverable uint8
+	UpperNonCritical    uint8
+	UpperCritical       uint8
+	UpperNonRecoverable uint8
+}
+
+// section 35.6
+type SetSensorHysteresisRequest struct {
+	SensorNumber       uint8
+	Reserved           uint8 // hysteresis mask, write as 0xff
+	PositiveHysteresis uint8
+	NegativeHysteresis uint8
+}
+
+type SetSensorHysteresisResponse struct {
+	CompletionCode
+}
+
+// section 35.7
+type GetSensorHysteresisRequest struct {
+	SensorNumber uint8
+	Reserved     uint8 // hysteresis mask, write as 0xff
+}
+
+type GetSensorHysteresisResponse struct {
+	CompletionCode
+	PositiveHysteresis uint8
+	NegativeHysteresis uint8
+}
+
+// section 35.8
+type SetSensorThresholdsRequest struct {
+	SensorNumber uint8
+	Mask         ThresholdStatus // thresholds to set
+	SensorThresholdValues
+}
+
+type SetSensorThresholdsResponse struct {
+	CompletionCode
+}
+
+// section 35.9
+type GetSensorThresholdsRequest struct {
+	SensorNumber uint8
+}
+
+type GetSensorThresholdsResponse struct {
+	CompletionCode
+	Readable ThresholdStatus // readable thresholds
+	SensorThresholdValues
+}
+
+// section 35.10
+type SetSensorEventEnableRequest struct {
+	SensorNumber    uint8
+	Flags           uint8 // [7] event messages, [6] scanning, [5:4] action on the masks
+	AssertionMask   uint16
+	DeassertionMask uint16
+}
+
+type SetSensorEventEnableResponse struct {
+	CompletionCode
+}
+
+// section 35.11
+type GetSensorEventEnableRequest struct {
+	SensorNumber uint8
+}
+
+type GetSensorEventEnableResponse struct {
+	CompletionCode
+	Flags           uint8 // [7] event messages enabled, [6] scanning enabled
+	AssertionMask   uint16
+	DeassertionMask uint16
+}
+
+// MarshalBinary implementation to handle the optional mask bytes
+func (r *GetSensorEventEnableResponse) MarshalBinary() ([]byte, error) {
+	return []byte{
+		byte(r.CompletionCode), r.Flags,
+		byte(r.AssertionMask), byte(r.AssertionMask >> 8),
+		byte(r.DeassertionMask), byte(r.DeassertionMask >> 8),
+	}, nil
+}
+
+// UnmarshalBinary implementation to handle the optional mask bytes
+func (r *GetSensorEventEnableResponse) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 2 {
+		return ErrShortPacket
+	}
+	r.CompletionCode = CompletionCode(buf[0])
+	r.Flags = buf[1]
+	mask := make([]byte, 4)
+	copy(mask, buf[2:])
+	r.AssertionMask = uint16(mask[0]) | uint16(mask[1])<<8
+	r.DeassertionMask = uint16(mask[2]) | uint16(mask[3])<<8
+	return nil
+}
+
+// MarshalBinary implementation to handle the optional mask bytes
+func (r *SetSensorEventEnableRequest) MarshalBinary() ([]byte, error) {
+	return []byte{
+		r.SensorNumber, r.Flags,
+		byte(r.AssertionMask), byte(r.AssertionMask >> 8),
+		byte(r.DeassertionMask), byte(r.DeassertionMask >> 8),
+	}, nil
+}
+
+// UnmarshalBinary implementation to handle the optional mask bytes
+func (r *SetSensorEventEnableRequest) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 2 {
+		return ErrShortPacket
+	}
+	r.SensorNumber = buf[0]
+	r.Flags = buf[1]
+	mask := make([]byte, 4)
+	copy(mask, buf[2:])
+	r.AssertionMask = uint16(mask[0]) | uint16(mask[1])<<8
+	r.DeassertionMask = uint16(mask[2]) | uint16(mask[3])<<8
+	return nil
+}
@@ -0,0 +1,354 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// section 26.2 and 26.3, SOL configuration commands on NetworkFunctionTransport
+const (
+	CommandSetSOLConfig = Command(0x21)
+	CommandGetSOLConfig = Command(0x22)
+)
+
+// SOL configuration parameters per section 26.3 - table 26-5
+const (
+	SOLParamSetInProgress      = 0
+	SOLParamEnable             = 1
+	SOLParamAuth               = 2
+	SOLParamAccumulate         = 3
+	SOLParamRetry              = 4
+	SOLParamNonVolatileBitRate = 5
+	SOLParamVolatileBitRate    = 6
+	SOLParamPayloadChannel     = 7
+	SOLParamPayloadPort        = 8
+)
+
+// Get/Set SOL Configuration Parameters completion codes
+const (
+	ErrSOLParamNotSupported = CompletionCode(0x80)
+	ErrSOLSetInProgress     = ErrSetInProgress
+	ErrSOLParamReadOnly     = CompletionCode(0x82)
+)
+
+// ErrSOLParamValue is returned when a SOL parameter value cannot be encoded
+var ErrSOLParamValue = errors.New("value out of range for the SOL parameter")
+
+// SOL character accumulate and retry interval units
+const (
+	SOLAccumulateUnit = 5 * time.Millisecond
+	SOLRetryUnit      = 10 * time.Millisecond
+)
+
+// SetSOLConfigRequest per section 26.2
+type SetSOLConfigRequest struct {
+	Channel uint8
+	Param   uint8
+	Data    []uint8
+}
+
+// SetSOLConfigResponse per section 26.2
+type SetSOLConfigResponse struct {
+	CompletionCode
+}
+
+// SOLConfigRequest per section 26.3, bit 7 of Channel asks for the parameter revision only
+type SOLConfigRequest struct {
+	Channel uint8
+	Param   uint8
+	Set     uint8
+	Block   uint8
+}
+
+// SOLConfigResponse per section 26.3
+type SOLConfigResponse struct {
+	CompletionCode
+	Revision uint8
+	Data     []uint8
+}
+
+// MarshalBinary implementation to handle variable length Data
+func (r *SetSOLConfigRequest) MarshalBinary() ([]byte, error) {
+	return append([]byte{r.Channel, r.Param}, r.Data...), nil
+}
+
+// UnmarshalBinary implementation to handle variable length Data
+func (r *SetSOLConfigRequest) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 3 {
+		return ErrShortPacket
+	}
+	r.Channel = buf[0]
+	r.Param = buf[1]
+	r.Data = buf[2:]
+	return nil
+}
+
+// MarshalBinary implementation to handle variable length Data
+func (r *SOLConfigResponse) MarshalBinary() ([]byte, error) {
+	return append([]byte{byte(r.CompletionCode), r.Revision}, r.Data...), nil
+}
+
+// UnmarshalBinary implementation to handle variable length Data
+func (r *SOLConfigResponse) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 2 {
+		return ErrShortPacket
+	}
+	r.CompletionCode = CompletionCode(buf[0])
+	r.Revision = buf[1]
+	r.Data = buf[2:]
+	return nil
+}
+
+// SOLConfigParam is a typed SOL configuration parameter, the binary form
+// being the parameter data of the Get/Set SOL Configuration Parameters commands
+type SOLConfigParam interface {
+	Param() uint8
+	MarshalBinary() ([]byte, error)
+}
+
+// SOLConfigParamDecoder is a pointer to a SOL configuration parameter,
+// which the parameter data can be read into
+type SOLConfigParamDecoder interface {
+	SOLConfigParam
+	UnmarshalBinary([]byte) error
+}
+
+// SOLEnable enables SOL on the channel, parameter 1
+type SOLEnable bool
+
+// Param is the parameter selector
+func (SOLEnable) Param() uint8 { return SOLParamEnable }
+
+// MarshalBinary encodes the parameter data
+func (e SOLEnable) MarshalBinary() ([]byte, error) {
+	return []byte{boolBit(bool(e), 0x01)}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (e *SOLEnable) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1 {
+		return ErrShortPacket
+	}
+	*e = buf[0]&0x01 != 0
+	return nil
+}
+
+// SOLAuth is the SOL security, parameter 2
+type SOLAuth struct {
+	ForceEncryption     bool
+	ForceAuthentication bool
+	Privilege           uint8 // minimum privilege level to activate SOL
+}
+
+// Param is the parameter selector
+func (*SOLAuth) Param() uint8 { return SOLParamAuth }
+
+// MarshalBinary encodes the parameter data
+func (p *SOLAuth) MarshalBinary() ([]byte, error) {
+	return []byte{
+		boolBit(p.ForceEncryption, 0x80) | boolBit(p.ForceAuthentication, 0x40) | p.Privilege&0x0f,
+	}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (p *SOLAuth) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1 {
+		return ErrShortPacket
+	}
+	p.ForceEncryption = buf[0]&0x80 != 0
+	p.ForceAuthentication = buf[0]&0x40 != 0
+	p.Privilege = buf[0] & 0x0f
+	return nil
+}
+
+// SOLAccumulate tells when the BMC sends the characters it accumulated, parameter 3
+type SOLAccumulate struct {
+	Interval  time.Duration // in SOLAccumulateUnit, 5ms to 1275ms
+	Threshold uint8         // character count, not zero
+}
+
+// Param is the parameter selector
+func (*SOLAccumulate) Param() uint8 { return SOLParamAccumulate }
+
+// MarshalBinary encodes the parameter data
+func (p *SOLAccumulate) MarshalBinary() ([]byte, error) {
+	interval := p.Interval / SOLAccumulateUnit
+	if interval < 1 || interval > 0xff || p.Threshold == 0 {
+		return nil, ErrSOLParamValue
+	}
+	return []byte{uint8(interval), p.Threshold}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (p *SOLAccumulate) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 2 {
+		return ErrShortPacket
+	}
+	p.Interval = time.Duration(buf[0]) * SOLAccumulateUnit
+	p.Threshold = buf[1]
+	return nil
+}
+
+// SOLRetry is the retransmission of unacknowledged SOL packets, parameter 4
+type SOLRetry struct {
+	Count    uint8         // 0 to 7, 0 for no retries
+	Interval time.Duration // in SOLRetryUnit, up to 2550ms
+}
+
+// Param is the parameter selector
+func (*SOLRetry) Param() uint8 { return SOLParamRetry }
+
+// MarshalBinary encodes the parameter data
+func (p *SOLRetry) MarshalBinary() ([]byte, error) {
+	interval := p.Interval / SOLRetryUnit
+	if p.Count > 7 || interval > 0xff {
+		return nil, ErrSOLParamValue
+	}
+	return []byte{p.Count, uint8(interval)}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (p *SOLRetry) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 2 {
+		return ErrShortPacket
+	}
+	p.Count = buf[0] & 0x07
+	p.Interval = time.Duration(buf[1]) * SOLRetryUnit
+	return nil
+}
+
+// SOLBitRate is the SOL serial bit rate
+type SOLBitRate uint8
+
+// SOLBitRate values
+const (
+	SOLBitRateSerial = SOLBitRate(0x00) // the IPMI over serial channel setting
+	SOLBitRate9600   = SOLBitRate(0x06)
+	SOLBitRate19200  = SOLBitRate(0x07)
+	SOLBitRate38400  = SOLBitRate(0x08)
+	SOLBitRate57600  = SOLBitRate(0x09)
+	SOLBitRate115200 = SOLBitRate(0x0a)
+)
+
+var solBitRates = map[SOLBitRate]int{
+	SOLBitRate9600:   9600,
+	SOLBitRate19200:  19200,
+	SOLBitRate38400:  38400,
+	SOLBitRate57600:  57600,
+	SOLBitRate115200: 115200,
+}
+
+// SOLBitRateFromBaud returns the SOLBitRate of a baud rate
+func SOLBitRateFromBaud(baud int) (SOLBitRate, error) {
+	for rate, b := range solBitRates {
+		if b == baud {
+			return rate, nil
+		}
+	}
+	return 0, ErrSOLParamValue
+}
+
+// Baud is the baud rate, 0 for SOLBitRateSerial or a reserved value
+func (r SOLBitRate) Baud() int {
+	return solBitRates[r]
+}
+
+func (r SOLBitRate) String() string {
+	if r == SOLBitRateSerial {
+		return "serial"
+	}
+	if baud, ok := solBitRates[r]; ok {
+		return fmt.Sprintf("%d", baud)
+	}
+	return fmt.Sprintf("reserved (0x%02x)", uint8(r))
+}
+
+// MarshalBinary encodes the parameter data
+func (r SOLBitRate) MarshalBinary() ([]byte, error) {
+	return []byte{uint8(r) & 0x0f}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (r *SOLBitRate) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1 {
+		return ErrShortPacket
+	}
+	*r = SOLBitRate(buf[0] & 0x0f)
+	return nil
+}
+
+// SOLNonVolatileBitRate is the bit rate SOL starts with, parameter 5
+type SOLNonVolatileBitRate struct {
+	SOLBitRate
+}
+
+// Param is the parameter selector
+func (*SOLNonVolatileBitRate) Param() uint8 { return SOLParamNonVolatileBitRate }
+
+// SOLVolatileBitRate is the bit rate SOL uses until the next BMC reset, parameter 6
+type SOLVolatileBitRate struct {
+	SOLBitRate
+}
+
+// Param is the parameter selector
+func (*SOLVolatileBitRate) Param() uint8 { return SOLParamVolatileBitRate }
+
+// SOLPayloadChannel is the read only channel the SOL payload is carried on, parameter 7
+type SOLPayloadChannel uint8
+
+// Param is the parameter selector
+func (SOLPayloadChannel) Param() uint8 { return SOLParamPayloadChannel }
+
+// MarshalBinary encodes the parameter data
+func (c SOLPayloadChannel) MarshalBinary() ([]byte, error) {
+	return []byte{uint8(c) & 0x0f}, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (c *SOLPayloadChannel) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1 {
+		return ErrShortPacket
+	}
+	*c = SOLPayloadChannel(buf[0] & 0x0f)
+	return nil
+}
+
+// SOLPayloadPort is the UDP port of the SOL payload, parameter 8
+type SOLPayloadPort uint16
+
+// Param is the parameter selector
+func (SOLPayloadPort) Param() uint8 { return SOLParamPayloadPort }
+
+// MarshalBinary encodes the parameter data
+func (p SOLPayloadPort) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 2)
+	binary.LittleEndian.PutUint16(buf, uint16(p))
+	return buf, nil
+}
+
+// UnmarshalBinary decodes the parameter data
+func (p *SOLPayloadPort) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 2 {
+		return ErrShortPacket
+	}
+	*p = SOLPayloadPort(binary.LittleEndian.Uint16(buf))
+	return nil
+}
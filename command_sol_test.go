@@ -0,0 +1,81 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSOLConfigParams(t *testing.T) {
+	tests := []struct {
+		param   SOLConfigParamDecoder
+		decoded SOLConfigParamDecoder
+		data    []byte
+	}{
+		{
+			&SOLAuth{ForceEncryption: true, ForceAuthentication: true, Privilege: PrivLevelUser},
+			&SOLAuth{},
+			[]byte{0xc2},
+		},
+		{
+			&SOLAccumulate{Interval: 50 * time.Millisecond, Threshold: 200},
+			&SOLAccumulate{},
+			[]byte{0x0a, 0xc8},
+		},
+		{
+			&SOLRetry{Count: 3, Interval: 200 * time.Millisecond},
+			&SOLRetry{},
+			[]byte{0x03, 0x14},
+		},
+		{
+			&SOLVolatileBitRate{SOLBitRate115200},
+			&SOLVolatileBitRate{},
+			[]byte{0x0a},
+		},
+	}
+
+	for _, test := range tests {
+		data, err := test.param.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, test.data, data)
+
+		err = test.decoded.UnmarshalBinary(data)
+		assert.NoError(t, err)
+		assert.Equal(t, test.param, test.decoded)
+	}
+
+	port := SOLPayloadPort(0)
+	assert.NoError(t, port.UnmarshalBinary([]byte{0x6f, 0x02}))
+	assert.Equal(t, SOLPayloadPort(623), port)
+
+	_, err := (&SOLAccumulate{Interval: 2 * time.Second, Threshold: 1}).MarshalBinary()
+	assert.Equal(t, ErrSOLParamValue, err)
+	_, err = (&SOLRetry{Count: 8}).MarshalBinary()
+	assert.Equal(t, ErrSOLParamValue, err)
+
+	rate, err := SOLBitRateFromBaud(57600)
+	assert.NoError(t, err)
+	assert.Equal(t, SOLBitRate57600, rate)
+	assert.Equal(t, 57600, rate.Baud())
+	assert.Equal(t, "57600", rate.String())
+	assert.Equal(t, "serial", SOLBitRateSerial.String())
+	_, err = SOLBitRateFromBaud(4800)
+	assert.Equal(t, ErrSOLParamValue, err)
+}
@@ -49,6 +49,11 @@ const (
 	ErrUnspecified       = CompletionCode(0xff)
 )
 
+// ErrSetInProgress is the command specific completion code of the Set LAN, SOL, PEF
+// Configuration Parameters and Set System Boot Options commands, set-in-progress
+// being already set by another party
+const ErrSetInProgress = CompletionCode(0x81)
+
 var completionCodes = map[CompletionCode]string{
 	CommandCompleted:     "Command completed normally",
 	ErrNodeBusy:          "Node busy",
@@ -52,6 +52,21 @@ const (
 	SDR_SENSOR_TYPECODES_FAN         = 0x04
 )
 
+// section 43.1, threshold and hysteresis access support in the sensor capabilities
+const (
+	SENSOR_ACCESS_NONE     = 0x00
+	SENSOR_ACCESS_READABLE = 0x01
+	SENSOR_ACCESS_SETTABLE = 0x02
+	SENSOR_ACCESS_FIXED    = 0x03
+)
+
+// section 43.1, modifier unit in the sensor units 1 byte
+const (
+	SENSOR_MODIFIER_UNIT_NONE     = 0x00
+	SENSOR_MODIFIER_UNIT_DIVIDE   = 0x01 // basic unit / modifier unit
+	SENSOR_MODIFIER_UNIT_MULTIPLY = 0x02 // basic unit * modifier unit
+)
+
 type SDRSensorReadingType uint8
 
 const (
@@ -0,0 +1,381 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Additional device support bits of the Device ID per section 20.1
+const (
+	DeviceSupportSensor             = 0x01
+	DeviceSupportSDRRepository      = 0x02
+	DeviceSupportSEL                = 0x04
+	DeviceSupportFRUInventory       = 0x08
+	DeviceSupportIPMBEventReceiver  = 0x10
+	DeviceSupportIPMBEventGenerator = 0x20
+	DeviceSupportBridge             = 0x40
+	DeviceSupportChassis            = 0x80
+)
+
+// Self test results per section 20.4
+const (
+	SelfTestPassed         = 0x55
+	SelfTestNotImplemented = 0x56
+	SelfTestCorrupted      = 0x57 // the detail byte tells what is corrupted or inaccessible
+	SelfTestFatal          = 0x58
+)
+
+// ACPI power states per section 20.6
+const (
+	ACPISystemS0        = 0x00 // S0/G0 working
+	ACPISystemS1        = 0x01
+	ACPISystemS2        = 0x02
+	ACPISystemS3        = 0x03
+	ACPISystemS4        = 0x04
+	ACPISystemS5        = 0x05 // S5/G2 soft off
+	ACPISystemS4S5      = 0x06 // soft off, S4 or S5 cannot be differentiated
+	ACPISystemG3        = 0x07 // mechanical off
+	ACPISystemSleeping  = 0x08 // S1, S2 or S3
+	ACPISystemG1        = 0x09 // S1, S2 or S4
+	ACPISystemOverride  = 0x0a // S5 entered by override
+	ACPISystemLegacyOn  = 0x20
+	ACPISystemLegacyOff = 0x21
+	ACPISystemUnknown   = 0x2a
+
+	ACPIDeviceD0      = 0x00
+	ACPIDeviceD1      = 0x01
+	ACPIDeviceD2      = 0x02
+	ACPIDeviceD3      = 0x03
+	ACPIDeviceUnknown = 0x2a
+
+	// ACPINoChange leaves the state as is on Set ACPI Power State
+	ACPINoChange = 0x7f
+)
+
+var selfTestFailures = []struct {
+	bit  uint8
+	desc string
+}{
+	{0x80, "Cannot access SEL device"},
+	{0x40, "Cannot access SDR Repository"},
+	{0x20, "Cannot access BMC FRU device"},
+	{0x10, "IPMB signal lines do not respond"},
+	{0x08, "SDR Repository empty"},
+	{0x04, "Internal Use Area of BMC FRU corrupted"},
+	{0x02, "Controller update 'boot block' firmware corrupted"},
+	{0x01, "Controller operational firmware corrupted"},
+}
+
+var acpiSystemStates = map[uint8]string{
+	ACPISystemS0:        "S0/G0: working",
+	ACPISystemS1:        "S1: hardware context maintained",
+	ACPISystemS2:        "S2: processor context lost",
+	ACPISystemS3:        "S3: suspend-to-RAM",
+	ACPISystemS4:        "S4: suspend-to-disk",
+	ACPISystemS5:        "S5/G2: soft-off",
+	ACPISystemS4S5:      "S4/S5: soft-off",
+	ACPISystemG3:        "G3: mechanical off",
+	ACPISystemSleeping:  "sleeping",
+	ACPISystemG1:        "G1: sleeping",
+	ACPISystemOverride:  "S5: entered by override",
+	ACPISystemLegacyOn:  "legacy on",
+	ACPISystemLegacyOff: "legacy soft-off",
+	ACPISystemUnknown:   "unknown",
+}
+
+var acpiDeviceStates = map[uint8]string{
+	ACPIDeviceD0:      "D0",
+	ACPIDeviceD1:      "D1",
+	ACPIDeviceD2:      "D2",
+	ACPIDeviceD3:      "D3",
+	ACPIDeviceUnknown: "unknown",
+}
+
+// DeviceCapabilities tells which optional features the BMC implements,
+// per the additional device support field of the Device ID
+type DeviceCapabilities struct {
+	Sensor             bool
+	SDRRepository      bool
+	SEL                bool
+	FRUInventory       bool
+	IPMBEventReceiver  bool
+	IPMBEventGenerator bool
+	Bridge             bool
+	Chassis            bool
+	DeviceSDRs         bool // the device provides Device SDRs
+	IPMIVersionMajor   uint8
+	IPMIVersionMinor   uint8
+}
+
+// IPMI20 tells whether the BMC implements IPMI v2.0 or later
+func (c *DeviceCapabilities) IPMI20() bool {
+	return c.IPMIVersionMajor >= 2
+}
+
+// MarshalBinary implementation to handle the 3 byte manufacturer ID and the optional aux bytes
+func (r *DeviceIDResponse) MarshalBinary() ([]byte, error) {
+	buf := []byte{
+		byte(r.CompletionCode),
+		r.DeviceID,
+		r.DeviceRevision,
+		r.FirmwareRevision1,
+		r.FirmwareRevision2,
+		r.IPMIVersion,
+		r.AdditionalDeviceSupport,
+		byte(r.ManufacturerID),
+		byte(r.ManufacturerID >> 8),
+		byte(r.ManufacturerID>>16) & 0x0f,
+		byte(r.ProductID),
+		byte(r.ProductID >> 8),
+	}
+	return append(buf, r.AuxFirmwareRevision...), nil
+}
+
+// UnmarshalBinary implementation to handle the 3 byte manufacturer ID and the optional aux bytes
+func (r *DeviceIDResponse) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 12 {
+		return ErrShortPacket
+	}
+	r.CompletionCode = CompletionCode(buf[0])
+	r.DeviceID = buf[1]
+	r.DeviceRevision = buf[2]
+	r.FirmwareRevision1 = buf[3]
+	r.FirmwareRevision2 = buf[4]
+	r.IPMIVersion = buf[5]
+	r.AdditionalDeviceSupport = buf[6]
+	r.ManufacturerID = OemID(uint32(buf[7]) | uint32(buf[8])<<8 | uint32(buf[9]&0x0f)<<16)
+	r.ProductID = binary.LittleEndian.Uint16(buf[10:])
+	r.AuxFirmwareRevision = nil
+	if len(buf) >= 16 {
+		r.AuxFirmwareRevision = append([]uint8{}, buf[12:16]...)
+	}
+	return nil
+}
+
+// Revision is the device revision
+func (r *DeviceIDResponse) Revision() uint8 {
+	return r.DeviceRevision & 0x0f
+}
+
+// ProvidesDeviceSDRs tells whether the device provides Device SDRs
+func (r *DeviceIDResponse) ProvidesDeviceSDRs() bool {
+	return r.DeviceRevision&0x80 != 0
+}
+
+// IsUpdateInProgress tells whether the device firmware is being updated, or self-initializing
+func (r *DeviceIDResponse) IsUpdateInProgress() bool {
+	return r.FirmwareRevision1&0x80 != 0
+}
+
+// FirmwareMajor is the major firmware revision
+func (r *DeviceIDResponse) FirmwareMajor() uint8 {
+	return r.FirmwareRevision1 & 0x7f
+}
+
+// FirmwareMinor is the BCD encoded minor firmware revision, decoded
+func (r *DeviceIDResponse) FirmwareMinor() uint8 {
+	return fromBCD(r.FirmwareRevision2)
+}
+
+// FirmwareVersion formats the firmware revision the way ipmitool does, "2.41" for 0x02 0x41
+func (r *DeviceIDResponse) FirmwareVersion() string {
+	return fmt.Sprintf("%d.%02d", r.FirmwareMajor(), r.FirmwareMinor())
+}
+
+// IPMIVersionMajor is the major IPMI version the device implements
+func (r *DeviceIDResponse) IPMIVersionMajor() uint8 {
+	return r.IPMIVersion & 0x0f
+}
+
+// IPMIVersionMinor is the minor IPMI version the device implements
+func (r *DeviceIDResponse) IPMIVersionMinor() uint8 {
+	return r.IPMIVersion >> 4
+}
+
+// IPMIVersionString formats the IPMI version, "1.5" or "2.0"
+func (r *DeviceIDResponse) IPMIVersionString() string {
+	return fmt.Sprintf("%d.%d", r.IPMIVersionMajor(), r.IPMIVersionMinor())
+}
+
+// Capabilities decodes the additional device support field
+func (r *DeviceIDResponse) Capabilities() *DeviceCapabilities {
+	support := r.AdditionalDeviceSupport
+	return &DeviceCapabilities{
+		Sensor:             support&DeviceSupportSensor != 0,
+		SDRRepository:      support&DeviceSupportSDRRepository != 0,
+		SEL:                support&DeviceSupportSEL != 0,
+		FRUInventory:       support&DeviceSupportFRUInventory != 0,
+		IPMBEventReceiver:  support&DeviceSupportIPMBEventReceiver != 0,
+		IPMBEventGenerator: support&DeviceSupportIPMBEventGenerator != 0,
+		Bridge:             support&DeviceSupportBridge != 0,
+		Chassis:            support&DeviceSupportChassis != 0,
+		DeviceSDRs:         r.ProvidesDeviceSDRs(),
+		IPMIVersionMajor:   r.IPMIVersionMajor(),
+		IPMIVersionMinor:   r.IPMIVersionMinor(),
+	}
+}
+
+// fromBCD decodes a 2 digit binary coded decimal
+func fromBCD(b uint8) uint8 {
+	return (b>>4)*10 + b&0x0f
+}
+
+// ColdResetRequest per section 20.2
+type ColdResetRequest struct{}
+
+// ColdResetResponse per section 20.2
+type ColdResetResponse struct {
+	CompletionCode
+}
+
+// WarmResetRequest per section 20.3
+type WarmResetRequest struct{}
+
+// WarmResetResponse per section 20.3
+type WarmResetResponse struct {
+	CompletionCode
+}
+
+// SelfTestResultsRequest per section 20.4
+type SelfTestResultsRequest struct{}
+
+// SelfTestResultsResponse per section 20.4
+type SelfTestResultsResponse struct {
+	CompletionCode
+	Result uint8
+	Detail uint8
+}
+
+// Passed tells whether the self test passed or the BMC does not implement one
+func (r *SelfTestResultsResponse) Passed() bool {
+	return r.Result == SelfTestPassed || r.Result == SelfTestNotImplemented
+}
+
+// Failures describes the failed bits of a SelfTestCorrupted result
+func (r *SelfTestResultsResponse) Failures() []string {
+	if r.Result != SelfTestCorrupted {
+		return nil
+	}
+	var failures []string
+	for _, f := range selfTestFailures {
+		if r.Detail&f.bit != 0 {
+			failures = append(failures, f.desc)
+		}
+	}
+	return failures
+}
+
+func (r *SelfTestResultsResponse) String() string {
+	switch r.Result {
+	case SelfTestPassed:
+		return "passed"
+	case SelfTestNotImplemented:
+		return "not implemented"
+	case SelfTestCorrupted:
+		return "device error"
+	case SelfTestFatal:
+		return "fatal hardware error"
+	default:
+		return fmt.Sprintf("device specific error 0x%02x", r.Result)
+	}
+}
+
+// SetACPIPowerStateRequest per section 20.6, bit 7 of each state must be set for the state to change
+type SetACPIPowerStateRequest struct {
+	SystemState uint8
+	DeviceState uint8
+}
+
+// SetACPIPowerStateResponse per section 20.6
+type SetACPIPowerStateResponse struct {
+	CompletionCode
+}
+
+// ACPIPowerStateRequest per section 20.7
+type ACPIPowerStateRequest struct{}
+
+// ACPIPowerStateResponse per section 20.7
+type ACPIPowerStateResponse struct {
+	CompletionCode
+	SystemState uint8
+	DeviceState uint8
+}
+
+// System is the ACPI system power state
+func (r *ACPIPowerStateResponse) System() uint8 {
+	return r.SystemState & 0x7f
+}
+
+// Device is the ACPI device power state
+func (r *ACPIPowerStateResponse) Device() uint8 {
+	return r.DeviceState & 0x7f
+}
+
+// SystemString describes the ACPI system power state
+func (r *ACPIPowerStateResponse) SystemString() string {
+	if s, ok := acpiSystemStates[r.System()]; ok {
+		return s
+	}
+	return fmt.Sprintf("unknown (0x%02x)", r.System())
+}
+
+// DeviceString describes the ACPI device power state
+func (r *ACPIPowerStateResponse) DeviceString() string {
+	if s, ok := acpiDeviceStates[r.Device()]; ok {
+		return s
+	}
+	return fmt.Sprintf("unknown (0x%02x)", r.Device())
+}
+
+// UUID is an RFC 4122 UUID in network byte order
+type UUID [16]byte
+
+func (u UUID) String() string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
+
+// GUIDRequest is both the Get Device GUID request per section 20.8
+// and the Get System GUID request per section 22.14
+type GUIDRequest struct{}
+
+// GUIDResponse holds the GUID as sent by the BMC
+type GUIDResponse struct {
+	CompletionCode
+	GUID [16]byte
+}
+
+// UUID converts the GUID to an RFC 4122 UUID. IPMI sends the GUID
+// least significant byte first, the reverse of the RFC 4122 byte order
+func (r *GUIDResponse) UUID() UUID {
+	var u UUID
+	for i := range r.GUID {
+		u[i] = r.GUID[len(r.GUID)-1-i]
+	}
+	return u
+}
+
+// SMBIOSUUID converts a GUID sent in the SMBIOS encoding used by some BMCs, where only
+// the time_low, time_mid and time_hi_and_version fields are least significant byte first
+func (r *GUIDResponse) SMBIOSUUID() UUID {
+	u := UUID(r.GUID)
+	u[0], u[1], u[2], u[3] = r.GUID[3], r.GUID[2], r.GUID[1], r.GUID[0]
+	u[4], u[5] = r.GUID[5], r.GUID[4]
+	u[6], u[7] = r.GUID[7], r.GUID[6]
+	return u
+}
@@ -0,0 +1,98 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceIDParse(t *testing.T) {
+	res := &DeviceIDResponse{}
+	err := responseFromString("20 81 02 41 02 bf 80 b9 00 1b 08 00 00 01 02", res)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(1), res.Revision())
+	assert.True(t, res.ProvidesDeviceSDRs())
+	assert.False(t, res.IsUpdateInProgress())
+	assert.Equal(t, "2.41", res.FirmwareVersion())
+	assert.Equal(t, "2.0", res.IPMIVersionString())
+	assert.Equal(t, OemSupermicro47488, res.ManufacturerID)
+	assert.Equal(t, uint16(0x081b), res.ProductID)
+	assert.Equal(t, []uint8{0x00, 0x00, 0x01, 0x02}, res.AuxFirmwareRevision)
+
+	caps := res.Capabilities()
+	assert.True(t, caps.IPMI20())
+	assert.True(t, caps.Chassis)
+	assert.False(t, caps.Bridge)
+	assert.True(t, caps.IPMBEventGenerator)
+	assert.True(t, caps.SEL)
+	assert.True(t, caps.Sensor)
+
+	// no aux firmware revision
+	err = responseFromString("20 01 82 09 51 2c a2 02 00 00 00", res)
+	assert.NoError(t, err)
+	assert.True(t, res.IsUpdateInProgress())
+	assert.Equal(t, "2.09", res.FirmwareVersion())
+	assert.Equal(t, "1.5", res.IPMIVersionString())
+	assert.Equal(t, OemDell, res.ManufacturerID)
+	assert.Nil(t, res.AuxFirmwareRevision)
+	assert.False(t, res.Capabilities().IPMI20())
+
+	assert.Equal(t, ErrShortPacket, responseFromString("20 01 02 09 51 2c a2 02", res))
+}
+
+func TestSelfTestResultsParse(t *testing.T) {
+	res := &SelfTestResultsResponse{}
+	err := responseFromString("55 00", res)
+	assert.NoError(t, err)
+	assert.True(t, res.Passed())
+	assert.Nil(t, res.Failures())
+	assert.Equal(t, "passed", res.String())
+
+	err = responseFromString("57 88", res)
+	assert.NoError(t, err)
+	assert.False(t, res.Passed())
+	assert.Equal(t, "device error", res.String())
+	assert.Equal(t, []string{"Cannot access SEL device", "SDR Repository empty"}, res.Failures())
+}
+
+func TestGUIDParse(t *testing.T) {
+	res := &GUIDResponse{}
+	err := responseFromString("31 4e 4d 4f c0 b4 56 80 10 35 42 00 44 45 4c 4c", res)
+	assert.NoError(t, err)
+	assert.Equal(t, "4c4c4544-0042-3510-8056-b4c04f4d4e31", res.UUID().String())
+
+	// the same UUID in the SMBIOS encoding
+	err = responseFromString("44 45 4c 4c 42 00 10 35 80 56 b4 c0 4f 4d 4e 31", res)
+	assert.NoError(t, err)
+	assert.Equal(t, "4c4c4544-0042-3510-8056-b4c04f4d4e31", res.SMBIOSUUID().String())
+}
+
+func TestACPIPowerStateParse(t *testing.T) {
+	res := &ACPIPowerStateResponse{}
+	err := responseFromString("05 03", res)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(ACPISystemS5), res.System())
+	assert.Equal(t, "S5/G2: soft-off", res.SystemString())
+	assert.Equal(t, "D3", res.DeviceString())
+
+	err = responseFromString("15 2a", res)
+	assert.NoError(t, err)
+	assert.Equal(t, "unknown (0x15)", res.SystemString())
+	assert.Equal(t, "unknown", res.DeviceString())
+}
@@ -278,7 +278,7 @@ func (l *lan) getAuthCapabilities() error {
 		NetworkFunctionApp,
 		CommandGetAuthCapabilities,
 		AuthCapabilitiesRequest{
-			ChannelNumber: 0x0e, // lanChannelE
+			ChannelNumber: ChannelCurrent,
 			PrivLevel:     l.priv,
 		},
 	}
@@ -289,15 +289,14 @@ func (l *lan) getAuthCapabilities() error {
 	}
 
 	for _, t := range []uint8{AuthTypeMD5, AuthTypePassword, AuthTypeNone} {
-		if (res.AuthTypeSupport & (1 << t)) != 0 {
+		if res.SupportsAuthType(t) {
 			l.AuthType = t
-			break
+			return nil
 		}
-		log.Printf("BMC did not offer a supported AuthType")
-		return CompletionCode(0xd4)
 	}
 
-	return nil
+	log.Printf("BMC did not offer a supported AuthType")
+	return ErrPrivLevel
 }
 
 func (l *lan) getSessionChallenge() (*SessionChallengeResponse, error) {
@@ -377,7 +376,7 @@ func (l *lan) closeSession() error {
 	req := &Request{
 		NetworkFunctionApp,
 		CommandCloseSession,
-		CloseSessionRequest{
+		&CloseSessionRequest{
 			SessionID: l.SessionID,
 		},
 	}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"encoding"
 	"encoding/binary"
+	"net"
 
 	"io"
 )
@@ -45,11 +46,12 @@ type NetworkFunction uint8
 
 // Network Function Codes per section 5.1
 var (
-	NetworkFunctionChassis     = NetworkFunction(0x00)
-	NetworkFunctionSensorEvent = NetworkFunction(0x04)
-	NetworkFunctionApp         = NetworkFunction(0x06)
-	NetworkFunctionStorge      = NetworkFunction(0x0A)
-	NetworkFunctionTransport   = NetworkFunction(0x0C)
+	NetworkFunctionChassis        = NetworkFunction(0x00)
+	NetworkFunctionSensorEvent    = NetworkFunction(0x04)
+	NetworkFunctionApp            = NetworkFunction(0x06)
+	NetworkFunctionStorge         = NetworkFunction(0x0A)
+	NetworkFunctionTransport      = NetworkFunction(0x0C)
+	NetworkFunctionGroupExtension = NetworkFunction(0x2C)
 )
 
 var (
@@ -64,8 +66,9 @@ type Message struct {
 	*ipmiSession
 	AuthCode [16]byte
 	*ipmiHeader
-	Data      []byte
-	RequestID string
+	Data       []byte
+	RequestID  string
+	RemoteAddr net.Addr // the sender of a request received by the Simulator
 }
 
 // NetFn returns the NetworkFunction portion of the NetFn/RsLUN field
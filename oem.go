@@ -21,7 +21,8 @@ import "fmt"
 // OemID aka IANA assigned Enterprise Number per:
 // http://www.iana.org/assignments/enterprise-numbers/enterprise-numbers
 // Note that constants defined here are the same subset that ipmitool recognizes.
-type OemID uint16
+// Enterprise numbers are 20 bits, sent in 3 bytes least significant byte first.
+type OemID uint32
 
 // IANA assigned manufacturer IDs
 const (
@@ -25,4 +25,6 @@ import (
 func TestOEM(t *testing.T) {
 	assert.Equal(t, "Dell Inc", OemDell.String())
 	assert.Equal(t, "Hewlett-Packard", OemHP.String())
+	assert.Equal(t, "Supermicro", OemSupermicro47488.String())
+	assert.Equal(t, "Unknown (1048575)", OemID(0xfffff).String())
 }
@@ -0,0 +1,470 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidPET is returned when a packet is not a Platform Event Trap
+var ErrInvalidPET = errors.New("not a valid Platform Event Trap")
+
+// PET object identifiers per the Platform Event Trap Format Specification v1.0
+const (
+	PETEnterprise = "1.3.6.1.4.1.3183.1.1"
+	petVarbind    = PETEnterprise + ".1"
+)
+
+// PETUTCOffsetUnspecified is the UTC offset of a trap that does not give one
+const PETUTCOffsetUnspecified = -1
+
+// petPort is the SNMP trap port
+const petPort = 162
+
+// petEpoch is the origin of the PET local timestamp
+var petEpoch = time.Date(1998, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// SNMP v1 generic trap of PET
+const snmpTrapEnterpriseSpecific = 6
+
+// size of the PET varbind data up to the OEM custom fields
+const petDataSize = 46
+
+// PETEvent is a decoded Platform Event Trap, the event itself being in the SEL event model
+type PETEvent struct {
+	SELEvent
+	Source         *net.UDPAddr // set by the PETListener
+	Community      string
+	AgentAddr      net.IP
+	Uptime         time.Duration
+	GUID           [16]byte
+	Sequence       uint16
+	UTCOffset      int // minutes, PETUTCOffsetUnspecified if not given
+	TrapSource     uint8
+	EventSource    uint8
+	Severity       uint8 // PEFSeverity*
+	SensorDevice   uint8 // slave address of the sensor owner
+	Entity         uint8
+	EntityInstance uint8
+	Language       uint8
+	ManufacturerID OemID
+	SystemID       uint16
+	OEMData        []byte // OEM custom fields
+}
+
+// SpecificTrap is the SNMP specific trap number of the event
+func (e *PETEvent) SpecificTrap() uint32 {
+	return uint32(e.SensorType)<<16 | uint32(e.EventType)<<8 |
+		uint32(boolBit(e.Deassertion, 0x80)) | uint32(e.Offset())
+}
+
+// localTimestamp is the PET timestamp, the local time of the system in seconds since 1998, 0 if unspecified
+func (e *PETEvent) localTimestamp() uint32 {
+	if e.Timestamp.IsZero() {
+		return 0
+	}
+	local := e.Timestamp
+	if e.UTCOffset != PETUTCOffsetUnspecified {
+		local = local.Add(time.Duration(e.UTCOffset) * time.Minute)
+	}
+	return uint32(local.Unix() - petEpoch.Unix())
+}
+
+// BER tags of the SNMP v1 trap message
+const (
+	berInteger     = 0x02
+	berOctetString = 0x04
+	berOID         = 0x06
+	berSequence    = 0x30
+	berIPAddress   = 0x40
+	berTimeTicks   = 0x43
+	berTrapPDU     = 0xa4
+)
+
+// berRead reads a BER value with the given tag, returning its contents and the bytes after it
+func berRead(buf []byte, tag uint8) ([]byte, []byte, error) {
+	if len(buf) < 2 || buf[0] != tag {
+		return nil, nil, ErrInvalidPET
+	}
+	n := int(buf[1])
+	buf = buf[2:]
+	if n&0x80 != 0 {
+		size := n & 0x7f
+		if size == 0 || size > 2 || len(buf) < size {
+			return nil, nil, ErrInvalidPET
+		}
+		n = 0
+		for _, b := range buf[:size] {
+			n = n<<8 | int(b)
+		}
+		buf = buf[size:]
+	}
+	if len(buf) < n {
+		return nil, nil, ErrInvalidPET
+	}
+	return buf[:n], buf[n:], nil
+}
+
+// berReadInt reads an integer value with the given tag
+func berReadInt(buf []byte, tag uint8) (int64, []byte, error) {
+	data, rest, err := berRead(buf, tag)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(data) == 0 || len(data) > 8 {
+		return 0, nil, ErrInvalidPET
+	}
+	v := int64(int8(data[0])) // sign extension
+	for _, b := range data[1:] {
+		v = v<<8 | int64(b)
+	}
+	return v, rest, nil
+}
+
+// berReadOID reads an object identifier in its dotted form
+func berReadOID(buf []byte) (string, []byte, error) {
+	data, rest, err := berRead(buf, berOID)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(data) == 0 {
+		return "", nil, ErrInvalidPET
+	}
+	ids := []string{strconv.Itoa(int(data[0]) / 40), strconv.Itoa(int(data[0]) % 40)}
+	id := 0
+	for _, b := range data[1:] {
+		id = id<<7 | int(b&0x7f)
+		if b&0x80 == 0 {
+			ids = append(ids, strconv.Itoa(id))
+			id = 0
+		}
+	}
+	return strings.Join(ids, "."), rest, nil
+}
+
+// berTLV encodes a BER value
+func berTLV(tag uint8, data ...[]byte) []byte {
+	var value []byte
+	for _, d := range data {
+		value = append(value, d...)
+	}
+	n := len(value)
+	switch {
+	case n < 0x80:
+		return append([]byte{tag, uint8(n)}, value...)
+	case n <= 0xff:
+		return append([]byte{tag, 0x81, uint8(n)}, value...)
+	default:
+		return append([]byte{tag, 0x82, uint8(n >> 8), uint8(n)}, value...)
+	}
+}
+
+// berInt encodes an integer in its shortest two's complement form
+func berInt(tag uint8, v int64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(v))
+	for len(buf) > 1 && (buf[0] == 0 && buf[1]&0x80 == 0 || buf[0] == 0xff && buf[1]&0x80 != 0) {
+		buf = buf[1:]
+	}
+	return berTLV(tag, buf)
+}
+
+// berOIDValue encodes an object identifier from its dotted form
+func berOIDValue(oid string) []byte {
+	var ids []int
+	for _, s := range strings.Split(oid, ".") {
+		id, _ := strconv.Atoi(s)
+		ids = append(ids, id)
+	}
+	data := []byte{uint8(ids[0]*40 + ids[1])}
+	for _, id := range ids[2:] {
+		var enc []byte
+		for {
+			enc = append([]byte{uint8(id & 0x7f)}, enc...)
+			id >>= 7
+			if id == 0 {
+				break
+			}
+		}
+		for i := 0; i < len(enc)-1; i++ {
+			enc[i] |= 0x80
+		}
+		data = append(data, enc...)
+	}
+	return berTLV(berOID, data)
+}
+
+// MarshalBinary encodes the event as an SNMP v1 trap message
+func (e *PETEvent) MarshalBinary() ([]byte, error) {
+	data := make([]byte, petDataSize, petDataSize+len(e.OEMData)+1)
+	copy(data[0:16], e.GUID[:])
+	binary.BigEndian.PutUint16(data[16:], e.Sequence)
+	binary.BigEndian.PutUint32(data[18:], e.localTimestamp())
+	binary.BigEndian.PutUint16(data[22:], uint16(int16(e.UTCOffset)))
+	data[24] = e.TrapSource
+	data[25] = e.EventSource
+	data[26] = e.Severity
+	data[27] = e.SensorDevice
+	data[28] = e.SensorNumber
+	data[29] = e.Entity
+	data[30] = e.EntityInstance
+	copy(data[31:34], e.EventData[:])
+	for i := 34; i < 39; i++ {
+		data[i] = 0xff // event data 4 to 8 are unspecified
+	}
+	data[39] = e.Language
+	binary.BigEndian.PutUint32(data[40:], uint32(e.ManufacturerID))
+	binary.BigEndian.PutUint16(data[44:], e.SystemID)
+	if len(e.OEMData) != 0 {
+		data = append(append(data, e.OEMData...), 0xc1)
+	}
+
+	agent := e.AgentAddr.To4()
+	if agent == nil {
+		agent = net.IPv4zero.To4()
+	}
+
+	pdu := berTLV(berTrapPDU,
+		berOIDValue(PETEnterprise),
+		berTLV(berIPAddress, agent),
+		berInt(berInteger, snmpTrapEnterpriseSpecific),
+		berInt(berInteger, int64(e.SpecificTrap())),
+		berInt(berTimeTicks, int64(e.Uptime/(10*time.Millisecond))),
+		berTLV(berSequence, berTLV(berSequence, berOIDValue(petVarbind), berTLV(berOctetString, data))),
+	)
+	return berTLV(berSequence, berInt(berInteger, 0), berTLV(berOctetString, []byte(e.Community)), pdu), nil
+}
+
+// UnmarshalBinary decodes an SNMP v1 trap message, returning ErrInvalidPET if it is not a PET
+func (e *PETEvent) UnmarshalBinary(buf []byte) error {
+	msg, _, err := berRead(buf, berSequence)
+	if err != nil {
+		return err
+	}
+	version, msg, err := berReadInt(msg, berInteger)
+	if err != nil || version != 0 {
+		return ErrInvalidPET
+	}
+	community, msg, err := berRead(msg, berOctetString)
+	if err != nil {
+		return err
+	}
+	pdu, _, err := berRead(msg, berTrapPDU)
+	if err != nil {
+		return err
+	}
+
+	enterprise, pdu, err := berReadOID(pdu)
+	if err != nil || enterprise != PETEnterprise {
+		return ErrInvalidPET
+	}
+	agent, pdu, err := berRead(pdu, berIPAddress)
+	if err != nil || len(agent) != net.IPv4len {
+		return ErrInvalidPET
+	}
+	generic, pdu, err := berReadInt(pdu, berInteger)
+	if err != nil || generic != snmpTrapEnterpriseSpecific {
+		return ErrInvalidPET
+	}
+	specific, pdu, err := berReadInt(pdu, berInteger)
+	if err != nil {
+		return err
+	}
+	ticks, pdu, err := berReadInt(pdu, berTimeTicks)
+	if err != nil {
+		return err
+	}
+	varbinds, _, err := berRead(pdu, berSequence)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	for len(varbinds) != 0 && data == nil {
+		var varbind []byte
+		varbind, varbinds, err = berRead(varbinds, berSequence)
+		if err != nil {
+			return err
+		}
+		name, value, err := berReadOID(varbind)
+		if err != nil {
+			return err
+		}
+		if name == petVarbind {
+			if data, _, err = berRead(value, berOctetString); err != nil {
+				return err
+			}
+		}
+	}
+	if len(data) < petDataSize {
+		return ErrInvalidPET
+	}
+
+	*e = PETEvent{
+		Community:      string(community),
+		AgentAddr:      net.IP(append([]byte(nil), agent...)),
+		Uptime:         time.Duration(ticks) * 10 * time.Millisecond,
+		Sequence:       binary.BigEndian.Uint16(data[16:]),
+		UTCOffset:      int(int16(binary.BigEndian.Uint16(data[22:]))),
+		TrapSource:     data[24],
+		EventSource:    data[25],
+		Severity:       data[26],
+		SensorDevice:   data[27],
+		Entity:         data[29],
+		EntityInstance: data[30],
+		Language:       data[39],
+		ManufacturerID: OemID(binary.BigEndian.Uint32(data[40:])),
+		SystemID:       binary.BigEndian.Uint16(data[44:]),
+	}
+	copy(e.GUID[:], data[0:16])
+	if len(data) > petDataSize {
+		e.OEMData = append([]byte(nil), data[petDataSize:]...)
+		if n := len(e.OEMData); e.OEMData[n-1] == 0xc1 {
+			e.OEMData = e.OEMData[:n-1]
+		}
+	}
+
+	e.RecordType = SELRecordSystemEvent
+	e.EvMRev = SELEvMRev
+	e.GeneratorID = uint16(e.SensorDevice)
+	e.SensorType = SDRSensorType(specific >> 16)
+	e.EventType = SDRSensorReadingType(specific >> 8 & 0x7f)
+	e.Deassertion = specific&0x80 != 0
+	e.SensorNumber = data[28]
+	copy(e.EventData[:], data[31:34])
+	if ts := binary.BigEndian.Uint32(data[18:]); ts != 0 {
+		// the timestamp is the local time of the system
+		e.Timestamp = petEpoch.Add(time.Duration(ts) * time.Second)
+		if e.UTCOffset != PETUTCOffsetUnspecified {
+			e.Timestamp = e.Timestamp.Add(-time.Duration(e.UTCOffset) * time.Minute)
+		}
+	}
+
+	return nil
+}
+
+func (e *PETEvent) String() string {
+	dir := "assertion"
+	if e.Deassertion {
+		dir = "deassertion"
+	}
+	return fmt.Sprintf("%s sensor %d %s event 0x%02x offset %d from %s",
+		e.SensorTypeName(), e.SensorNumber, dir, uint8(e.EventType), e.Offset(), e.AgentAddr)
+}
+
+// PETListener receives Platform Event Traps
+type PETListener struct {
+	// ErrorHandler, if set, is called with the source of each datagram that
+	// could not be decoded as a PET. Such datagrams are otherwise dropped.
+	ErrorHandler func(net.Addr, error)
+
+	wg     sync.WaitGroup
+	once   sync.Once
+	addr   net.UDPAddr
+	conn   *net.UDPConn
+	events chan *PETEvent
+	done   chan struct{}
+}
+
+// NewPETListener constructs a PETListener with the given addr, port 162 if the port is 0
+func NewPETListener(addr net.UDPAddr) *PETListener {
+	if addr.Port == 0 {
+		addr.Port = petPort
+	}
+	return &PETListener{
+		addr:   addr,
+		events: make(chan *PETEvent, 16),
+		done:   make(chan struct{}),
+	}
+}
+
+// Run the PETListener, decoded traps are sent to Events
+func (l *PETListener) Run() error {
+	var err error
+	l.conn, err = net.ListenUDP("udp4", &l.addr)
+	if err != nil {
+		return err
+	}
+
+	l.wg.Add(1)
+
+	go func() {
+		_ = l.serve()
+		close(l.events)
+		l.wg.Done()
+	}()
+
+	return nil
+}
+
+// Stop the PETListener, Events is closed once it has stopped.
+// It is safe to call Stop more than once.
+func (l *PETListener) Stop() {
+	l.once.Do(func() {
+		close(l.done)
+		if l.conn != nil {
+			_ = l.conn.Close()
+		}
+	})
+	l.wg.Wait()
+}
+
+// Events is the channel of the decoded traps
+func (l *PETListener) Events() <-chan *PETEvent {
+	return l.events
+}
+
+// LocalAddr returns the address the listener is bound to.
+func (l *PETListener) LocalAddr() *net.UDPAddr {
+	if l.conn != nil {
+		return l.conn.LocalAddr().(*net.UDPAddr)
+	}
+	return &l.addr
+}
+
+func (l *PETListener) serve() error {
+	buf := make([]byte, ipmiBufSize)
+
+	for {
+		n, addr, err := l.conn.ReadFromUDP(buf)
+		if err != nil {
+			return err // conn closed
+		}
+
+		e := &PETEvent{}
+		if err := e.UnmarshalBinary(buf[:n]); err != nil {
+			if l.ErrorHandler != nil {
+				l.ErrorHandler(addr, err)
+			}
+			continue
+		}
+		e.Source = addr
+
+		select {
+		case l.events <- e:
+		case <-l.done:
+			return nil
+		}
+	}
+}
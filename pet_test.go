@@ -0,0 +1,138 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// a temperature lower critical going high trap from 10.0.0.5
+var testPET = rawDecode("30 6a 02 01 00 04 06 70 75 62 6c 69 63 a4 5d 06 09 2b 06 01 04 01 98 6f 01 01 " +
+	"40 04 0a 00 00 05 02 01 06 02 03 01 01 01 43 02 03 e8 30 3e 30 3c 06 0a 2b 06 01 04 01 98 6f 01 01 01 " +
+	"04 2e 00 01 02 03 04 05 06 07 08 09 0a 0b 0c 0d 0e 0f 00 05 29 61 04 80 ff ff 20 20 10 20 30 07 01 " +
+	"51 00 00 ff ff ff ff ff 19 00 00 01 a3 01 02")
+
+func TestPETEvent(t *testing.T) {
+	event := &PETEvent{}
+	err := event.UnmarshalBinary(testPET)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "public", event.Community)
+	assert.Equal(t, "10.0.0.5", event.AgentAddr.String())
+	assert.Equal(t, 10*time.Second, event.Uptime)
+	assert.Equal(t, [16]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}, event.GUID)
+	assert.Equal(t, uint16(5), event.Sequence)
+	assert.Equal(t, PETUTCOffsetUnspecified, event.UTCOffset)
+	assert.Equal(t, uint8(PEFSeverityCritical), event.Severity)
+	assert.Equal(t, uint8(0x20), event.SensorDevice)
+	assert.Equal(t, uint8(0x07), event.Entity)
+	assert.Equal(t, uint8(0x01), event.EntityInstance)
+	assert.Equal(t, uint8(0x19), event.Language)
+	assert.Equal(t, OemID(0x01a3), event.ManufacturerID)
+	assert.Equal(t, uint16(0x0102), event.SystemID)
+	assert.Nil(t, event.OEMData)
+
+	// the event itself in the SEL event model
+	assert.Equal(t, SELEvent{
+		RecordType:   SELRecordSystemEvent,
+		Timestamp:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		GeneratorID:  0x20,
+		EvMRev:       SELEvMRev,
+		SensorType:   SDR_SENSOR_TYPECODES_TEMPERATURE,
+		SensorNumber: 0x30,
+		EventType:    SENSOR_READTYPE_THREADHOLD,
+		EventData:    [3]uint8{0x51, 0x00, 0x00},
+	}, event.SELEvent)
+	assert.Equal(t, uint32(0x010101), event.SpecificTrap())
+
+	data, err := event.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, testPET, data)
+
+	// local timestamp with a UTC offset, OEM custom fields
+	event.UTCOffset = -300
+	event.Deassertion = true
+	event.OEMData = []byte{0xc0, 0x01}
+	data, err = event.MarshalBinary()
+	assert.NoError(t, err)
+
+	decoded := &PETEvent{}
+	err = decoded.UnmarshalBinary(data)
+	assert.NoError(t, err)
+	assert.Equal(t, event, decoded)
+
+	// not a PET
+	err = decoded.UnmarshalBinary(testPET[:40])
+	assert.Equal(t, ErrInvalidPET, err)
+	err = decoded.UnmarshalBinary(append(rawDecode("30 0a 02 01 01"), testPET[5:]...))
+	assert.Equal(t, ErrInvalidPET, err)
+	err = decoded.UnmarshalBinary(nil)
+	assert.Equal(t, ErrInvalidPET, err)
+}
+
+func TestPETListener(t *testing.T) {
+	errs := make(chan error, 1)
+	handler := func(_ net.Addr, err error) { errs <- err }
+
+	l := NewPETListener(net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 0})
+	l.ErrorHandler = handler
+	err := l.Run()
+	if err != nil {
+		// port 162 needs privileges
+		l = NewPETListener(net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 16200})
+		l.ErrorHandler = handler
+		err = l.Run()
+	}
+	assert.NoError(t, err)
+
+	conn, err := net.DialUDP("udp4", nil, l.LocalAddr())
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	// malformed packets are skipped
+	_, err = conn.Write([]byte("garbage"))
+	assert.NoError(t, err)
+	_, err = conn.Write(testPET)
+	assert.NoError(t, err)
+
+	select {
+	case event := <-l.Events():
+		assert.Equal(t, uint16(5), event.Sequence)
+		assert.Equal(t, conn.LocalAddr().String(), event.Source.String())
+	case <-time.After(5 * time.Second):
+		t.Fatal("no trap received")
+	}
+
+	// the garbage was handled before the trap that followed it
+	select {
+	case err := <-errs:
+		assert.Equal(t, ErrInvalidPET, err)
+	default:
+		t.Fatal("malformed packet not reported")
+	}
+
+	l.Stop()
+	_, ok := <-l.Events()
+	assert.False(t, ok)
+
+	// a second Stop is a no-op
+	l.Stop()
+}
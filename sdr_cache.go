@@ -0,0 +1,199 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// SDRCacheKey identifies the BMC model and firmware a SDR repository was read from
+type SDRCacheKey struct {
+	ManufacturerID    OemID
+	ProductID         uint16
+	FirmwareRevision1 uint8
+	FirmwareRevision2 uint8
+}
+
+func (k SDRCacheKey) String() string {
+	return fmt.Sprintf("%06x-%04x-%02x%02x", uint32(k.ManufacturerID), k.ProductID,
+		k.FirmwareRevision1, k.FirmwareRevision2)
+}
+
+type sdrCacheEntry struct {
+	addition uint32
+	erase    uint32
+	records  []*SDRRepositoryRecord
+}
+
+// SDRCache keeps the SDR repositories read from BMCs, so they only have to be walked
+// again when the repository changes, as reported by Get SDR Repository Info.
+// When Dir is set, repositories are also loaded from and saved to files there,
+// in the format of `ipmitool sdr dump`.
+type SDRCache struct {
+	Dir string
+
+	mu      sync.Mutex
+	entries map[SDRCacheKey]*sdrCacheEntry
+}
+
+// NewSDRCache creates a SDRCache, persisted to dir unless it is empty
+func NewSDRCache(dir string) *SDRCache {
+	return &SDRCache{
+		Dir:     dir,
+		entries: map[SDRCacheKey]*sdrCacheEntry{},
+	}
+}
+
+func (sc *SDRCache) path(key SDRCacheKey, info *SDRRepositoryInfoResponse) string {
+	name := fmt.Sprintf("%s-%08x-%08x.sdr", key, info.TimestampMostRecentAddition, info.TimestampMostRecentErase)
+	return filepath.Join(sc.Dir, name)
+}
+
+// Repository returns the SDR repository of the BMC c is connected to.
+// It costs a single Get SDR Repository Info request as long as the cached repository is current.
+func (sc *SDRCache) Repository(c *Client) ([]*SDRRepositoryRecord, error) {
+	id, err := c.cachedDeviceID()
+	if err != nil {
+		return nil, err
+	}
+	key := SDRCacheKey{
+		ManufacturerID:    id.ManufacturerID,
+		ProductID:         id.ProductID,
+		FirmwareRevision1: id.FirmwareRevision1,
+		FirmwareRevision2: id.FirmwareRevision2,
+	}
+
+	info, err := c.RepositoryInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	sc.mu.Lock()
+	entry, ok := sc.entries[key]
+	sc.mu.Unlock()
+	if ok && entry.addition == info.TimestampMostRecentAddition && entry.erase == info.TimestampMostRecentErase {
+		return entry.records, nil
+	}
+
+	var records []*SDRRepositoryRecord
+	if sc.Dir != "" {
+		records, err = LoadSDRDump(sc.path(key, info))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	if records == nil {
+		records, err = c.ReadSDRRepository()
+		if err != nil {
+			return nil, err
+		}
+		if sc.Dir != "" {
+			if err := SaveSDRDump(sc.path(key, info), records); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sc.mu.Lock()
+	sc.entries[key] = &sdrCacheEntry{
+		addition: info.TimestampMostRecentAddition,
+		erase:    info.TimestampMostRecentErase,
+		records:  records,
+	}
+	sc.mu.Unlock()
+
+	return records, nil
+}
+
+// WriteSDRDump writes the raw records back to back, as `ipmitool sdr dump` does
+func WriteSDRDump(w io.Writer, records []*SDRRepositoryRecord) error {
+	for _, record := range records {
+		if _, err := w.Write(record.Raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadSDRDump reads records written by WriteSDRDump or `ipmitool sdr dump`
+func ReadSDRDump(r io.Reader) ([]*SDRRepositoryRecord, error) {
+	records := make([]*SDRRepositoryRecord, 0, 30)
+	for {
+		header := make([]byte, 5)
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF {
+				return records, nil
+			}
+			return nil, err
+		}
+
+		raw := make([]byte, 5+int(header[4]))
+		copy(raw, header)
+		if _, err := io.ReadFull(r, raw[5:]); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+
+		record, err := UnmarshalSDRRecord(raw)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, &SDRRepositoryRecord{record, raw})
+	}
+}
+
+// SaveSDRDump writes records to the file at path, which `ipmitool -S` can read
+func SaveSDRDump(path string, records []*SDRRepositoryRecord) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	err = WriteSDRDump(w, records)
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// LoadSDRDump reads records from a file written by SaveSDRDump or `ipmitool sdr dump`
+func LoadSDRDump(path string) ([]*SDRRepositoryRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadSDRDump(bufio.NewReader(f))
+}
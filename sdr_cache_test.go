@@ -0,0 +1,142 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"bytes"
+	"encoding"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSDRDump(t *testing.T) {
+	r1, _ := NewSDRFullSensor(1, "Fan 1")
+	r2, _ := NewSDRFruDeviceLocator(2, "PSU1")
+	r3 := NewSDROEMRecord(3, 674, []byte{0x01, 0x02})
+
+	var records []*SDRRepositoryRecord
+	for _, r := range []SDRRecord{r1, r2, r3} {
+		raw := mustMarshal(t, r)
+		rec, err := UnmarshalSDRRecord(raw)
+		assert.NoError(t, err)
+		records = append(records, &SDRRepositoryRecord{rec, raw})
+	}
+
+	buf := new(bytes.Buffer)
+	err := WriteSDRDump(buf, records)
+	assert.NoError(t, err)
+
+	loaded, err := ReadSDRDump(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, records, loaded)
+
+	_, err = ReadSDRDump(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+
+	path := filepath.Join(t.TempDir(), "dump.sdr")
+	err = SaveSDRDump(path, records)
+	assert.NoError(t, err)
+	loaded, err = LoadSDRDump(path)
+	assert.NoError(t, err)
+	assert.Equal(t, records, loaded)
+}
+
+func mustMarshal(t *testing.T, r SDRRecord) []byte {
+	data, err := r.(encoding.BinaryMarshaler).MarshalBinary()
+	assert.NoError(t, err)
+	return data
+}
+
+func TestSDRCache(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	info := &SDRRepositoryInfoResponse{
+		CompletionCode:              CommandCompleted,
+		SDRVersion:                  0x51,
+		TimestampMostRecentAddition: 0x5a000000,
+		TimestampMostRecentErase:    0x59000000,
+	}
+	s.SetHandler(NetworkFunctionStorge, CommandGetSDRRepositoryInfo, func(*Message) Response {
+		return info
+	})
+
+	getSDR := s.getSDR
+	walks := 0
+	// the handlers run on the simulator goroutine, under s.mu
+	walked := func() int {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return walks
+	}
+	s.SetHandler(NetworkFunctionStorge, CommandGetSDR, func(m *Message) Response {
+		request := &GetSDRCommandRequest{}
+		if err := m.Request(request); err != nil {
+			return err
+		}
+		if request.RecordID == 0 && request.OffsetIntoRecord == 0 {
+			walks++
+		}
+		return getSDR(m)
+	})
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+	cache := NewSDRCache(dir)
+
+	records, err := cache.Repository(client)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, walked())
+	assert.Equal(t, 2, len(records))
+
+	records, err = cache.Repository(client)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, walked())
+	assert.Equal(t, 2, len(records))
+
+	// a new cache on the same directory loads the dump
+	records, err = NewSDRCache(dir).Repository(client)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, walked())
+	assert.Equal(t, 2, len(records))
+
+	// a record was added
+	s.mu.Lock()
+	info.TimestampMostRecentAddition++
+	s.mu.Unlock()
+	records, err = cache.Repository(client)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, walked())
+	assert.Equal(t, 2, len(records))
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.sdr"))
+	assert.Equal(t, 2, len(matches))
+
+	err = client.Close()
+	assert.NoError(t, err)
+	s.Stop()
+}
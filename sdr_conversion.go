@@ -0,0 +1,266 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"errors"
+	"math"
+)
+
+// section 43.1, analog data format in the sensor units 1 byte
+const (
+	SDR_ANALOG_FORMAT_UNSIGNED      = 0x00
+	SDR_ANALOG_FORMAT_1S_COMPLEMENT = 0x01
+	SDR_ANALOG_FORMAT_2S_COMPLEMENT = 0x02
+	SDR_ANALOG_FORMAT_NONE          = 0x03
+)
+
+// section 36.3, linearization functions
+const (
+	SDR_LINEARIZATION_LINEAR      = 0x00
+	SDR_LINEARIZATION_LN          = 0x01
+	SDR_LINEARIZATION_LOG10       = 0x02
+	SDR_LINEARIZATION_LOG2        = 0x03
+	SDR_LINEARIZATION_E           = 0x04
+	SDR_LINEARIZATION_EXP10       = 0x05
+	SDR_LINEARIZATION_EXP2        = 0x06
+	SDR_LINEARIZATION_1_X         = 0x07
+	SDR_LINEARIZATION_SQR         = 0x08
+	SDR_LINEARIZATION_CUBE        = 0x09
+	SDR_LINEARIZATION_SQRT        = 0x0a
+	SDR_LINEARIZATION_CUBE_ROOT   = 0x0b
+	SDR_LINEARIZATION_NONLINEAR_L = 0x70
+	SDR_LINEARIZATION_NONLINEAR_H = 0x7f
+)
+
+var (
+	ErrLinearizationNotSupport = errors.New("Linearization not support")
+)
+
+// SensorFactors holds the reading conversion factors, laid out as in bytes 25:30 of the
+// full sensor record and in the Get Sensor Reading Factors response (section 35.5)
+type SensorFactors struct {
+	MTol  uint16 // M, tolerance
+	Bacc  uint16 // B, accuracy
+	Acc   uint8  // accuracy, accuracy exp, sensor direction
+	RBexp uint8  // R (result) exp, B exp
+}
+
+// toSigned converts the low bits of a 2's complement value into a signed value
+func toSigned(v uint16, bits uint) int16 {
+	if v&(1<<(bits-1)) != 0 {
+		return int16(v) - int16(1<<bits)
+	}
+	return int16(v)
+}
+
+// fromSigned converts a signed value into a 2's complement value of the given bits
+func fromSigned(v int16, bits uint) uint16 {
+	return uint16(v) & (1<<bits - 1)
+}
+
+// M: 10bit signed 2's complement
+// B: 10bit signed 2's complement
+// Bexp: 4bit signed 2's complement
+// Rexp: 4bit signed 2's complement
+func (f *SensorFactors) SetMBExp(M int16, B int16, Bexp int8, Rexp int8) {
+	_M := fromSigned(M, 10)
+	f.MTol = (f.MTol & 0x3f00) | (_M & 0x00ff) | ((_M << 6) & 0xc000)
+
+	_B := fromSigned(B, 10)
+	f.Bacc = (f.Bacc & 0x3f00) | (_B & 0x00ff) | ((_B << 6) & 0xc000)
+
+	f.RBexp = uint8(fromSigned(int16(Rexp), 4)<<4) | uint8(fromSigned(int16(Bexp), 4))
+}
+
+func (f *SensorFactors) GetMBExp() (M int16, B int16, Bexp int8, Rexp int8) {
+	M = toSigned(((f.MTol&0xc000)>>6)|(f.MTol&0x00ff), 10)
+	B = toSigned(((f.Bacc&0xc000)>>6)|(f.Bacc&0x00ff), 10)
+	Bexp = int8(toSigned(uint16(f.RBexp&0x0f), 4))
+	Rexp = int8(toSigned(uint16(f.RBexp>>4), 4))
+	return
+}
+
+// Tolerance returns the tolerance in +/- 1/2 raw counts
+func (f *SensorFactors) Tolerance() uint8 {
+	return uint8(f.MTol>>8) & 0x3f
+}
+
+func (f *SensorFactors) SetTolerance(tolerance uint8) {
+	f.MTol = (f.MTol & 0xc0ff) | uint16(tolerance&0x3f)<<8
+}
+
+// Accuracy returns the unsigned 10 bit accuracy in 1/100 percent and its exponent
+func (f *SensorFactors) Accuracy() (accuracy uint16, exp uint8) {
+	accuracy = (f.Bacc>>8)&0x3f | uint16(f.Acc&0xf0)<<2
+	exp = (f.Acc >> 2) & 0x03
+	return
+}
+
+func (f *SensorFactors) SetAccuracy(accuracy uint16, exp uint8) {
+	f.Bacc = (f.Bacc & 0xc0ff) | (accuracy&0x3f)<<8
+	f.Acc = (f.Acc & 0x03) | uint8(accuracy>>2)&0xf0 | (exp&0x03)<<2
+}
+
+// ToleranceValue returns the tolerance in the sensor units, before linearization
+func (f *SensorFactors) ToleranceValue() float64 {
+	M, _, _, Rexp := f.GetMBExp()
+	return math.Abs(float64(M)*float64(f.Tolerance())/2) * math.Pow(10, float64(Rexp))
+}
+
+// AccuracyPercent returns the accuracy in percent
+func (f *SensorFactors) AccuracyPercent() float64 {
+	accuracy, exp := f.Accuracy()
+	return float64(accuracy) * math.Pow(10, float64(exp)) / 100
+}
+
+// ConvertReading converts a raw reading into a value in the sensor units, section 36.3:
+// y = L[(M*x + B*10^Bexp) * 10^Rexp]
+func (f *SensorFactors) ConvertReading(format uint8, linearization uint8, raw uint8) (float64, error) {
+	var x float64
+	switch format {
+	case SDR_ANALOG_FORMAT_UNSIGNED:
+		x = float64(raw)
+	case SDR_ANALOG_FORMAT_1S_COMPLEMENT:
+		if raw&0x80 != 0 {
+			x = -float64(^raw)
+		} else {
+			x = float64(raw)
+		}
+	case SDR_ANALOG_FORMAT_2S_COMPLEMENT:
+		x = float64(int8(raw))
+	default:
+		return 0, ErrUnitNotSupport
+	}
+
+	M, B, Bexp, Rexp := f.GetMBExp()
+	y := (float64(M)*x + float64(B)*math.Pow(10, float64(Bexp))) * math.Pow(10, float64(Rexp))
+	return linearize(linearization&0x7f, y)
+}
+
+// ConvertValue converts a value in the sensor units into the raw reading closest to it,
+// values out of the range of the analog data format are clamped
+func (f *SensorFactors) ConvertValue(format uint8, linearization uint8, value float64) (uint8, error) {
+	M, B, Bexp, Rexp := f.GetMBExp()
+	if M == 0 {
+		return 0, ErrMZero
+	}
+
+	y, err := unlinearize(linearization&0x7f, value)
+	if err != nil {
+		return 0, err
+	}
+	x := math.Round((y/math.Pow(10, float64(Rexp)) - float64(B)*math.Pow(10, float64(Bexp))) / float64(M))
+
+	switch format {
+	case SDR_ANALOG_FORMAT_UNSIGNED:
+		return uint8(math.Max(0, math.Min(255, x))), nil
+	case SDR_ANALOG_FORMAT_1S_COMPLEMENT:
+		x = math.Max(-127, math.Min(127, x))
+		if x < 0 {
+			return ^uint8(-x), nil
+		}
+		return uint8(x), nil
+	case SDR_ANALOG_FORMAT_2S_COMPLEMENT:
+		return uint8(int8(math.Max(-128, math.Min(127, x)))), nil
+	default:
+		return 0, ErrUnitNotSupport
+	}
+}
+
+// ConvertHysteresis converts a raw hysteresis into the sensor units, hysteresis has no offset
+func (f *SensorFactors) ConvertHysteresis(raw uint8) float64 {
+	M, _, _, Rexp := f.GetMBExp()
+	return math.Abs(float64(M)*float64(raw)) * math.Pow(10, float64(Rexp))
+}
+
+// HysteresisRaw converts a hysteresis in the sensor units into the closest raw hysteresis
+func (f *SensorFactors) HysteresisRaw(value float64) (uint8, error) {
+	M, _, _, Rexp := f.GetMBExp()
+	if M == 0 {
+		return 0, ErrMZero
+	}
+	x := math.Round(math.Abs(value / math.Pow(10, float64(Rexp)) / float64(M)))
+	return uint8(math.Min(255, x)), nil
+}
+
+func linearize(linearization uint8, y float64) (float64, error) {
+	switch linearization {
+	case SDR_LINEARIZATION_LINEAR:
+		return y, nil
+	case SDR_LINEARIZATION_LN:
+		return math.Log(y), nil
+	case SDR_LINEARIZATION_LOG10:
+		return math.Log10(y), nil
+	case SDR_LINEARIZATION_LOG2:
+		return math.Log2(y), nil
+	case SDR_LINEARIZATION_E:
+		return math.Exp(y), nil
+	case SDR_LINEARIZATION_EXP10:
+		return math.Pow(10, y), nil
+	case SDR_LINEARIZATION_EXP2:
+		return math.Exp2(y), nil
+	case SDR_LINEARIZATION_1_X:
+		return 1 / y, nil
+	case SDR_LINEARIZATION_SQR:
+		return y * y, nil
+	case SDR_LINEARIZATION_CUBE:
+		return y * y * y, nil
+	case SDR_LINEARIZATION_SQRT:
+		return math.Sqrt(y), nil
+	case SDR_LINEARIZATION_CUBE_ROOT:
+		return math.Cbrt(y), nil
+	}
+	// non-linear sensors are converted linearly with the factors of the reading
+	if linearization >= SDR_LINEARIZATION_NONLINEAR_L && linearization <= SDR_LINEARIZATION_NONLINEAR_H {
+		return y, nil
+	}
+	return 0, ErrLinearizationNotSupport
+}
+
+func unlinearize(linearization uint8, v float64) (float64, error) {
+	switch linearization {
+	case SDR_LINEARIZATION_LINEAR:
+		return v, nil
+	case SDR_LINEARIZATION_LN:
+		return math.Exp(v), nil
+	case SDR_LINEARIZATION_LOG10:
+		return math.Pow(10, v), nil
+	case SDR_LINEARIZATION_LOG2:
+		return math.Exp2(v), nil
+	case SDR_LINEARIZATION_E:
+		return math.Log(v), nil
+	case SDR_LINEARIZATION_EXP10:
+		return math.Log10(v), nil
+	case SDR_LINEARIZATION_EXP2:
+		return math.Log2(v), nil
+	case SDR_LINEARIZATION_1_X:
+		return 1 / v, nil
+	case SDR_LINEARIZATION_SQR:
+		return math.Sqrt(v), nil
+	case SDR_LINEARIZATION_CUBE:
+		return math.Cbrt(v), nil
+	case SDR_LINEARIZATION_SQRT:
+		return v * v, nil
+	case SDR_LINEARIZATION_CUBE_ROOT:
+		return v * v * v, nil
+	}
+	if linearization >= SDR_LINEARIZATION_NONLINEAR_L && linearization <= SDR_LINEARIZATION_NONLINEAR_H {
+		return v, nil
+	}
+	return 0, ErrLinearizationNotSupport
+}
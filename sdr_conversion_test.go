@@ -0,0 +1,146 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// readings as shown by ipmitool sensor list for the same factors
+func TestConvertReading(t *testing.T) {
+	tests := []struct {
+		name          string
+		format        uint8
+		linearization uint8
+		M, B          int16
+		Bexp, Rexp    int8
+		raw           uint8
+		value         float64
+	}{
+		{"Fan 1", SDR_ANALOG_FORMAT_UNSIGNED, SDR_LINEARIZATION_LINEAR, 63, 0, 0, 0, 0x29, 2583},
+		{"System 3.3V", SDR_ANALOG_FORMAT_UNSIGNED, SDR_LINEARIZATION_LINEAR, 2, 0, 0, -2, 0xa8, 3.36},
+		{"12V", SDR_ANALOG_FORMAT_UNSIGNED, SDR_LINEARIZATION_LINEAR, 59, 0, 0, -3, 0xcb, 11.977},
+		{"Inlet Temp", SDR_ANALOG_FORMAT_UNSIGNED, SDR_LINEARIZATION_LINEAR, 1, -128, 0, 0, 0xa5, 37},
+		{"Current", SDR_ANALOG_FORMAT_UNSIGNED, SDR_LINEARIZATION_LINEAR, 1, 5, -1, -1, 0x0c, 1.25},
+		{"CPU1 DTS", SDR_ANALOG_FORMAT_2S_COMPLEMENT, SDR_LINEARIZATION_LINEAR, 1, 0, 0, 0, 0xcf, -49},
+		{"CPU2 DTS", SDR_ANALOG_FORMAT_1S_COMPLEMENT, SDR_LINEARIZATION_LINEAR, 1, 0, 0, 0, 0xce, -49},
+		{"ones' zero", SDR_ANALOG_FORMAT_1S_COMPLEMENT, SDR_LINEARIZATION_LINEAR, 1, 0, 0, 0, 0xff, 0},
+		{"signed positive", SDR_ANALOG_FORMAT_2S_COMPLEMENT, SDR_LINEARIZATION_LINEAR, 2, 0, 0, 0, 0x7f, 254},
+		{"negative M", SDR_ANALOG_FORMAT_UNSIGNED, SDR_LINEARIZATION_LINEAR, -2, 0, 0, 0, 0x10, -32},
+		{"ln", SDR_ANALOG_FORMAT_UNSIGNED, SDR_LINEARIZATION_LN, 1, 0, 0, 0, 1, 0},
+		{"log10", SDR_ANALOG_FORMAT_UNSIGNED, SDR_LINEARIZATION_LOG10, 1, 0, 0, 0, 100, 2},
+		{"log2", SDR_ANALOG_FORMAT_UNSIGNED, SDR_LINEARIZATION_LOG2, 1, 0, 0, 0, 8, 3},
+		{"e", SDR_ANALOG_FORMAT_UNSIGNED, SDR_LINEARIZATION_E, 1, 0, 0, 0, 0, 1},
+		{"exp10", SDR_ANALOG_FORMAT_UNSIGNED, SDR_LINEARIZATION_EXP10, 1, 0, 0, -1, 20, 100},
+		{"exp2", SDR_ANALOG_FORMAT_UNSIGNED, SDR_LINEARIZATION_EXP2, 1, 0, 0, 0, 10, 1024},
+		{"1/x", SDR_ANALOG_FORMAT_UNSIGNED, SDR_LINEARIZATION_1_X, 1, 0, 0, 0, 4, 0.25},
+		{"sqr", SDR_ANALOG_FORMAT_UNSIGNED, SDR_LINEARIZATION_SQR, 1, 0, 0, 0, 10, 100},
+		{"cube", SDR_ANALOG_FORMAT_UNSIGNED, SDR_LINEARIZATION_CUBE, 1, 0, 0, 0, 3, 27},
+		{"sqrt", SDR_ANALOG_FORMAT_UNSIGNED, SDR_LINEARIZATION_SQRT, 1, 0, 0, 0, 49, 7},
+		{"cube root", SDR_ANALOG_FORMAT_UNSIGNED, SDR_LINEARIZATION_CUBE_ROOT, 1, 0, 0, 0, 27, 3},
+		{"non-linear", SDR_ANALOG_FORMAT_UNSIGNED, SDR_LINEARIZATION_NONLINEAR_L, 3, 0, 0, 0, 10, 30},
+	}
+
+	for _, test := range tests {
+		r, _ := NewSDRFullSensor(1, test.name)
+		r.Unit = test.format << 6
+		r.Linearization = test.linearization
+		r.SetMBExp(test.M, test.B, test.Bexp, test.Rexp)
+
+		value, err := r.ConvertReading(test.raw)
+		assert.NoError(t, err, test.name)
+		assert.InDelta(t, test.value, value, 1e-9, test.name)
+
+		raw, err := r.ConvertValue(test.value)
+		assert.NoError(t, err, test.name)
+		if test.name == "ones' zero" {
+			assert.Equal(t, uint8(0), raw, test.name)
+		} else {
+			assert.Equal(t, test.raw, raw, test.name)
+		}
+	}
+}
+
+func TestConvertReadingErrors(t *testing.T) {
+	r, _ := NewSDRFullSensor(1, "no reading")
+	r.Unit = SDR_ANALOG_FORMAT_NONE << 6
+	r.SetMBExp(1, 0, 0, 0)
+	_, err := r.ConvertReading(0x10)
+	assert.Equal(t, ErrUnitNotSupport, err)
+
+	r.Unit = 0
+	r.Linearization = 0x20
+	_, err = r.ConvertReading(0x10)
+	assert.Equal(t, ErrLinearizationNotSupport, err)
+
+	r.Linearization = SDR_LINEARIZATION_LINEAR
+	r.SetMBExp(0, 0, 0, 0)
+	_, err = r.ConvertValue(1)
+	assert.Equal(t, ErrMZero, err)
+}
+
+func TestConvertValueClamp(t *testing.T) {
+	r, _ := NewSDRFullSensor(1, "clamp")
+	r.SetMBExp(1, 0, 0, 0)
+	raw, _ := r.ConvertValue(-5)
+	assert.Equal(t, uint8(0), raw)
+	raw, _ = r.ConvertValue(300)
+	assert.Equal(t, uint8(0xff), raw)
+
+	r.Unit = SDR_ANALOG_FORMAT_2S_COMPLEMENT << 6
+	raw, _ = r.ConvertValue(-300)
+	assert.Equal(t, uint8(0x80), raw)
+
+	r.Unit = SDR_ANALOG_FORMAT_1S_COMPLEMENT << 6
+	raw, _ = r.ConvertValue(-300)
+	assert.Equal(t, uint8(0x80), raw)
+}
+
+func TestSensorFactorsToleranceAccuracy(t *testing.T) {
+	// M=63 tolerance 5, accuracy 101 exp 0, Rexp 0
+	f := &SensorFactors{MTol: 0x053f, Bacc: 0x2500, Acc: 0x10}
+	M, B, _, _ := f.GetMBExp()
+	assert.Equal(t, int16(63), M)
+	assert.Equal(t, int16(0), B)
+	assert.Equal(t, uint8(5), f.Tolerance())
+	assert.Equal(t, 157.5, f.ToleranceValue())
+	accuracy, exp := f.Accuracy()
+	assert.Equal(t, uint16(101), accuracy)
+	assert.Equal(t, uint8(0), exp)
+	assert.InDelta(t, 1.01, f.AccuracyPercent(), 1e-9)
+
+	// setting M and B keeps tolerance and accuracy
+	f.SetMBExp(-2, 5, 1, -2)
+	assert.Equal(t, uint8(5), f.Tolerance())
+	accuracy, _ = f.Accuracy()
+	assert.Equal(t, uint16(101), accuracy)
+	assert.Equal(t, 0.05, f.ToleranceValue())
+
+	f.SetTolerance(0x3f)
+	f.SetAccuracy(0x3ff, 3)
+	assert.Equal(t, uint8(0x3f), f.Tolerance())
+	accuracy, exp = f.Accuracy()
+	assert.Equal(t, uint16(0x3ff), accuracy)
+	assert.Equal(t, uint8(3), exp)
+	M, B, Bexp, Rexp := f.GetMBExp()
+	assert.Equal(t, int16(-2), M)
+	assert.Equal(t, int16(5), B)
+	assert.Equal(t, int8(1), Bexp)
+	assert.Equal(t, int8(-2), Rexp)
+}
@@ -18,19 +18,20 @@ package ipmi
 
 import (
 	"bytes"
+	"encoding"
 	"encoding/binary"
 	"errors"
 	//"fmt"
-	"math"
 )
 
 var (
 	ErrDeviceIdMustLess16   = errors.New("Device Id must be less or equal to 16 bytes length")
-	ErrUnitNotSupport       = errors.New("Unit not support, only support unsigned and 2's complement signed")
+	ErrUnitNotSupport       = errors.New("Unit not support, the sensor has no analog reading")
 	ErrMZero                = errors.New("M mustn't be 0")
 	ErrIdStringLenNotMatch  = errors.New("Length of the Id string is mismatch")
 	ErrSensorReadUnavail    = errors.New("Sensor Reading Unavailable")
 	ErrNotFoundTheSensorNum = errors.New("failed to found the SensorNumber")
+	ErrSDRRecordTooShort    = errors.New("SDR record is shorter than its header")
 )
 
 var sdrRecordValueBasicUnit []string = []string{
@@ -90,7 +91,7 @@ type sdrMcDeviceLocatorFields struct { //size 10
 
 	PSNGI     uint8
 	DeviceCap uint8
-	reserved  [3]byte
+	Reserved  [3]byte
 	EntityId  uint8
 	EntityIns uint8
 	OEM       uint8
@@ -143,13 +144,27 @@ func (r *SDRMcDeviceLocator) MarshalBinary() (data []byte, err error) {
 	return hb.Bytes(), nil
 }
 
-// section 43.9
+func (r *SDRMcDeviceLocator) UnmarshalBinary(data []byte) error {
+	buffer, err := unmarshalSDR(data, &r.SDRRecordHeader, &r.sdrMcDeviceLocatorFields)
+	if err != nil {
+		return err
+	}
+	r.deviceId, err = readSDRIDString(buffer)
+	return err
+}
+
+// HasFRUInventory reports whether the controller provides FRU inventory (FRU Device 0)
+func (r *SDRMcDeviceLocator) HasFRUInventory() bool {
+	return r.DeviceCap&0x08 != 0
+}
+
+// section 43.8
 type sdrFruDeviceLocatorFields struct { //size 10
 	DeviceAccAddr     uint8
 	FRUDeviceID       uint8
 	LogPhyAccLUNBusID uint8
 	ChannNum          uint8
-	reserved          uint8
+	Reserved          uint8
 	DeviceType        uint8
 	DevTypeModif      uint8
 	FruEntityId       uint8
@@ -204,6 +219,55 @@ func (r *SDRFruDeviceLocator) MarshalBinary() (data []byte, err error) {
 	return hb.Bytes(), nil
 }
 
+func (r *SDRFruDeviceLocator) UnmarshalBinary(data []byte) error {
+	buffer, err := unmarshalSDR(data, &r.SDRRecordHeader, &r.sdrFruDeviceLocatorFields)
+	if err != nil {
+		return err
+	}
+	r.deviceId, err = readSDRIDString(buffer)
+	return err
+}
+
+// IsLogical reports whether the record describes a logical FRU device,
+// accessed through Read FRU Data on the controller at DeviceAccAddr.
+// Otherwise it is a non-intelligent device accessed via Master Write-Read.
+func (r *SDRFruDeviceLocator) IsLogical() bool {
+	return r.LogPhyAccLUNBusID&0x80 != 0
+}
+
+// AccessLUN returns the LUN used to access the FRU device
+func (r *SDRFruDeviceLocator) AccessLUN() uint8 {
+	return (r.LogPhyAccLUNBusID >> 3) & 0x03
+}
+
+// PrivateBusID returns the private bus the FRU device sits on
+func (r *SDRFruDeviceLocator) PrivateBusID() uint8 {
+	return r.LogPhyAccLUNBusID & 0x07
+}
+
+// Channel returns the channel number used to access the FRU device
+func (r *SDRFruDeviceLocator) Channel() uint8 {
+	return r.ChannNum >> 4
+}
+
+// readSDRIDString reads the Device ID String Type/Length code and the string that follows it
+func readSDRIDString(buffer *bytes.Reader) (string, error) {
+	typeLen, err := buffer.ReadByte()
+	if err != nil {
+		return "", err
+	}
+
+	//bit 7:6 is the string type, bit 4:0 the length
+	idLen := int(typeLen & 0x1f)
+	id := make([]byte, idLen)
+	n, err := buffer.Read(id)
+	if idLen > 0 && (err != nil || n != idLen) {
+		return "", ErrIdStringLenNotMatch
+	}
+
+	return string(id), nil
+}
+
 // section 43.1
 type sdrFullSensorFields struct { //size 42
 	SensorOwnerId        uint8
@@ -274,112 +338,74 @@ func (r *SDRFullSensor) RecordType() SDRRecordType {
 	return r.Rtype
 }
 
+// Factors returns a copy of the reading conversion factors of the record
+func (r *SDRFullSensor) Factors() *SensorFactors {
+	return &SensorFactors{r.MTol, r.Bacc, r.Acc, r.RBexp}
+}
+
+func (r *SDRFullSensor) SetFactors(f *SensorFactors) {
+	r.MTol, r.Bacc, r.Acc, r.RBexp = f.MTol, f.Bacc, f.Acc, f.RBexp
+}
+
 //M: 10bit signed 2's complement
 //B: 10bit signed 2's complement
 //Bexp: 4bit signed 2's complement
 //Rexp: 4bit signed 2's complement
 func (r *SDRFullSensor) SetMBExp(M int16, B int16, Bexp int8, Rexp int8) {
+	f := r.Factors()
+	f.SetMBExp(M, B, Bexp, Rexp)
+	r.SetFactors(f)
+}
 
-	r.MTol = 0
-	r.Bacc = 0
-	r.RBexp = 0
-
-	_M := uint16(math.Abs(float64(M)))
-	_M = _M & 0x01ff //mask leave low 9bit
-	if M < 0 {
-		_M = (((^_M) + 1) & 0x01ff) | 0x0200
-	}
-	r.MTol = r.MTol | (_M & 0x00ff)
-	r.MTol = r.MTol | ((_M << 6) & 0xc000)
-
-	_B := uint16(math.Abs(float64(B)))
-	_B = _B & 0x01ff //mask leave low 9bit
-	if B < 0 {
-		_B = (((^_B) + 1) & 0x01ff) | 0x0200
-	}
-	r.Bacc = r.Bacc | (_B & 0x00ff)
-	r.Bacc = r.Bacc | ((_B << 6) & 0xc000)
-
-	_Bexp := uint8(math.Abs(float64(Bexp)))
-	_Bexp = _Bexp & 0x07 //mask leeve low 3bit
-	if Bexp < 0 {
-		_Bexp = (((^_Bexp) + 1) & 0x07) | 0x08
-	}
-	r.RBexp = r.RBexp | (_Bexp & 0x0f)
+func (r *SDRFullSensor) GetMBExp() (M int16, B int16, Bexp int8, Rexp int8) {
+	return r.Factors().GetMBExp()
+}
 
-	_Rexp := uint8(math.Abs(float64(Rexp)))
-	_Rexp = _Rexp & 0x07 //mask leave low 3bit
-	if Rexp < 0 {
-		_Rexp = (((^_Rexp) + 1) & 0x07) | 0x08
-	}
-	r.RBexp = r.RBexp | ((_Rexp << 4) & 0xf0)
+// AnalogDataFormat returns one of the SDR_ANALOG_FORMAT_* values
+func (r *SDRFullSensor) AnalogDataFormat() uint8 {
+	return (r.Unit & 0xc0) >> 6
+}
 
+// IsNonLinear reports whether the conversion factors change with the reading,
+// they have to be fetched with Get Sensor Reading Factors
+func (r *SDRFullSensor) IsNonLinear() bool {
+	return r.Linearization&0x7f >= SDR_LINEARIZATION_NONLINEAR_L
 }
 
-func (r *SDRFullSensor) GetMBExp() (M int16, B int16, Bexp int8, Rexp int8) {
-	_M := uint16(((r.MTol & 0xc000) >> 6) | (r.MTol & 0x00ff))
-	if (_M & 0x0200) == 0x0200 { //most significate is 1, mean signed
-		//fmt.Printf("%d,0x%x\n", int16((_M & 0xfdff)), (_M & 0xfdff))
-		M = int16((_M & 0xfdff)) - 512 //2^9
-	} else {
-		M = int16(_M & 0xfdff)
-	}
+// ConvertReading converts a raw reading into a value in the sensor units
+func (r *SDRFullSensor) ConvertReading(raw uint8) (float64, error) {
+	return r.Factors().ConvertReading(r.AnalogDataFormat(), r.Linearization, raw)
+}
 
-	_B := uint16(((r.Bacc & 0xc000) >> 6) | (r.Bacc & 0x00ff))
-	if (_B & 0x0200) == 0x0200 { //most significate is 1, mean signed
-		B = int16((_B & 0xfdff)) - 512 //2^9
-	} else {
-		B = int16(_B & 0xfdff)
-	}
+// ConvertValue converts a value in the sensor units into a raw reading
+func (r *SDRFullSensor) ConvertValue(value float64) (uint8, error) {
+	return r.Factors().ConvertValue(r.AnalogDataFormat(), r.Linearization, value)
+}
 
-	_Bexp := uint8(r.RBexp & 0x0f)
-	if (_Bexp & 0x08) == 0x08 {
-		Bexp = int8((_Bexp & 0xf7)) - 8 //2^3
-	} else {
-		Bexp = int8(_Bexp & 0xf7)
-	}
+// ThresholdAccess returns one of the SENSOR_ACCESS_* values
+func (r *SDRFullSensor) ThresholdAccess() uint8 {
+	return (r.SensorCap >> 2) & 0x03
+}
 
-	_Rexp := uint8((r.RBexp & 0xf0) >> 4)
-	if (_Rexp & 0x08) == 0x08 {
-		Rexp = int8((_Rexp & 0xf7)) - 8 //2^3
-	} else {
-		Rexp = int8(_Rexp & 0xf7)
-	}
+// HysteresisAccess returns one of the SENSOR_ACCESS_* values
+func (r *SDRFullSensor) HysteresisAccess() uint8 {
+	return (r.SensorCap >> 4) & 0x03
+}
 
-	return
+// ReadableThresholds returns the thresholds that can be read from a threshold sensor
+func (r *SDRFullSensor) ReadableThresholds() ThresholdStatus {
+	return ThresholdStatus(r.DiscreteReadingMask & 0x3f)
 }
 
-// calculate the given value into the SDR reading value, using current M,B,Bexp,Rexp setting
-func (r *SDRFullSensor) CalValue(value float64) uint8 {
-	M, B, Bexp, Rexp := r.GetMBExp()
-	if M == 0 {
-		panic(ErrMZero)
-	}
+// SettableThresholds returns the thresholds that can be set on a threshold sensor
+func (r *SDRFullSensor) SettableThresholds() ThresholdStatus {
+	return ThresholdStatus(r.DiscreteReadingMask>>8) & 0x3f
+}
 
-	//y=(M x V + B x pow(10,Bexp)) x pow(10,Rexp)
-	//know y, cal V
-	var neg bool = false
-	v := (value/math.Pow(10, float64(Rexp)) - float64(B)*math.Pow(10, float64(Bexp))) / float64(M)
-	if v < 0 {
-		neg = true
-	}
-	v = math.Abs(v)
-	uv := uint8(v)
-	if neg {
-		if (r.Unit & 0xc0) == 0x80 {
-			return ((128 - uv) | 0x80)
-		} else {
-			panic(ErrUnitNotSupport)
-		}
-	} else {
-		if (r.Unit & 0xc0) == 0x00 {
-			return uv
-		} else if (r.Unit & 0xc0) == 0x80 {
-			return uv & 0x7f
-		} else {
-			panic(ErrUnitNotSupport)
-		}
-	}
+// calculate the given value into the SDR reading value, using current M,B,Bexp,Rexp setting.
+// It is ConvertValue, an error being returned for a malformed SDR (M of zero, ...)
+func (r *SDRFullSensor) CalValue(value float64) (uint8, error) {
+	return r.ConvertValue(value)
 }
 
 func (r *SDRFullSensor) MarshalBinary() (data []byte, err error) {
@@ -414,20 +440,8 @@ func (r *SDRFullSensor) UnmarshalBinary(data []byte) error {
 
 	binary.Read(buffer, binary.LittleEndian, &r.sdrFullSensorFields)
 
-	idLen, err := buffer.ReadByte()
-	if err != nil {
-		return err
-	}
-
-	id := make([]byte, int(idLen))
-	n, err := buffer.Read(id)
-	if err != nil || n != int(idLen) {
-		return ErrIdStringLenNotMatch
-	}
-
-	r.deviceId = string(id)
-
-	return nil
+	r.deviceId, err = readSDRIDString(buffer)
+	return err
 }
 
 // section 43.2
@@ -514,20 +528,8 @@ func (r *SDRCompactSensor) UnmarshalBinary(data []byte) error {
 
 	binary.Read(buffer, binary.LittleEndian, &r.sdrCompactSensorFields)
 
-	idLen, err := buffer.ReadByte()
-	if err != nil {
-		return err
-	}
-
-	id := make([]byte, int(idLen))
-	n, err := buffer.Read(id)
-	if err != nil || n != int(idLen) {
-		return ErrIdStringLenNotMatch
-	}
-
-	r.deviceId = string(id)
-
-	return nil
+	r.deviceId, err = readSDRIDString(buffer)
+	return err
 }
 
 // section 43.9
@@ -548,3 +550,488 @@ type SDRMCDeviceLoc struct {
 	sdrMCDeviceLocFields
 	deviceId string
 }
+
+// marshalSDR lays out the record header, the record length, the fixed fields and the remaining body bytes
+func marshalSDR(h SDRRecordHeader, fields interface{}, body []byte) ([]byte, error) {
+	hb := new(bytes.Buffer)
+	fb := new(bytes.Buffer)
+	if err := binary.Write(hb, binary.LittleEndian, h); err != nil {
+		return nil, err
+	}
+	if fields != nil {
+		if err := binary.Write(fb, binary.LittleEndian, fields); err != nil {
+			return nil, err
+		}
+	}
+	fb.Write(body)
+
+	hb.WriteByte(byte(fb.Len()))
+	hb.Write(fb.Bytes())
+	return hb.Bytes(), nil
+}
+
+// unmarshalSDR reads the record header and the fixed fields, returning the reader positioned after them
+func unmarshalSDR(data []byte, h *SDRRecordHeader, fields interface{}) (*bytes.Reader, error) {
+	buffer := bytes.NewReader(data)
+	err := binary.Read(buffer, binary.LittleEndian, h)
+	if err != nil {
+		return nil, err
+	}
+
+	//skip the record length
+	_, err = buffer.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	if fields != nil {
+		err = binary.Read(buffer, binary.LittleEndian, fields)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buffer, nil
+}
+
+// sdrIDStringBytes encodes the Device ID String Type/Length code followed by the string
+func sdrIDStringBytes(id string) []byte {
+	return append([]byte{byte(len(id))}, id...)
+}
+
+// UnmarshalSDRRecord decodes an entire SDR record, header included, into its typed representation.
+// Records of a type this package doesn't know about are preserved as *SDRRawRecord.
+func UnmarshalSDRRecord(data []byte) (SDRRecord, error) {
+	if len(data) < 5 {
+		return nil, ErrSDRRecordTooShort
+	}
+
+	var r interface {
+		SDRRecord
+		encoding.BinaryUnmarshaler
+	}
+	switch data[3] {
+	case SDR_RECORD_TYPE_FULL_SENSOR:
+		r = &SDRFullSensor{}
+	case SDR_RECORD_TYPE_COMPACT_SENSOR:
+		r = &SDRCompactSensor{}
+	case SDR_RECORD_TYPE_EVENTONLY_SENSOR:
+		r = &SDREventOnlySensor{}
+	case SDR_RECORD_TYPE_ENTITY_ASSOC:
+		r = &SDREntityAssociation{}
+	case SDR_RECORD_TYPE_DEVICE_ENTITY_ASSOC:
+		r = &SDRDeviceEntityAssociation{}
+	case SDR_RECORD_TYPE_GENERIC_DEVICE_LOCATOR:
+		r = &SDRGenericDeviceLocator{}
+	case SDR_RECORD_TYPE_FRU_DEVICE_LOCATOR:
+		r = &SDRFruDeviceLocator{}
+	case SDR_RECORD_TYPE_MC_DEVICE_LOCATOR:
+		r = &SDRMcDeviceLocator{}
+	case SDR_RECORD_TYPE_MC_CONFIRMATION:
+		r = &SDRMcConfirmation{}
+	case SDR_RECORD_TYPE_BMC_MSG_CHANNEL_INFO:
+		r = &SDRBMCMsgChannelInfo{}
+	case SDR_RECORD_TYPE_OEM:
+		r = &SDROEMRecord{}
+	default:
+		r = &SDRRawRecord{}
+	}
+
+	if err := r.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// section 43.3
+type sdrEventOnlySensorFields struct { //size 11
+	SensorOwnerId    uint8
+	SensorOwnerLUN   uint8
+	SensorNumber     uint8
+	EntityId         uint8
+	EntityIns        uint8
+	SensorType       SDRSensorType
+	ReadingType      SDRSensorReadingType
+	SensorRecSharing uint16
+	Reserved         uint8
+	OEM              uint8
+}
+
+type SDREventOnlySensor struct {
+	SDRRecordHeader
+	sdrEventOnlySensorFields
+	deviceId string
+}
+
+func NewSDREventOnlySensor(id uint16, name string) (*SDREventOnlySensor, error) {
+	if len(name) > 16 {
+		return nil, ErrDeviceIdMustLess16
+	}
+	r := &SDREventOnlySensor{}
+	r.Recordid = id
+	r.Rtype = SDR_RECORD_TYPE_EVENTONLY_SENSOR
+	r.SDRVersion = 0x51
+	r.deviceId = name
+	return r, nil
+}
+
+func (r *SDREventOnlySensor) DeviceId() string {
+	return r.deviceId
+}
+
+func (r *SDREventOnlySensor) RecordId() uint16 {
+	return r.Recordid
+}
+
+func (r *SDREventOnlySensor) RecordType() SDRRecordType {
+	return r.Rtype
+}
+
+func (r *SDREventOnlySensor) MarshalBinary() (data []byte, err error) {
+	return marshalSDR(r.SDRRecordHeader, r.sdrEventOnlySensorFields, sdrIDStringBytes(r.deviceId))
+}
+
+func (r *SDREventOnlySensor) UnmarshalBinary(data []byte) error {
+	buffer, err := unmarshalSDR(data, &r.SDRRecordHeader, &r.sdrEventOnlySensorFields)
+	if err != nil {
+		return err
+	}
+	r.deviceId, err = readSDRIDString(buffer)
+	return err
+}
+
+// SDREntity identifies an entity by its Entity ID and instance
+type SDREntity struct {
+	EntityId  uint8
+	EntityIns uint8
+}
+
+// section 43.4
+type sdrEntityAssociationFields struct { //size 11
+	ContainerEntityId  uint8
+	ContainerEntityIns uint8
+	Flags              uint8
+	// contained entities, or (first, last) range pairs when IsRange
+	ContainedEntities [4]SDREntity
+}
+
+type SDREntityAssociation struct {
+	SDRRecordHeader
+	sdrEntityAssociationFields
+}
+
+func NewSDREntityAssociation(id uint16) *SDREntityAssociation {
+	r := &SDREntityAssociation{}
+	r.Recordid = id
+	r.Rtype = SDR_RECORD_TYPE_ENTITY_ASSOC
+	r.SDRVersion = 0x51
+	return r
+}
+
+func (r *SDREntityAssociation) DeviceId() string {
+	return ""
+}
+
+func (r *SDREntityAssociation) RecordId() uint16 {
+	return r.Recordid
+}
+
+func (r *SDREntityAssociation) RecordType() SDRRecordType {
+	return r.Rtype
+}
+
+// IsRange reports whether ContainedEntities holds ranges rather than a list
+func (r *SDREntityAssociation) IsRange() bool {
+	return r.Flags&0x80 != 0
+}
+
+func (r *SDREntityAssociation) MarshalBinary() (data []byte, err error) {
+	return marshalSDR(r.SDRRecordHeader, r.sdrEntityAssociationFields, nil)
+}
+
+func (r *SDREntityAssociation) UnmarshalBinary(data []byte) error {
+	_, err := unmarshalSDR(data, &r.SDRRecordHeader, &r.sdrEntityAssociationFields)
+	return err
+}
+
+// SDRDeviceEntity identifies an entity relative to the device that owns it
+type SDRDeviceEntity struct {
+	DeviceAddr    uint8
+	DeviceChannel uint8
+	EntityId      uint8
+	EntityIns     uint8
+}
+
+// section 43.5
+type sdrDeviceEntityAssociationFields struct { //size 21
+	ContainerEntityId      uint8
+	ContainerEntityIns     uint8
+	ContainerDeviceAddr    uint8
+	ContainerDeviceChannel uint8
+	Flags                  uint8
+	// contained entities, or (first, last) range pairs when IsRange
+	ContainedEntities [4]SDRDeviceEntity
+}
+
+type SDRDeviceEntityAssociation struct {
+	SDRRecordHeader
+	sdrDeviceEntityAssociationFields
+}
+
+func NewSDRDeviceEntityAssociation(id uint16) *SDRDeviceEntityAssociation {
+	r := &SDRDeviceEntityAssociation{}
+	r.Recordid = id
+	r.Rtype = SDR_RECORD_TYPE_DEVICE_ENTITY_ASSOC
+	r.SDRVersion = 0x51
+	return r
+}
+
+func (r *SDRDeviceEntityAssociation) DeviceId() string {
+	return ""
+}
+
+func (r *SDRDeviceEntityAssociation) RecordId() uint16 {
+	return r.Recordid
+}
+
+func (r *SDRDeviceEntityAssociation) RecordType() SDRRecordType {
+	return r.Rtype
+}
+
+// IsRange reports whether ContainedEntities holds ranges rather than a list
+func (r *SDRDeviceEntityAssociation) IsRange() bool {
+	return r.Flags&0x80 != 0
+}
+
+func (r *SDRDeviceEntityAssociation) MarshalBinary() (data []byte, err error) {
+	return marshalSDR(r.SDRRecordHeader, r.sdrDeviceEntityAssociationFields, nil)
+}
+
+func (r *SDRDeviceEntityAssociation) UnmarshalBinary(data []byte) error {
+	_, err := unmarshalSDR(data, &r.SDRRecordHeader, &r.sdrDeviceEntityAssociationFields)
+	return err
+}
+
+// section 43.7
+type sdrGenericDeviceLocatorFields struct { //size 10
+	DeviceAccAddr    uint8
+	DeviceSlaveAddr  uint8
+	ChannAccLUNBusID uint8
+	AddressSpan      uint8
+	Reserved         uint8
+	DeviceType       uint8
+	DevTypeModif     uint8
+	EntityId         uint8
+	EntityIns        uint8
+	OEM              uint8
+}
+
+type SDRGenericDeviceLocator struct {
+	SDRRecordHeader
+	sdrGenericDeviceLocatorFields
+	deviceId string
+}
+
+func NewSDRGenericDeviceLocator(id uint16, name string) (*SDRGenericDeviceLocator, error) {
+	if len(name) > 16 {
+		return nil, ErrDeviceIdMustLess16
+	}
+	r := &SDRGenericDeviceLocator{}
+	r.Recordid = id
+	r.Rtype = SDR_RECORD_TYPE_GENERIC_DEVICE_LOCATOR
+	r.SDRVersion = 0x51
+	r.deviceId = name
+	return r, nil
+}
+
+func (r *SDRGenericDeviceLocator) DeviceId() string {
+	return r.deviceId
+}
+
+func (r *SDRGenericDeviceLocator) RecordId() uint16 {
+	return r.Recordid
+}
+
+func (r *SDRGenericDeviceLocator) RecordType() SDRRecordType {
+	return r.Rtype
+}
+
+func (r *SDRGenericDeviceLocator) MarshalBinary() (data []byte, err error) {
+	return marshalSDR(r.SDRRecordHeader, r.sdrGenericDeviceLocatorFields, sdrIDStringBytes(r.deviceId))
+}
+
+func (r *SDRGenericDeviceLocator) UnmarshalBinary(data []byte) error {
+	buffer, err := unmarshalSDR(data, &r.SDRRecordHeader, &r.sdrGenericDeviceLocatorFields)
+	if err != nil {
+		return err
+	}
+	r.deviceId, err = readSDRIDString(buffer)
+	return err
+}
+
+// section 43.10
+type sdrMcConfirmationFields struct { //size 27
+	DeviceSlaveAddr   uint8
+	DeviceID          uint8
+	ChannDeviceRev    uint8
+	FirmwareRevision1 uint8
+	FirmwareRevision2 uint8
+	IPMIVersion       uint8
+	ManufacturerID    [3]uint8
+	ProductID         uint16
+	DeviceGUID        [16]uint8
+}
+
+type SDRMcConfirmation struct {
+	SDRRecordHeader
+	sdrMcConfirmationFields
+}
+
+func NewSDRMcConfirmation(id uint16) *SDRMcConfirmation {
+	r := &SDRMcConfirmation{}
+	r.Recordid = id
+	r.Rtype = SDR_RECORD_TYPE_MC_CONFIRMATION
+	r.SDRVersion = 0x51
+	return r
+}
+
+func (r *SDRMcConfirmation) DeviceId() string {
+	return ""
+}
+
+func (r *SDRMcConfirmation) RecordId() uint16 {
+	return r.Recordid
+}
+
+func (r *SDRMcConfirmation) RecordType() SDRRecordType {
+	return r.Rtype
+}
+
+func (r *SDRMcConfirmation) MarshalBinary() (data []byte, err error) {
+	return marshalSDR(r.SDRRecordHeader, r.sdrMcConfirmationFields, nil)
+}
+
+func (r *SDRMcConfirmation) UnmarshalBinary(data []byte) error {
+	_, err := unmarshalSDR(data, &r.SDRRecordHeader, &r.sdrMcConfirmationFields)
+	return err
+}
+
+// section 43.11
+type sdrBMCMsgChannelInfoFields struct { //size 11
+	ChannelInfo        [8]uint8
+	MsgInterruptType   uint8
+	EventInterruptType uint8
+	Reserved           uint8
+}
+
+type SDRBMCMsgChannelInfo struct {
+	SDRRecordHeader
+	sdrBMCMsgChannelInfoFields
+}
+
+func NewSDRBMCMsgChannelInfo(id uint16) *SDRBMCMsgChannelInfo {
+	r := &SDRBMCMsgChannelInfo{}
+	r.Recordid = id
+	r.Rtype = SDR_RECORD_TYPE_BMC_MSG_CHANNEL_INFO
+	r.SDRVersion = 0x51
+	return r
+}
+
+func (r *SDRBMCMsgChannelInfo) DeviceId() string {
+	return ""
+}
+
+func (r *SDRBMCMsgChannelInfo) RecordId() uint16 {
+	return r.Recordid
+}
+
+func (r *SDRBMCMsgChannelInfo) RecordType() SDRRecordType {
+	return r.Rtype
+}
+
+func (r *SDRBMCMsgChannelInfo) MarshalBinary() (data []byte, err error) {
+	return marshalSDR(r.SDRRecordHeader, r.sdrBMCMsgChannelInfoFields, nil)
+}
+
+func (r *SDRBMCMsgChannelInfo) UnmarshalBinary(data []byte) error {
+	_, err := unmarshalSDR(data, &r.SDRRecordHeader, &r.sdrBMCMsgChannelInfoFields)
+	return err
+}
+
+// section 43.12
+type SDROEMRecord struct {
+	SDRRecordHeader
+	ManufacturerID uint32 // 3 bytes on the wire
+	Data           []byte
+}
+
+func NewSDROEMRecord(id uint16, manufacturerID uint32, data []byte) *SDROEMRecord {
+	r := &SDROEMRecord{}
+	r.Recordid = id
+	r.Rtype = SDR_RECORD_TYPE_OEM
+	r.SDRVersion = 0x51
+	r.ManufacturerID = manufacturerID
+	r.Data = data
+	return r
+}
+
+func (r *SDROEMRecord) DeviceId() string {
+	return ""
+}
+
+func (r *SDROEMRecord) RecordId() uint16 {
+	return r.Recordid
+}
+
+func (r *SDROEMRecord) RecordType() SDRRecordType {
+	return r.Rtype
+}
+
+func (r *SDROEMRecord) MarshalBinary() (data []byte, err error) {
+	body := []byte{byte(r.ManufacturerID), byte(r.ManufacturerID >> 8), byte(r.ManufacturerID >> 16)}
+	return marshalSDR(r.SDRRecordHeader, nil, append(body, r.Data...))
+}
+
+func (r *SDROEMRecord) UnmarshalBinary(data []byte) error {
+	_, err := unmarshalSDR(data, &r.SDRRecordHeader, nil)
+	if err != nil {
+		return err
+	}
+	body := data[5:]
+	if len(body) < 3 {
+		return ErrSDRRecordTooShort
+	}
+	r.ManufacturerID = uint32(body[0]) | uint32(body[1])<<8 | uint32(body[2])<<16
+	r.Data = append([]byte{}, body[3:]...)
+	return nil
+}
+
+// SDRRawRecord preserves a record of a type this package doesn't decode
+type SDRRawRecord struct {
+	SDRRecordHeader
+	Data []byte // record body, following the record length
+}
+
+func (r *SDRRawRecord) DeviceId() string {
+	return ""
+}
+
+func (r *SDRRawRecord) RecordId() uint16 {
+	return r.Recordid
+}
+
+func (r *SDRRawRecord) RecordType() SDRRecordType {
+	return r.Rtype
+}
+
+func (r *SDRRawRecord) MarshalBinary() (data []byte, err error) {
+	return marshalSDR(r.SDRRecordHeader, nil, r.Data)
+}
+
+func (r *SDRRawRecord) UnmarshalBinary(data []byte) error {
+	_, err := unmarshalSDR(data, &r.SDRRecordHeader, nil)
+	if err != nil {
+		return err
+	}
+	r.Data = append([]byte{}, data[5:]...)
+	return nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package ipmi
 
 import (
+	"encoding"
 	"fmt"
 	"testing"
 
@@ -34,7 +35,7 @@ func TestSDRRecType_McDeviceLocator(t *testing.T) {
 		ChannelNumber:   0x00,
 		PSNGI:           0x00,
 		DeviceCap:       0xff,
-		reserved:        [3]byte{0, 0, 0},
+		Reserved:        [3]byte{0, 0, 0},
 		EntityId:        0x00,
 		EntityIns:       0x01,
 		OEM:             0x00,
@@ -167,7 +168,8 @@ func TestSimulatorSDR_L_CalValue1(t *testing.T) {
 	r.BaseUnit = 0x04      //Voltage
 	r.Linearization = 0x00 //no linearization
 	r.SetMBExp(2, 0, 0, -2)
-	v := r.CalValue(3.36)
+	v, err := r.CalValue(3.36)
+	assert.NoError(t, err)
 	assert.Equal(t, uint8(0xa8), v)
 }
 
@@ -177,7 +179,8 @@ func TestSimulatorSDR_L_CalValue2(t *testing.T) {
 	r.BaseUnit = 0x01      //Temprature
 	r.Linearization = 0x00 //no linearization
 	r.SetMBExp(1, 0, 0, 0)
-	v := r.CalValue(23.0)
+	v, err := r.CalValue(23.0)
+	assert.NoError(t, err)
 	assert.Equal(t, uint8(0x17), v)
 }
 
@@ -187,7 +190,8 @@ func TestSimulatorSDR_L_CalValue3(t *testing.T) {
 	r.BaseUnit = 0x01      //Temprature
 	r.Linearization = 0x00 //no linearization
 	r.SetMBExp(1, 0, 0, 0)
-	v := r.CalValue(-49.0)
+	v, err := r.CalValue(-49.0)
+	assert.NoError(t, err)
 	assert.Equal(t, uint8(0xcf), v)
 }
 
@@ -197,8 +201,14 @@ func TestSimulatorSDR_L_CalValue4(t *testing.T) {
 	r.BaseUnit = 0x12      //RPM
 	r.Linearization = 0x00 //no linearization
 	r.SetMBExp(63, 0, 0, 0)
-	v := r.CalValue(2583.0)
+	v, err := r.CalValue(2583.0)
+	assert.NoError(t, err)
 	assert.Equal(t, uint8(0x29), v)
+
+	// a malformed SDR is an error, not a panic
+	r.SetMBExp(0, 0, 0, 0)
+	_, err = r.CalValue(2583.0)
+	assert.Equal(t, ErrMZero, err)
 }
 
 func TestSimulatorSDR_L_FullSensorMarshaling(t *testing.T) {
@@ -227,3 +237,105 @@ func TestSimulatorSDR_L_FullSensorMarshaling(t *testing.T) {
 	assert.Equal(t, r2.RecordType(), r1.RecordType())
 
 }
+
+func TestSDRRecType_FruDeviceLocator(t *testing.T) {
+	r1, _ := NewSDRFruDeviceLocator(7, "PSU2 FRU")
+	r1.DeviceAccAddr = 0x20
+	r1.FRUDeviceID = 0x02
+	r1.LogPhyAccLUNBusID = 0x80 | 0x08 | 0x03
+	r1.ChannNum = 0x70
+	r1.FruEntityId = 0x0a
+	r1.FruEntityInst = 0x02
+
+	bin, err := r1.MarshalBinary()
+	assert.Nil(t, err)
+	// real BMCs set the 8-bit ASCII type in the ID string type/length code
+	bin[len(bin)-len(r1.DeviceId())-1] |= 0xc0
+
+	r2, _ := NewSDRFruDeviceLocator(0, "")
+	err = r2.UnmarshalBinary(bin)
+	assert.Nil(t, err)
+	assert.Equal(t, r1.sdrFruDeviceLocatorFields, r2.sdrFruDeviceLocatorFields)
+	assert.Equal(t, "PSU2 FRU", r2.DeviceId())
+	assert.Equal(t, uint16(7), r2.RecordId())
+	assert.True(t, r2.IsLogical())
+	assert.Equal(t, uint8(1), r2.AccessLUN())
+	assert.Equal(t, uint8(3), r2.PrivateBusID())
+	assert.Equal(t, uint8(7), r2.Channel())
+}
+
+func TestSDRRecType_UnmarshalSDRRecord(t *testing.T) {
+	eventOnly, _ := NewSDREventOnlySensor(1, "PS Redundancy")
+	eventOnly.SensorOwnerId = 0x20
+	eventOnly.SensorNumber = 0x50
+	eventOnly.EntityId = 0x0a
+	eventOnly.SensorType = SDRSensorType(0x08)
+	eventOnly.ReadingType = SDRSensorReadingType(0x0b)
+
+	assoc := NewSDREntityAssociation(2)
+	assoc.ContainerEntityId = 0x17
+	assoc.ContainerEntityIns = 0x01
+	assoc.Flags = 0x80
+	assoc.ContainedEntities[0] = SDREntity{0x0a, 0x01}
+	assoc.ContainedEntities[1] = SDREntity{0x0a, 0x02}
+
+	devAssoc := NewSDRDeviceEntityAssociation(3)
+	devAssoc.ContainerEntityId = 0x07
+	devAssoc.ContainerDeviceAddr = 0x20
+	devAssoc.ContainedEntities[3] = SDRDeviceEntity{0xb0, 0x00, 0x03, 0x60}
+
+	generic, _ := NewSDRGenericDeviceLocator(4, "LM75")
+	generic.DeviceAccAddr = 0x20
+	generic.DeviceSlaveAddr = 0x90
+	generic.DeviceType = 0x02
+
+	fru, _ := NewSDRFruDeviceLocator(5, "Riser FRU")
+	fru.FRUDeviceID = 0x03
+	fru.LogPhyAccLUNBusID = 0x80
+
+	mc, _ := NewSDRMcDeviceLocator(6, "BMC")
+	mc.DeviceSlaveAddr = 0x20
+	mc.DeviceCap = 0xbf
+
+	confirm := NewSDRMcConfirmation(7)
+	confirm.DeviceSlaveAddr = 0x20
+	confirm.ManufacturerID = [3]uint8{0x57, 0x01, 0x00}
+	confirm.ProductID = 0x0a1b
+	confirm.DeviceGUID[0] = 0xaa
+
+	channel := NewSDRBMCMsgChannelInfo(8)
+	channel.ChannelInfo[1] = 0x02
+
+	oem := NewSDROEMRecord(9, 10876, []byte{0x01, 0x02, 0x03})
+
+	raw := &SDRRawRecord{SDRRecordHeader{10, 0x51, SDRRecordType(0x20)}, []byte{0xde, 0xad}}
+
+	tests := []SDRRecord{eventOnly, assoc, devAssoc, generic, fru, mc, confirm, channel, oem, raw}
+	for _, test := range tests {
+		data, err := test.(encoding.BinaryMarshaler).MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, len(data)-5, int(data[4]))
+
+		rec, err := UnmarshalSDRRecord(data)
+		assert.NoError(t, err)
+		assert.Equal(t, test, rec)
+		assert.Equal(t, test.RecordId(), rec.RecordId())
+		assert.Equal(t, test.RecordType(), rec.RecordType())
+		assert.Equal(t, test.DeviceId(), rec.DeviceId())
+	}
+
+	_, err := UnmarshalSDRRecord([]byte{0x01, 0x00, 0x51})
+	assert.Equal(t, ErrSDRRecordTooShort, err)
+}
+
+func TestSDRRecType_FullSensorIDStringType(t *testing.T) {
+	r1, _ := NewSDRFullSensor(3, "Inlet Temp")
+	bin, err := r1.MarshalBinary()
+	assert.Nil(t, err)
+	// 8-bit ASCII + Latin 1 type/length code, as sent by real BMCs
+	bin[len(bin)-len(r1.DeviceId())-1] |= 0xc0
+
+	r2, err := UnmarshalSDRRecord(bin)
+	assert.Nil(t, err)
+	assert.Equal(t, "Inlet Temp", r2.DeviceId())
+}
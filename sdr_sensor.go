@@ -0,0 +1,251 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// SdrSensorInfo is a sensor described by a full or compact SDR, along with its reading
+type SdrSensorInfo struct {
+	SensorType string
+	BaseUnit   string
+	Value      float64
+	DeviceId   string
+	Reading    SensorReading
+	avail      bool
+
+	RecordId       uint16
+	RecordType     SDRRecordType
+	SensorNumber   uint8
+	OwnerId        uint8
+	OwnerLUN       uint8
+	EntityId       uint8
+	EntityInstance uint8
+	SensorTypeCode SDRSensorType
+	ReadingType    SDRSensorReadingType
+	// Analog threshold sensors have their readings converted into Value
+	Analog       bool
+	Percentage   bool
+	ModifierOp   uint8 // one of the SENSOR_MODIFIER_UNIT_* values
+	ModifierUnit string
+	RateUnit     string
+
+	// full sensors only
+	NominalReading    float64
+	NormalMax         float64
+	NormalMin         float64
+	HasNominalReading bool
+	HasNormalMax      bool
+	HasNormalMin      bool
+	SensorMax         float64
+	SensorMin         float64
+	Thresholds        SensorThresholds
+}
+
+var sdrRateUnit = []string{"", "per us", "per ms", "per s", "per minute", "per hour", "per day"}
+
+func sensorTypeName(sensorType SDRSensorType) string {
+	if int(sensorType) < len(sdrRecordValueSensorType) {
+		return sdrRecordValueSensorType[sensorType]
+	}
+	if sensorType >= 0xc0 {
+		return "OEM reserved"
+	}
+	return "reserved"
+}
+
+func baseUnitName(unit uint8) string {
+	if int(unit) < len(sdrRecordValueBasicUnit) {
+		return sdrRecordValueBasicUnit[unit]
+	}
+	return "unknown"
+}
+
+// NewSdrSensorInfos describes the sensors of a full or compact SDR, a compact SDR may be shared by several sensors
+func NewSdrSensorInfos(record SDRRecord) []SdrSensorInfo {
+	switch r := record.(type) {
+	case *SDRFullSensor:
+		return []SdrSensorInfo{newFullSensorInfo(r)}
+	case *SDRCompactSensor:
+		return newCompactSensorInfos(r)
+	}
+	return nil
+}
+
+// setUnits decodes the sensor units 1, base unit and modifier unit bytes
+func (s *SdrSensorInfo) setUnits(unit uint8, baseUnit uint8, modifierUnit uint8) {
+	s.BaseUnit = baseUnitName(baseUnit)
+	s.Percentage = unit&0x01 != 0
+	s.ModifierOp = (unit >> 1) & 0x03
+	if s.ModifierOp != SENSOR_MODIFIER_UNIT_NONE {
+		s.ModifierUnit = baseUnitName(modifierUnit)
+	}
+	if rate := int(unit>>3) & 0x07; rate < len(sdrRateUnit) {
+		s.RateUnit = sdrRateUnit[rate]
+	}
+}
+
+func newFullSensorInfo(r *SDRFullSensor) SdrSensorInfo {
+	s := SdrSensorInfo{
+		SensorType:     sensorTypeName(r.SensorType),
+		DeviceId:       r.DeviceId(),
+		RecordId:       r.RecordId(),
+		RecordType:     r.RecordType(),
+		SensorNumber:   r.SensorNumber,
+		OwnerId:        r.SensorOwnerId,
+		OwnerLUN:       r.SensorOwnerLUN & 0x03,
+		EntityId:       r.EntityId,
+		EntityInstance: r.EntityIns,
+		SensorTypeCode: r.SensorType,
+		ReadingType:    r.ReadingType,
+	}
+	s.setUnits(r.Unit, r.BaseUnit, r.ModifierUnit)
+
+	s.Analog = r.ReadingType == SENSOR_READTYPE_THREADHOLD && r.AnalogDataFormat() != SDR_ANALOG_FORMAT_NONE
+	if !s.Analog {
+		return s
+	}
+
+	convert := func(raw uint8, value *float64) bool {
+		v, err := r.ConvertReading(raw)
+		if err != nil {
+			return false
+		}
+		*value = v
+		return true
+	}
+	s.HasNominalReading = r.AnalogFlag&0x01 != 0 && convert(r.NominalReading, &s.NominalReading)
+	s.HasNormalMax = r.AnalogFlag&0x02 != 0 && convert(r.NormalMax, &s.NormalMax)
+	s.HasNormalMin = r.AnalogFlag&0x04 != 0 && convert(r.NormalMin, &s.NormalMin)
+	convert(r.SensorMax, &s.SensorMax)
+	convert(r.SensorMin, &s.SensorMin)
+
+	if r.ThresholdAccess() != SENSOR_ACCESS_NONE {
+		raw := []uint8{r.L_NC, r.L_C, r.L_NR, r.U_NC, r.U_C, r.U_NR}
+		for i, value := range s.Thresholds.values() {
+			bit := ThresholdStatus(1 << uint(i))
+			if r.ReadableThresholds()&bit != 0 && convert(raw[i], value) {
+				s.Thresholds.Mask |= bit
+			}
+		}
+	}
+	return s
+}
+
+func newCompactSensorInfos(r *SDRCompactSensor) []SdrSensorInfo {
+	// section 43.2, sensor record sharing
+	count := int(r.SensorRecSharing & 0x0f)
+	alpha := (r.SensorRecSharing>>4)&0x03 == 0x01
+	offset := int(r.SensorRecSharing>>8) & 0x7f
+	entityShared := r.SensorRecSharing&0x8000 != 0
+	if count < 1 {
+		count = 1
+	}
+
+	infos := make([]SdrSensorInfo, count)
+	for i := range infos {
+		s := SdrSensorInfo{
+			SensorType:     sensorTypeName(r.SensorType),
+			DeviceId:       r.DeviceId(),
+			RecordId:       r.RecordId(),
+			RecordType:     r.RecordType(),
+			SensorNumber:   r.SensorNumber + uint8(i),
+			OwnerId:        r.SensorOwnerId,
+			OwnerLUN:       r.SensorOwnerLUN & 0x03,
+			EntityId:       r.EntityId,
+			EntityInstance: r.EntityIns,
+			SensorTypeCode: r.SensorType,
+			ReadingType:    r.ReadingType,
+		}
+		s.setUnits(r.Unit, r.BaseUnit, r.ModifierUnit)
+		if count > 1 {
+			s.DeviceId += idStringInstanceModifier(alpha, offset+i)
+			if entityShared {
+				s.EntityInstance += uint8(i)
+			}
+		}
+		infos[i] = s
+	}
+	return infos
+}
+
+// idStringInstanceModifier returns the suffix of a shared ID string: a number, or A to Z then AA, AB...
+func idStringInstanceModifier(alpha bool, n int) string {
+	if !alpha {
+		return strconv.Itoa(n)
+	}
+	if n < 26 {
+		return string(rune('A' + n))
+	}
+	return string([]rune{rune('A' + n/26 - 1), rune('A' + n%26)})
+}
+
+// Available reports whether the sensor had a reading
+func (s *SdrSensorInfo) Available() bool {
+	return s.avail
+}
+
+// Units returns the units of the sensor as shown by ipmitool, e.g. "degrees C" or "discrete"
+func (s *SdrSensorInfo) Units() string {
+	if !s.Analog {
+		return "discrete"
+	}
+	units := s.BaseUnit
+	switch s.ModifierOp {
+	case SENSOR_MODIFIER_UNIT_DIVIDE:
+		units += "/" + s.ModifierUnit
+	case SENSOR_MODIFIER_UNIT_MULTIPLY:
+		units += " * " + s.ModifierUnit
+	}
+	if s.RateUnit != "" {
+		units += " " + s.RateUnit
+	}
+	if s.Percentage {
+		units = "% " + units
+	}
+	return units
+}
+
+// String returns the sensor in the columns of ipmitool sensor list:
+// name, value, units, status, lower non-recoverable, lower critical, lower non-critical,
+// upper non-critical, upper critical and upper non-recoverable
+func (s *SdrSensorInfo) String() string {
+	value, status := "na", "na"
+	if !s.Reading.Unavailable {
+		if s.Analog {
+			if s.avail {
+				value, status = fmt.Sprintf("%.3f", s.Value), s.Reading.Threshold.String()
+			}
+		} else {
+			mask := s.Reading.stateMask()
+			value, status = fmt.Sprintf("0x%x", s.Reading.Raw), fmt.Sprintf("0x%02x%02x", uint8(mask), uint8(mask>>8)|0x80)
+		}
+	}
+
+	columns := []interface{}{s.DeviceId, value, s.Units(), status}
+	thresholds := s.Thresholds.values()
+	for _, i := range []uint{2, 1, 0, 3, 4, 5} {
+		if s.Thresholds.Mask&(1<<i) != 0 {
+			columns = append(columns, fmt.Sprintf("%.3f", *thresholds[i]))
+		} else {
+			columns = append(columns, "na")
+		}
+	}
+	return fmt.Sprintf("%-16s | %-10s | %-10s | %-6s | %-10s | %-10s | %-10s | %-10s | %-10s | %-10s", columns...)
+}
@@ -0,0 +1,161 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSdrSensorInfosFull(t *testing.T) {
+	r, _ := NewSDRFullSensor(7, "PS1 Input Power")
+	r.SensorOwnerId = 0x20
+	r.SensorNumber = 0x61
+	r.EntityId = 0x0a
+	r.EntityIns = 0x01
+	r.SensorType = 0x0b
+	r.ReadingType = SENSOR_READTYPE_THREADHOLD
+	r.SensorCap = SENSOR_ACCESS_READABLE << 2
+	r.Unit = 5<<3 | SENSOR_MODIFIER_UNIT_NONE<<1
+	r.BaseUnit = 0x06
+	r.SetMBExp(10, 0, 0, 0)
+	r.AnalogFlag = 0x03
+	r.NominalReading, r.NormalMax, r.SensorMax = 30, 50, 0xff
+	r.DiscreteReadingMask = 0x0018
+	r.U_NC, r.U_C = 60, 70
+
+	infos := NewSdrSensorInfos(r)
+	assert.Equal(t, 1, len(infos))
+	s := infos[0]
+	assert.Equal(t, uint16(7), s.RecordId)
+	assert.Equal(t, SDRRecordType(SDR_RECORD_TYPE_FULL_SENSOR), s.RecordType)
+	assert.Equal(t, uint8(0x61), s.SensorNumber)
+	assert.Equal(t, uint8(0x20), s.OwnerId)
+	assert.Equal(t, uint8(0x0a), s.EntityId)
+	assert.Equal(t, uint8(0x01), s.EntityInstance)
+	assert.Equal(t, "Other", s.SensorType)
+	assert.True(t, s.Analog)
+	assert.Equal(t, "Watts per hour", s.Units())
+	assert.True(t, s.HasNominalReading)
+	assert.Equal(t, float64(300), s.NominalReading)
+	assert.True(t, s.HasNormalMax)
+	assert.Equal(t, float64(500), s.NormalMax)
+	assert.False(t, s.HasNormalMin)
+	assert.Equal(t, float64(2550), s.SensorMax)
+	assert.Equal(t, SensorThresholds{
+		Mask:             ThresholdUpperNonCritical | ThresholdUpperCritical,
+		UpperNonCritical: 600,
+		UpperCritical:    700,
+	}, s.Thresholds)
+
+	r.Unit = SENSOR_MODIFIER_UNIT_DIVIDE<<1 | 0x01
+	r.ModifierUnit = 0x16
+	assert.Equal(t, "% Watts/second", NewSdrSensorInfos(r)[0].Units())
+	r.Unit = SENSOR_MODIFIER_UNIT_MULTIPLY << 1
+	assert.Equal(t, "Watts * second", NewSdrSensorInfos(r)[0].Units())
+
+	r.SensorType = 0xc2
+	assert.Equal(t, "OEM reserved", NewSdrSensorInfos(r)[0].SensorType)
+
+	assert.Nil(t, NewSdrSensorInfos(&SDRMcDeviceLocator{}))
+}
+
+func TestNewSdrSensorInfosCompactShared(t *testing.T) {
+	r, _ := NewSDRCompactSensor(9, "DIMM")
+	r.SensorNumber = 0x40
+	r.EntityIns = 0x01
+	r.SensorType = 0x0c
+	r.ReadingType = SENSOR_READTYPE_SENSORSPECIF
+	// 3 sensors, numeric modifier from 1, entity instance incremented
+	r.SensorRecSharing = 0x8103
+
+	infos := NewSdrSensorInfos(r)
+	assert.Equal(t, 3, len(infos))
+	for i, s := range infos {
+		assert.Equal(t, []string{"DIMM1", "DIMM2", "DIMM3"}[i], s.DeviceId)
+		assert.Equal(t, uint8(0x40+i), s.SensorNumber)
+		assert.Equal(t, uint8(0x01+i), s.EntityInstance)
+		assert.Equal(t, "Memory", s.SensorType)
+		assert.Equal(t, "discrete", s.Units())
+	}
+
+	// alpha modifier from Z, same entity instance
+	r.SensorRecSharing = 0x1912
+	infos = NewSdrSensorInfos(r)
+	assert.Equal(t, 2, len(infos))
+	assert.Equal(t, "DIMMZ", infos[0].DeviceId)
+	assert.Equal(t, "DIMMAA", infos[1].DeviceId)
+	assert.Equal(t, uint8(0x01), infos[1].EntityInstance)
+
+	// not shared
+	r.SensorRecSharing = 0x0001
+	infos = NewSdrSensorInfos(r)
+	assert.Equal(t, 1, len(infos))
+	assert.Equal(t, "DIMM", infos[0].DeviceId)
+}
+
+func TestSdrSensorInfoString(t *testing.T) {
+	s := NewSimulator(net.UDPAddr{})
+	err := s.Run()
+	assert.NoError(t, err)
+
+	client, err := NewClient(s.NewConnection())
+	assert.NoError(t, err)
+
+	err = client.Open()
+	assert.NoError(t, err)
+
+	getSensorReading := s.getSensorReading
+	s.SetHandler(NetworkFunctionSensorEvent, CommandGetSensorReading, func(m *Message) Response {
+		request := &GetSensorReadingRequest{}
+		if err := m.Request(request); err != nil {
+			return err
+		}
+		switch request.SensorNumber {
+		case 0x30:
+			return &GetSensorReadingResponse{SensorReading: 0x01, ReadingAvail: 0xc0, ForThresDiscreStat: 0x01}
+		case 0x31:
+			return &GetSensorReadingResponse{ReadingAvail: 0xe0}
+		}
+		return getSensorReading(m)
+	})
+
+	records, err := client.ReadSDRRepository()
+	assert.NoError(t, err)
+	psu, _ := NewSDRCompactSensor(3, "PS")
+	psu.SensorNumber = 0x30
+	psu.SensorType = 0x08
+	psu.ReadingType = SENSOR_READTYPE_SENSORSPECIF
+	psu.SensorRecSharing = 0x0102
+	records = append(records, &SDRRepositoryRecord{SDRRecord: psu})
+
+	list, err := client.ReadSensors(records)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(list))
+	assert.Equal(t, "Ambient Temp     | 2583.000   | degrees C  | ok     | 63.000     | 315.000    | 630.000    | 3024.000   | 3528.000   | 4032.000  ", list[0].String())
+	assert.Equal(t, "CPU1 DTS         | -49.000    | degrees C  | ok     | na         | na         | na         | na         | na         | na        ", list[1].String())
+	assert.Equal(t, "PS1              | 0x1        | discrete   | 0x0180 | na         | na         | na         | na         | na         | na        ", list[2].String())
+	assert.Equal(t, "PS2              | na         | discrete   | na     | na         | na         | na         | na         | na         | na        ", list[3].String())
+	assert.True(t, list[0].Available())
+	assert.False(t, list[3].Available())
+
+	err = client.Close()
+	assert.NoError(t, err)
+	s.Stop()
+}
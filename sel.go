@@ -0,0 +1,150 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// SEL record types per section 32
+const (
+	SELRecordSystemEvent = 0x02
+	SELRecordOEMFirst    = 0xc0 // 0xc0 to 0xdf are timestamped OEM records
+	SELRecordOEMNoTime   = 0xe0 // 0xe0 to 0xff are OEM records without a timestamp
+)
+
+// SELEvMRev is the event message format revision of IPMI v1.5 and v2.0
+const SELEvMRev = 0x04
+
+// selRecordSize is the size of a SEL record
+const selRecordSize = 16
+
+// eventMessageSize is the size of the event message data of section 29.3, from EvMRev to the event data
+const eventMessageSize = 7
+
+// SEL timestamp values per section 37.1
+const (
+	selTimestampUnspecified = 0xffffffff
+	selTimestampInitMax     = 0x20000000 // timestamps up to this are relative to the BMC initialization
+)
+
+// SELEvent is a platform event, as logged in the SEL by a system event record per section 32.1
+// and as carried by event messages per section 29.7 and Platform Event Traps
+type SELEvent struct {
+	RecordID     uint16
+	RecordType   uint8
+	Timestamp    time.Time // zero if unspecified
+	GeneratorID  uint16    // slave address or software ID, then channel and LUN
+	EvMRev       uint8
+	SensorType   SDRSensorType
+	SensorNumber uint8
+	EventType    SDRSensorReadingType // event/reading type code
+	Deassertion  bool
+	EventData    [3]uint8
+}
+
+// selTime converts a SEL timestamp, a time relative to the BMC initialization being kept as is
+func selTime(ts uint32) time.Time {
+	if ts == selTimestampUnspecified {
+		return time.Time{}
+	}
+	return time.Unix(int64(ts), 0).UTC()
+}
+
+// selTimestamp converts a time to a SEL timestamp
+func selTimestamp(t time.Time) uint32 {
+	if t.IsZero() {
+		return selTimestampUnspecified
+	}
+	return uint32(t.Unix())
+}
+
+// MarshalBinary encodes the 16 bytes of a system event record
+func (e *SELEvent) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, selRecordSize)
+	binary.LittleEndian.PutUint16(buf[0:], e.RecordID)
+	buf[2] = e.RecordType
+	binary.LittleEndian.PutUint32(buf[3:], selTimestamp(e.Timestamp))
+	binary.LittleEndian.PutUint16(buf[7:], e.GeneratorID)
+	buf[9] = e.EvMRev
+	buf[10] = uint8(e.SensorType)
+	buf[11] = e.SensorNumber
+	buf[12] = boolBit(e.Deassertion, 0x80) | uint8(e.EventType)&0x7f
+	copy(buf[13:], e.EventData[:])
+	return buf, nil
+}
+
+// UnmarshalBinary decodes the 16 bytes of a system event record
+func (e *SELEvent) UnmarshalBinary(buf []byte) error {
+	if len(buf) < selRecordSize {
+		return ErrShortPacket
+	}
+	e.RecordID = binary.LittleEndian.Uint16(buf[0:])
+	e.RecordType = buf[2]
+	e.Timestamp = selTime(binary.LittleEndian.Uint32(buf[3:]))
+	e.GeneratorID = binary.LittleEndian.Uint16(buf[7:])
+	e.EvMRev = buf[9]
+	e.SensorType = SDRSensorType(buf[10])
+	e.SensorNumber = buf[11]
+	e.Deassertion = buf[12]&0x80 != 0
+	e.EventType = SDRSensorReadingType(buf[12] & 0x7f)
+	copy(e.EventData[:], buf[13:16])
+	return nil
+}
+
+// marshalEventMessage encodes the event message data of section 29.3 without the generator ID,
+// which is only part of the request over the system interface
+func marshalEventMessage(e *SELEvent) []byte {
+	buf := []byte{
+		e.EvMRev,
+		uint8(e.SensorType),
+		e.SensorNumber,
+		boolBit(e.Deassertion, 0x80) | uint8(e.EventType)&0x7f,
+	}
+	return append(buf, e.EventData[:]...)
+}
+
+// unmarshalEventMessage decodes the event message data of section 29.3 as a system event
+func unmarshalEventMessage(buf []byte) *SELEvent {
+	e := &SELEvent{
+		RecordType:   SELRecordSystemEvent,
+		EvMRev:       buf[0],
+		SensorType:   SDRSensorType(buf[1]),
+		SensorNumber: buf[2],
+		EventType:    SDRSensorReadingType(buf[3] & 0x7f),
+		Deassertion:  buf[3]&0x80 != 0,
+	}
+	copy(e.EventData[:], buf[4:7])
+	return e
+}
+
+// Offset is the event offset, the state or threshold crossing of the event
+func (e *SELEvent) Offset() uint8 {
+	return e.EventData[0] & 0x0f
+}
+
+// SensorTypeName is the name of the sensor type
+func (e *SELEvent) SensorTypeName() string {
+	return sensorTypeName(e.SensorType)
+}
+
+// IsRelativeTime tells whether the timestamp is relative to the BMC initialization
+// rather than an absolute time, the BMC clock not being set when the event was logged
+func (e *SELEvent) IsRelativeTime() bool {
+	return !e.Timestamp.IsZero() && e.Timestamp.Unix() <= selTimestampInitMax
+}
@@ -0,0 +1,65 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSELEvent(t *testing.T) {
+	event := &SELEvent{
+		RecordID:     0x0102,
+		RecordType:   SELRecordSystemEvent,
+		Timestamp:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		GeneratorID:  0x0020,
+		EvMRev:       SELEvMRev,
+		SensorType:   SDR_SENSOR_TYPECODES_TEMPERATURE,
+		SensorNumber: 0x30,
+		EventType:    SENSOR_READTYPE_THREADHOLD,
+		Deassertion:  true,
+		EventData:    [3]uint8{0x59, 0x40, 0x50},
+	}
+
+	data, err := event.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, rawDecode("02 01 02 00 e1 0b 5e 20 00 04 01 30 81 59 40 50"), data)
+
+	decoded := &SELEvent{}
+	err = decoded.UnmarshalBinary(data)
+	assert.NoError(t, err)
+	assert.Equal(t, event, decoded)
+	assert.Equal(t, uint8(9), decoded.Offset())
+	assert.Equal(t, "Temperature", decoded.SensorTypeName())
+	assert.False(t, decoded.IsRelativeTime())
+
+	// logged before the BMC clock was set
+	err = decoded.UnmarshalBinary(rawDecode("03 00 02 10 00 00 00 20 00 04 01 30 01 51 40 50"))
+	assert.NoError(t, err)
+	assert.True(t, decoded.IsRelativeTime())
+
+	// unspecified timestamp
+	err = decoded.UnmarshalBinary(rawDecode("04 00 02 ff ff ff ff 20 00 04 01 30 01 51 40 50"))
+	assert.NoError(t, err)
+	assert.True(t, decoded.Timestamp.IsZero())
+	assert.False(t, decoded.IsRelativeTime())
+
+	err = decoded.UnmarshalBinary(data[:10])
+	assert.Equal(t, ErrShortPacket, err)
+}
@@ -0,0 +1,260 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// section 35.14, threshold comparison status of a threshold sensor reading
+type ThresholdStatus uint8
+
+const (
+	ThresholdLowerNonCritical ThresholdStatus = 1 << iota
+	ThresholdLowerCritical
+	ThresholdLowerNonRecoverable
+	ThresholdUpperNonCritical
+	ThresholdUpperCritical
+	ThresholdUpperNonRecoverable
+)
+
+// IsUpper reports whether an upper threshold is crossed
+func (s ThresholdStatus) IsUpper() bool {
+	return s&(ThresholdUpperNonCritical|ThresholdUpperCritical|ThresholdUpperNonRecoverable) != 0
+}
+
+// IsLower reports whether a lower threshold is crossed
+func (s ThresholdStatus) IsLower() bool {
+	return s&(ThresholdLowerNonCritical|ThresholdLowerCritical|ThresholdLowerNonRecoverable) != 0
+}
+
+// String returns the most severe status as shown by ipmitool: ok, nc, cr or nr
+func (s ThresholdStatus) String() string {
+	switch {
+	case s&(ThresholdUpperNonRecoverable|ThresholdLowerNonRecoverable) != 0:
+		return "nr"
+	case s&(ThresholdUpperCritical|ThresholdLowerCritical) != 0:
+		return "cr"
+	case s&(ThresholdUpperNonCritical|ThresholdLowerNonCritical) != 0:
+		return "nc"
+	}
+	return "ok"
+}
+
+// Description returns the most severe status in words, e.g. "Upper Critical"
+func (s ThresholdStatus) Description() string {
+	for _, d := range []struct {
+		status ThresholdStatus
+		name   string
+	}{
+		{ThresholdUpperNonRecoverable, "Upper Non-Recoverable"},
+		{ThresholdLowerNonRecoverable, "Lower Non-Recoverable"},
+		{ThresholdUpperCritical, "Upper Critical"},
+		{ThresholdLowerCritical, "Lower Critical"},
+		{ThresholdUpperNonCritical, "Upper Non-Critical"},
+		{ThresholdLowerNonCritical, "Lower Non-Critical"},
+	} {
+		if s&d.status != 0 {
+			return d.name
+		}
+	}
+	return "OK"
+}
+
+// SensorState is an asserted state of a discrete sensor
+type SensorState struct {
+	Offset uint8
+	Name   string
+}
+
+// SensorReading is the decoded Get Sensor Reading response of a sensor
+type SensorReading struct {
+	Raw                   uint8
+	EventMessagesDisabled bool
+	ScanningDisabled      bool
+	Unavailable           bool
+	// threshold sensors
+	Threshold ThresholdStatus
+	// discrete and sensor-specific sensors
+	States []SensorState
+}
+
+// StateNames returns the names of the asserted states
+func (r *SensorReading) StateNames() []string {
+	names := make([]string, len(r.States))
+	for i, state := range r.States {
+		names[i] = state.Name
+	}
+	return names
+}
+
+// String returns the threshold status of threshold sensors and the asserted states of discrete sensors
+func (r *SensorReading) String() string {
+	if r.Unavailable {
+		return "na"
+	}
+	if len(r.States) > 0 {
+		return strings.Join(r.StateNames(), ", ")
+	}
+	return r.Threshold.String()
+}
+
+// NewSensorReading decodes a Get Sensor Reading response with the event/reading type and sensor type of the sensor
+func NewSensorReading(res *GetSensorReadingResponse, readingType SDRSensorReadingType, sensorType SDRSensorType) SensorReading {
+	reading := SensorReading{
+		Raw:                   res.SensorReading,
+		EventMessagesDisabled: res.EventMessagesDisabled(),
+		ScanningDisabled:      res.ScanningDisabled(),
+		Unavailable:           res.ReadingUnavailable(),
+	}
+	if reading.Unavailable {
+		return reading
+	}
+	if readingType == SENSOR_READTYPE_THREADHOLD {
+		reading.Threshold = res.ThresholdStatus()
+		return reading
+	}
+	mask := res.StateMask()
+	for offset := uint8(0); offset < 15; offset++ {
+		if mask&(1<<offset) != 0 {
+			reading.States = append(reading.States, SensorState{
+				Offset: offset,
+				Name:   SensorStateName(readingType, sensorType, offset),
+			})
+		}
+	}
+	return reading
+}
+
+// SensorStateName returns the name of a discrete state offset, section 42.2
+func SensorStateName(readingType SDRSensorReadingType, sensorType SDRSensorType, offset uint8) string {
+	var names []string
+	if readingType == SENSOR_READTYPE_SENSORSPECIF {
+		names = sensorSpecificStates[sensorType]
+	} else {
+		names = sensorGenericStates[readingType]
+	}
+	if int(offset) < len(names) && names[offset] != "" {
+		return names[offset]
+	}
+	return fmt.Sprintf("State %d", offset)
+}
+
+// section 42.2, Table 42-2 generic event/reading type codes
+var sensorGenericStates = map[SDRSensorReadingType][]string{
+	0x02: {"Transition to Idle", "Transition to Active", "Transition to Busy"},
+	0x03: {"State Deasserted", "State Asserted"},
+	0x04: {"Predictive Failure Deasserted", "Predictive Failure Asserted"},
+	0x05: {"Limit Not Exceeded", "Limit Exceeded"},
+	0x06: {"Performance Met", "Performance Lags"},
+	0x07: {"Transition to OK", "Transition to Non-critical from OK",
+		"Transition to Critical from less severe", "Transition to Non-recoverable from less severe",
+		"Transition to Non-critical from more severe", "Transition to Critical from Non-recoverable",
+		"Transition to Non-recoverable", "Monitor", "Informational"},
+	0x08: {"Device Absent", "Device Present"},
+	0x09: {"Device Disabled", "Device Enabled"},
+	0x0a: {"Transition to Running", "Transition to In Test", "Transition to Power Off",
+		"Transition to On Line", "Transition to Off Line", "Transition to Off Duty",
+		"Transition to Degraded", "Transition to Power Save", "Install Error"},
+	0x0b: {"Fully Redundant", "Redundancy Lost", "Redundancy Degraded",
+		"Non-Redundant: Sufficient from Redundant", "Non-Redundant: Sufficient from Insufficient",
+		"Non-Redundant: Insufficient Resources", "Redundancy Degraded from Fully Redundant",
+		"Redundancy Degraded from Non-Redundant"},
+	0x0c: {"D0 Power State", "D1 Power State", "D2 Power State", "D3 Power State"},
+}
+
+// section 42.2, Table 42-3 sensor type codes and sensor-specific offsets
+var sensorSpecificStates = map[SDRSensorType][]string{
+	0x05: {"General Chassis intrusion", "Drive Bay intrusion", "I/O Card area intrusion",
+		"Processor area intrusion", "System unplugged from LAN", "Unauthorized dock",
+		"FAN area intrusion"},
+	0x06: {"Front Panel Lockout violation attempted", "Pre-boot password violation - user password",
+		"Pre-boot password violation - setup password", "Pre-boot password violation - network boot password",
+		"Other pre-boot password violation", "Out-of-band access password violation"},
+	0x07: {"IERR", "Thermal Trip", "FRB1/BIST failure", "FRB2/Hang in POST failure",
+		"FRB3/Processor startup/init failure", "Configuration Error", "SM BIOS Uncorrectable CPU-complex Error",
+		"Presence detected", "Disabled", "Terminator presence detected", "Throttled",
+		"Uncorrectable machine check exception", "Correctable machine check error"},
+	0x08: {"Presence detected", "Failure detected", "Predictive failure", "Power Supply AC lost",
+		"AC lost or out-of-range", "AC out-of-range, but present", "Config Error"},
+	0x09: {"Power off/down", "Power cycle", "240VA power down", "Interlock power down",
+		"AC lost", "Soft-power control failure", "Failure detected", "Predictive failure"},
+	0x0c: {"Correctable ECC", "Uncorrectable ECC", "Parity", "Memory Scrub Error",
+		"Memory Device Disabled", "Correctable ECC logging limit reached", "Presence Detected",
+		"Configuration Error", "Spare", "Throttled", "Critical Overtemperature"},
+	0x0d: {"Drive Present", "Drive Fault", "Predictive Failure", "Hot Spare",
+		"Parity Check In Progress", "In Critical Array", "In Failed Array",
+		"Rebuild In Progress", "Rebuild Aborted"},
+	0x0f: {"System Firmware Error", "System Firmware Hang", "System Firmware Progress"},
+	0x10: {"Correctable memory error logging disabled", "Event logging disabled",
+		"Log area reset/cleared", "All event logging disabled", "Log full", "Log almost full"},
+	0x11: {"BIOS Reset", "OS Reset", "OS Shut Down", "OS Power Down", "OS Power Cycle",
+		"OS NMI/Diag Interrupt", "OS Expired", "OS pre-timeout Interrupt"},
+	0x12: {"System Reconfigured", "OEM System boot event", "Undetermined system hardware failure",
+		"Entry added to auxiliary log", "PEF Action", "Timestamp Clock Sync"},
+	0x13: {"Front Panel NMI/Diag Interrupt", "Bus Timeout", "I/O Channel check NMI",
+		"Software NMI", "PCI PERR", "PCI SERR", "EISA failsafe timeout", "Bus Correctable error",
+		"Bus Uncorrectable error", "Fatal NMI", "Bus Fatal Error", "Bus Degraded"},
+	0x14: {"Power Button pressed", "Sleep Button pressed", "Reset Button pressed",
+		"FRU Latch open", "FRU Service"},
+	0x1d: {"Initiated by power up", "Initiated by hard reset", "Initiated by warm reset",
+		"User requested PXE boot", "Automatic boot to diagnostic", "OS initiated hard reset",
+		"OS initiated warm reset", "System Restart"},
+	0x1e: {"No bootable media", "Non-bootable disk in drive", "PXE server not found",
+		"Invalid boot sector", "Timeout waiting for selection"},
+	0x1f: {"A: boot completed", "C: boot completed", "PXE boot completed",
+		"Diagnostic boot completed", "CD-ROM boot completed", "ROM boot completed",
+		"boot completed - device not specified", "Installation started",
+		"Installation completed", "Installation aborted", "Installation failed"},
+	0x20: {"Critical stop during OS load", "Run-time critical stop", "OS graceful stop",
+		"OS graceful shutdown", "PEF initiated soft shutdown", "Agent not responding"},
+	0x21: {"Fault Status", "Identify Status", "Device Installed", "Ready for Device Installation",
+		"Ready for Device Removal", "Slot Power is Off", "Device Removal Request",
+		"Interlock", "Slot is Disabled", "Spare Device"},
+	0x22: {"S0/G0: working", "S1: sleeping with system hw & processor context maintained",
+		"S2: sleeping, processor context lost", "S3: sleeping, processor & hw context lost, memory retained",
+		"S4: non-volatile sleep/suspend-to-disk", "S5/G2: soft-off", "S4/S5: soft-off",
+		"G3: mechanical off", "Sleeping in S1/S2/S3 state", "G1: sleeping",
+		"S5: entered by override", "Legacy ON state", "Legacy OFF state", "", "Unknown"},
+	0x23: {"Timer expired", "Hard reset", "Power down", "Power cycle", "", "", "", "", "Timer interrupt"},
+	0x24: {"Platform generated page", "Platform generated LAN alert",
+		"Platform Event Trap generated", "Platform generated SNMP trap"},
+	0x25: {"Present", "Absent", "Disabled"},
+	0x27: {"Heartbeat Lost", "Heartbeat"},
+	0x28: {"Sensor access degraded or unavailable", "Controller access degraded or unavailable",
+		"Management controller off-line", "Management controller unavailable",
+		"Sensor failure", "FRU failure"},
+	0x29: {"Low", "Failed", "Presence Detected"},
+	0x2a: {"Session Activated", "Session Deactivated", "Invalid Username or Password",
+		"Invalid password disable"},
+	0x2b: {"Hardware change detected", "Firmware or software change detected",
+		"Hardware incompatibility detected", "Firmware or software incompatibility detected",
+		"Invalid or unsupported hardware version", "Invalid or unsupported firmware or software version",
+		"Hardware change success", "Firmware or software change success"},
+	0x2c: {"Not Installed", "Inactive", "Activation Requested", "Activation in Progress",
+		"Active", "Deactivation Requested", "Deactivation in Progress", "Communication lost"},
+}
+
+// stateMask returns the asserted states as a bit mask
+func (r *SensorReading) stateMask() uint16 {
+	var mask uint16
+	for _, state := range r.States {
+		mask |= 1 << state.Offset
+	}
+	return mask
+}
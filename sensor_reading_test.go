@@ -0,0 +1,100 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThresholdStatus(t *testing.T) {
+	tests := []struct {
+		status      ThresholdStatus
+		short       string
+		description string
+		upper       bool
+		lower       bool
+	}{
+		{0, "ok", "OK", false, false},
+		{ThresholdLowerNonCritical, "nc", "Lower Non-Critical", false, true},
+		{ThresholdUpperNonCritical | ThresholdUpperCritical, "cr", "Upper Critical", true, false},
+		{ThresholdLowerNonCritical | ThresholdLowerCritical | ThresholdLowerNonRecoverable, "nr", "Lower Non-Recoverable", false, true},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.short, test.status.String())
+		assert.Equal(t, test.description, test.status.Description())
+		assert.Equal(t, test.upper, test.status.IsUpper())
+		assert.Equal(t, test.lower, test.status.IsLower())
+	}
+}
+
+func TestNewSensorReading(t *testing.T) {
+	// threshold sensor over its upper critical threshold
+	res := &GetSensorReadingResponse{}
+	err := res.UnmarshalBinary([]byte{0x00, 0x5a, 0xc0, 0x18})
+	assert.NoError(t, err)
+	reading := NewSensorReading(res, SENSOR_READTYPE_THREADHOLD, SDR_SENSOR_TYPECODES_TEMPERATURE)
+	assert.Equal(t, uint8(0x5a), reading.Raw)
+	assert.False(t, reading.EventMessagesDisabled)
+	assert.False(t, reading.ScanningDisabled)
+	assert.False(t, reading.Unavailable)
+	assert.Equal(t, ThresholdUpperNonCritical|ThresholdUpperCritical, reading.Threshold)
+	assert.Equal(t, "cr", reading.String())
+	assert.Nil(t, reading.States)
+
+	// power supply presence detected and failure detected
+	err = res.UnmarshalBinary([]byte{0x00, 0x00, 0xc0, 0x03, 0x80})
+	assert.NoError(t, err)
+	reading = NewSensorReading(res, SENSOR_READTYPE_SENSORSPECIF, 0x08)
+	assert.Equal(t, []SensorState{{0, "Presence detected"}, {1, "Failure detected"}}, reading.States)
+	assert.Equal(t, "Presence detected, Failure detected", reading.String())
+
+	// generic redundancy states, upper state bytes
+	err = res.UnmarshalBinary([]byte{0x00, 0x00, 0x40, 0x00, 0x01})
+	assert.NoError(t, err)
+	reading = NewSensorReading(res, 0x0b, 0)
+	assert.True(t, reading.EventMessagesDisabled)
+	assert.Equal(t, []string{"State 8"}, reading.StateNames())
+	err = res.UnmarshalBinary([]byte{0x00, 0x00, 0x40, 0x02})
+	assert.NoError(t, err)
+	reading = NewSensorReading(res, 0x0b, 0)
+	assert.Equal(t, []string{"Redundancy Lost"}, reading.StateNames())
+
+	// scanning disabled, reading unavailable, state bytes omitted
+	err = res.UnmarshalBinary([]byte{0x00, 0x00, 0x20})
+	assert.NoError(t, err)
+	reading = NewSensorReading(res, SENSOR_READTYPE_THREADHOLD, SDR_SENSOR_TYPECODES_TEMPERATURE)
+	assert.True(t, reading.ScanningDisabled)
+	assert.True(t, reading.Unavailable)
+	assert.Equal(t, "na", reading.String())
+
+	err = res.UnmarshalBinary([]byte{0x00, 0x00})
+	assert.Equal(t, ErrShortPacket, err)
+}
+
+func TestSensorStateName(t *testing.T) {
+	assert.Equal(t, "Device Present", SensorStateName(0x08, 0, 1))
+	assert.Equal(t, "Drive Fault", SensorStateName(SENSOR_READTYPE_SENSORSPECIF, 0x0d, 1))
+	assert.Equal(t, "Timer interrupt", SensorStateName(SENSOR_READTYPE_SENSORSPECIF, 0x23, 8))
+	assert.Equal(t, "State 5", SensorStateName(SENSOR_READTYPE_SENSORSPECIF, 0x23, 5))
+	// ACPI power state offset 0Dh is reserved
+	assert.Equal(t, "State 13", SensorStateName(SENSOR_READTYPE_SENSORSPECIF, 0x22, 0x0d))
+	assert.Equal(t, "Unknown", SensorStateName(SENSOR_READTYPE_SENSORSPECIF, 0x22, 0x0e))
+	assert.Equal(t, "State 3", SensorStateName(SENSOR_READTYPE_OEM_L, 0, 3))
+}
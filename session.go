@@ -0,0 +1,190 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"encoding/binary"
+	"net"
+)
+
+// Get Session Info session index values other than the index of an active session, 1 to 63
+const (
+	SessionIndexCurrent  = 0x00
+	SessionIndexByHandle = 0xfe
+	SessionIndexByID     = 0xff
+)
+
+// Session protocols per the Get Session Info channel byte
+const (
+	SessionProtocolIPMI15 = 0x0
+	SessionProtocolIPMI20 = 0x1 // RMCP+
+)
+
+// Activate Session and Close Session completion codes
+const (
+	ErrSessionNoSlot        = CompletionCode(0x81) // Activate Session: no session slot available
+	ErrSessionInvalidID     = CompletionCode(0x87) // Close Session: invalid session ID
+	ErrSessionInvalidHandle = CompletionCode(0x88) // Close Session: invalid session handle
+)
+
+// SessionInfoRequest per section 22.20, Handle or ID select the session
+// for SessionIndexByHandle or SessionIndexByID
+type SessionInfoRequest struct {
+	Index  uint8
+	Handle uint8
+	ID     uint32
+}
+
+// SessionInfoResponse per section 22.20, the session fields
+// are present only when Handle is not zero
+type SessionInfoResponse struct {
+	CompletionCode
+	Handle         uint8
+	MaxSessions    uint8
+	ActiveSessions uint8
+	UserID         uint8
+	Privilege      uint8
+	Protocol       uint8
+	Channel        uint8
+	RemoteIP       net.IP // LAN channels only
+	RemoteMAC      net.HardwareAddr
+	RemotePort     uint16
+}
+
+// MarshalBinary implementation to handle the optional session handle
+func (r *CloseSessionRequest) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 4, 5)
+	binary.LittleEndian.PutUint32(buf, r.SessionID)
+	if r.SessionID == 0 {
+		buf = append(buf, r.Handle)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implementation to handle the optional session handle
+func (r *CloseSessionRequest) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 4 {
+		return ErrShortPacket
+	}
+	r.SessionID = binary.LittleEndian.Uint32(buf)
+	r.Handle = 0
+	if r.SessionID == 0 {
+		if len(buf) < 5 {
+			return ErrShortPacket
+		}
+		r.Handle = buf[4]
+	}
+	return nil
+}
+
+// MarshalBinary implementation to handle the variable length selector
+func (r *SessionInfoRequest) MarshalBinary() ([]byte, error) {
+	switch r.Index {
+	case SessionIndexByHandle:
+		return []byte{r.Index, r.Handle}, nil
+	case SessionIndexByID:
+		buf := make([]byte, 5)
+		buf[0] = r.Index
+		binary.LittleEndian.PutUint32(buf[1:], r.ID)
+		return buf, nil
+	default:
+		return []byte{r.Index}, nil
+	}
+}
+
+// UnmarshalBinary implementation to handle the variable length selector
+func (r *SessionInfoRequest) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1 {
+		return ErrShortPacket
+	}
+	r.Index = buf[0]
+	switch r.Index {
+	case SessionIndexByHandle:
+		if len(buf) < 2 {
+			return ErrShortPacket
+		}
+		r.Handle = buf[1]
+	case SessionIndexByID:
+		if len(buf) < 5 {
+			return ErrShortPacket
+		}
+		r.ID = binary.LittleEndian.Uint32(buf[1:])
+	}
+	return nil
+}
+
+// MarshalBinary implementation to handle the optional session and LAN fields
+func (r *SessionInfoResponse) MarshalBinary() ([]byte, error) {
+	buf := []byte{
+		byte(r.CompletionCode),
+		r.Handle,
+		r.MaxSessions & 0x3f,
+		r.ActiveSessions & 0x3f,
+	}
+	if r.Handle == 0 {
+		return buf, nil
+	}
+	buf = append(buf, r.UserID&0x3f, r.Privilege&0x0f, r.Protocol<<4|r.Channel&0x0f)
+	if ip := r.RemoteIP.To4(); ip != nil {
+		buf = append(buf, ip...)
+		mac := make([]byte, 6)
+		copy(mac, r.RemoteMAC)
+		buf = append(buf, mac...)
+		buf = append(buf, uint8(r.RemotePort), uint8(r.RemotePort>>8))
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implementation to handle the optional session and LAN fields
+func (r *SessionInfoResponse) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 4 {
+		return ErrShortPacket
+	}
+	*r = SessionInfoResponse{
+		CompletionCode: CompletionCode(buf[0]),
+		Handle:         buf[1],
+		MaxSessions:    buf[2] & 0x3f,
+		ActiveSessions: buf[3] & 0x3f,
+	}
+	if len(buf) < 7 {
+		// no active session at the index
+		return nil
+	}
+	r.UserID = buf[4] & 0x3f
+	r.Privilege = buf[5] & 0x0f
+	r.Protocol = buf[6] >> 4
+	r.Channel = buf[6] & 0x0f
+	if len(buf) >= 19 {
+		r.RemoteIP = net.IPv4(buf[7], buf[8], buf[9], buf[10])
+		r.RemoteMAC = net.HardwareAddr(append([]byte(nil), buf[11:17]...))
+		r.RemotePort = binary.LittleEndian.Uint16(buf[17:19])
+	}
+	return nil
+}
+
+// IsActive tells whether a session is active at the requested index
+func (r *SessionInfoResponse) IsActive() bool {
+	return r.Handle != 0
+}
+
+// RemoteAddr is the IP address and port of the remote console, nil if not known
+func (r *SessionInfoResponse) RemoteAddr() *net.UDPAddr {
+	if r.RemoteIP == nil {
+		return nil
+	}
+	return &net.UDPAddr{IP: r.RemoteIP, Port: int(r.RemotePort)}
+}
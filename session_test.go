@@ -0,0 +1,75 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionInfoParse(t *testing.T) {
+	res := &SessionInfoResponse{}
+	err := responseFromString("02 08 03 03 04 11 c0 a8 01 0a 00 1b 21 3c 4d 5e e8 a2", res)
+	assert.NoError(t, err)
+	assert.True(t, res.IsActive())
+	assert.Equal(t, uint8(2), res.Handle)
+	assert.Equal(t, uint8(8), res.MaxSessions)
+	assert.Equal(t, uint8(3), res.ActiveSessions)
+	assert.Equal(t, uint8(3), res.UserID)
+	assert.Equal(t, uint8(PrivLevelAdmin), res.Privilege)
+	assert.Equal(t, uint8(SessionProtocolIPMI20), res.Protocol)
+	assert.Equal(t, uint8(1), res.Channel)
+	assert.Equal(t, "192.168.1.10:41704", res.RemoteAddr().String())
+	assert.Equal(t, "00:1b:21:3c:4d:5e", res.RemoteMAC.String())
+
+	data, err := res.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, rawDecode("00 02 08 03 03 04 11 c0 a8 01 0a 00 1b 21 3c 4d 5e e8 a2"), data)
+
+	// no session at the index
+	err = responseFromString("00 08 03", res)
+	assert.NoError(t, err)
+	assert.False(t, res.IsActive())
+	assert.Nil(t, res.RemoteAddr())
+}
+
+func TestSessionRequests(t *testing.T) {
+	tests := []struct {
+		req  interface{}
+		data string
+	}{
+		{&SessionInfoRequest{Index: SessionIndexCurrent}, "00"},
+		{&SessionInfoRequest{Index: 3}, "03"},
+		{&SessionInfoRequest{Index: SessionIndexByHandle, Handle: 2}, "fe 02"},
+		{&SessionInfoRequest{Index: SessionIndexByID, ID: 0x04030201}, "ff 01 02 03 04"},
+		{&CloseSessionRequest{SessionID: 0x04030201}, "01 02 03 04"},
+		{&CloseSessionRequest{Handle: 2}, "00 00 00 00 02"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, rawDecode(test.data), messageDataToBytes(test.req))
+	}
+
+	req := &SessionInfoRequest{}
+	err := messageDataFromBytes([]byte{0xff, 0x01}, req)
+	assert.Equal(t, ErrShortPacket, err)
+	closeReq := &CloseSessionRequest{}
+	err = messageDataFromBytes([]byte{0x00, 0x00, 0x00, 0x00, 0x03}, closeReq)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(3), closeReq.Handle)
+}
@@ -17,9 +17,7 @@ limitations under the License.
 package ipmi
 
 import (
-	"bytes"
 	//"fmt"
-	"hash/adler32"
 	"log"
 	"net"
 	"sync"
@@ -33,47 +31,154 @@ type Handler func(*Message) Response
 // Simulator for IPMI
 type Simulator struct {
 	wg       sync.WaitGroup
+	mu       sync.Mutex // serializes handlers with the simulated timers
 	addr     net.UDPAddr
 	conn     *net.UDPConn
 	handlers map[NetworkFunction]map[Command]Handler
-	ids      map[uint32]string
+	sessions map[uint32]*simulatorSession
 	bopts    [BootParamInitMbox + 1][]uint8
+	mailbox  map[uint8][]uint8 // boot initiator mailbox blocks by block number
+	fru      map[uint8][]byte  // logical FRU devices by FRU Device ID
+	i2c      map[uint16][]byte // non-intelligent FRU devices by bus ID and slave address
+	// largest Get SDR ByteToRead accepted, 0 for no limit
+	sdrMaxRead uint8
+	sensors    map[uint8]*simulatorSensor // threshold sensor state by sensor number
+	chassis    simulatorChassis
+	watchdog   simulatorWatchdog
+	device     simulatorDevice
+	users      []*simulatorUser // by user ID, index 0 is unused
+	channels   map[uint8]*simulatorChannel
+	lan        simulatorLAN
+	sol        simulatorSOL
+	pef        simulatorPEF
+	alert      simulatorAlert
+	events     simulatorEvents
+	dcmi       simulatorDCMI
 }
 
 // NewSimulator constructs a Simulator with the given addr
 func NewSimulator(addr net.UDPAddr) *Simulator {
 	s := &Simulator{
 		addr:     addr,
-		ids:      map[uint32]string{},
+		sessions: map[uint32]*simulatorSession{},
 		handlers: map[NetworkFunction]map[Command]Handler{},
+		fru:      map[uint8][]byte{},
+		i2c:      map[uint16][]byte{},
+		chassis:  newSimulatorChassis(),
+		device:   newSimulatorDevice(),
+		users:    newSimulatorUsers(),
+		channels: newSimulatorChannels(),
+		lan:      newSimulatorLAN(),
+		sol:      newSimulatorSOL(),
+		pef:      newSimulatorPEF(),
+		alert:    newSimulatorAlert(),
+		events:   newSimulatorEvents(),
+		dcmi:     newSimulatorDCMI(),
 	}
 
 	// Built-in handlers for session management
 	s.handlers[NetworkFunctionApp] = map[Command]Handler{
 		CommandGetDeviceID:              s.deviceID,
+		CommandColdReset:                s.coldReset,
+		CommandWarmReset:                s.warmReset,
+		CommandGetSelfTestResults:       s.getSelfTestResults,
+		CommandSetACPIPowerState:        s.setACPIPowerState,
+		CommandGetACPIPowerState:        s.getACPIPowerState,
+		CommandGetDeviceGUID:            s.getDeviceGUID,
+		CommandGetSystemGUID:            s.getSystemGUID,
+		CommandSetChannelAccess:         s.setChannelAccess,
+		CommandGetChannelAccess:         s.getChannelAccess,
+		CommandGetChannelInfo:           s.getChannelInfo,
+		CommandGetChannelCipherSuites:   s.getChannelCipherSuites,
+		CommandSetUserAccess:            s.setUserAccess,
+		CommandGetUserAccess:            s.getUserAccess,
+		CommandSetUserName:              s.setUserName,
+		CommandGetUserName:              s.getUserName,
+		CommandSetUserPassword:          s.setUserPassword,
+		CommandSetUserPayloadAccess:     s.setUserPayloadAccess,
+		CommandGetUserPayloadAccess:     s.getUserPayloadAccess,
 		CommandGetAuthCapabilities:      s.authCapabilities,
 		CommandGetSessionChallenge:      s.sessionChallenge,
 		CommandActivateSession:          s.sessionActivate,
 		CommandSetSessionPrivilegeLevel: s.sessionPrivilege,
 		CommandCloseSession:             s.sessionClose,
+		CommandGetSessionInfo:           s.getSessionInfo,
+		CommandSetBMCGlobalEnables:      s.setBMCGlobalEnables,
+		CommandGetBMCGlobalEnables:      s.getBMCGlobalEnables,
+		CommandReadEventMessageBuffer:   s.readEventMessageBuffer,
+		CommandMasterWriteRead:          s.masterWriteRead,
+		CommandResetWatchdog:            s.resetWatchdog,
+		CommandSetWatchdog:              s.setWatchdog,
+		CommandGetWatchdog:              s.getWatchdog,
 	}
 
 	s.handlers[NetworkFunctionStorge] = map[Command]Handler{
 		CommandGetSDRRepositoryInfo: s.repositoryInfo,
 		CommandGetReserveSDRRepo:    s.reserveRepository,
 		CommandGetSDR:               s.getSDR,
+
+		CommandGetFRUInventoryAreaInfo: s.fruInventoryAreaInfo,
+		CommandReadFRUData:             s.readFRUData,
+
+		CommandGetSELEntry: s.getSELEntry,
 	}
 
 	// Built-in handlers for chassis commands
 	s.handlers[NetworkFunctionChassis] = map[Command]Handler{
-		CommandChassisStatus:        s.chassisStatus,
-		CommandGetSystemBootOptions: s.getSystemBootOptions,
-		CommandSetSystemBootOptions: s.setSystemBootOptions,
+		CommandChassisStatus:         s.chassisStatus,
+		CommandChassisControl:        s.chassisControl,
+		CommandChassisIdentify:       s.chassisIdentify,
+		CommandSetPowerRestorePolicy: s.setPowerRestorePolicy,
+		CommandGetSystemRestartCause: s.getSystemRestartCause,
+		CommandSetFrontPanelEnables:  s.setFrontPanelEnables,
+		CommandSetPowerCycleInterval: s.setPowerCycleInterval,
+		CommandGetPOHCounter:         s.getPOHCounter,
+		CommandGetSystemBootOptions:  s.getSystemBootOptions,
+		CommandSetSystemBootOptions:  s.setSystemBootOptions,
 	}
 
 	// Built-in handlers for Sensor/Event commands
 	s.handlers[NetworkFunctionSensorEvent] = map[Command]Handler{
-		CommandGetSensorReading: s.getSensorReading,
+		CommandSetEventReceiver:        s.setEventReceiver,
+		CommandGetEventReceiver:        s.getEventReceiver,
+		CommandPlatformEvent:           s.platformEvent,
+		CommandGetSensorReading:        s.getSensorReading,
+		CommandGetSensorReadingFactors: s.getSensorReadingFactors,
+		CommandGetSensorThresholds:     s.getSensorThresholds,
+		CommandSetSensorThresholds:     s.setSensorThresholds,
+		CommandGetSensorHysteresis:     s.getSensorHysteresis,
+		CommandSetSensorHysteresis:     s.setSensorHysteresis,
+		CommandGetSensorEventEnable:    s.getSensorEventEnable,
+		CommandSetSensorEventEnable:    s.setSensorEventEnable,
+		CommandGetPEFCapabilities:      s.getPEFCapabilities,
+		CommandArmPEFPostponeTimer:     s.armPEFPostponeTimer,
+		CommandGetPEFConfig:            s.getPEFConfig,
+		CommandSetPEFConfig:            s.setPEFConfig,
+		CommandAlertImmediate:          s.alertImmediate,
+		CommandPETAcknowledge:          s.petAcknowledge,
+	}
+
+	// Built-in handlers for transport commands
+	s.handlers[NetworkFunctionTransport] = map[Command]Handler{
+		CommandGetLANConfig: s.getLANConfig,
+		CommandSetLANConfig: s.setLANConfig,
+		CommandGetSOLConfig: s.getSOLConfig,
+		CommandSetSOLConfig: s.setSOLConfig,
+	}
+
+	// Built-in handlers for DCMI commands
+	s.handlers[NetworkFunctionGroupExtension] = map[Command]Handler{
+		CommandGetDCMICapabilities:    s.getDCMICapabilities,
+		CommandGetPowerReading:        s.getPowerReading,
+		CommandGetPowerLimit:          s.getPowerLimit,
+		CommandSetPowerLimit:          s.setPowerLimit,
+		CommandActivatePowerLimit:     s.activatePowerLimit,
+		CommandGetAssetTag:            s.getDCMIString,
+		CommandSetAssetTag:            s.setDCMIString,
+		CommandGetMCIDString:          s.getDCMIString,
+		CommandSetMCIDString:          s.setDCMIString,
+		CommandGetDCMISensorInfo:      s.getDCMISensorInfo,
+		CommandGetTemperatureReadings: s.getTemperatureReadings,
 	}
 
 	return s
@@ -81,6 +186,9 @@ func NewSimulator(addr net.UDPAddr) *Simulator {
 
 // SetHandler sets the command handler for the given netfn and command
 func (s *Simulator) SetHandler(netfn NetworkFunction, command Command, handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.handlers[netfn][command] = handler
 }
 
@@ -124,13 +232,10 @@ func (s *Simulator) Run() error {
 func (s *Simulator) Stop() {
 	_ = s.conn.Close()
 	s.wg.Wait()
-}
 
-func (s *Simulator) chassisStatus(*Message) Response {
-	return &ChassisStatusResponse{
-		CompletionCode: CommandCompleted,
-		PowerState:     SystemPower,
-	}
+	s.mu.Lock()
+	s.watchdog.stop()
+	s.mu.Unlock()
 }
 
 func (s *Simulator) getSystemBootOptions(m *Message) Response {
@@ -139,6 +244,23 @@ func (s *Simulator) getSystemBootOptions(m *Message) Response {
 		return err
 	}
 
+	if int(r.Param) >= len(s.bopts) {
+		return ErrParamRange
+	}
+
+	if r.Param == BootParamInitMbox {
+		block, ok := s.mailbox[r.Set]
+		if !ok {
+			return ErrParamRange
+		}
+		return &SystemBootOptionsResponse{
+			CompletionCode: CommandCompleted,
+			Version:        0x01,
+			Param:          r.Param,
+			Data:           append([]uint8{r.Set}, block...),
+		}
+	}
+
 	return &SystemBootOptionsResponse{
 		CompletionCode: CommandCompleted,
 		Version:        0x01,
@@ -153,77 +275,49 @@ func (s *Simulator) setSystemBootOptions(m *Message) Response {
 		return err
 	}
 
-	s.bopts[r.Param] = r.Data
-
-	return &SetSystemBootOptionsResponse{}
-}
-
-func (s *Simulator) deviceID(*Message) Response {
-	return &DeviceIDResponse{
-		CompletionCode: CommandCompleted,
-		IPMIVersion:    0x51, // 1.5
-	}
-}
-
-func (s *Simulator) authCapabilities(*Message) Response {
-	return &AuthCapabilitiesResponse{
-		CompletionCode:  CommandCompleted,
-		ChannelNumber:   0x01,
-		AuthTypeSupport: authTypeSupport,
+	if int(r.Param) >= len(s.bopts) {
+		return ErrParamRange
 	}
-}
 
-func (s *Simulator) sessionChallenge(m *Message) Response {
-	// Convert username to a uint32 and use as the SessionID.
-	// The SessionID will be propagated such that all requests
-	// for this session include the ID, which can be used to
-	// dispatch requests.
-	username := bytes.TrimRight(m.Data[1:], "\000")
-	hash := adler32.New()
-	_, err := hash.Write(username)
-	if err != nil {
-		panic(err)
+	if r.Param == BootParamInitMbox {
+		if len(r.Data) > 1+bootMailboxBlockSize {
+			return ErrLongPacket
+		}
+		if s.mailbox == nil {
+			s.mailbox = make(map[uint8][]uint8)
+		}
+		s.mailbox[r.Data[0]] = r.Data[1:]
+		return &SetSystemBootOptionsResponse{}
 	}
-	id := hash.Sum32()
 
-	s.ids[id] = string(username)
-
-	return &SessionChallengeResponse{
-		CompletionCode:     CommandCompleted,
-		TemporarySessionID: id,
-	}
-}
+	s.bopts[r.Param] = r.Data
 
-func (s *Simulator) sessionActivate(m *Message) Response {
-	return &ActivateSessionResponse{
-		CompletionCode: CommandCompleted,
-		AuthType:       m.AuthType,
-		SessionID:      m.SessionID,
-		InboundSeq:     m.Sequence,
-		MaxPriv:        PrivLevelAdmin,
-	}
+	return &SetSystemBootOptionsResponse{}
 }
 
-func (s *Simulator) sessionPrivilege(m *Message) Response {
-	return &SessionPrivilegeLevelResponse{
-		CompletionCode:    CommandCompleted,
-		NewPrivilegeLevel: m.Data[0],
+func (s *Simulator) deviceID(*Message) Response {
+	return &DeviceIDResponse{
+		CompletionCode:          CommandCompleted,
+		IPMIVersion:             0x51, // 1.5
+		AdditionalDeviceSupport: simulatorDeviceSupport,
 	}
 }
 
-func (s *Simulator) sessionClose(*Message) Response {
-	return CommandCompleted
-}
-
 func (s *Simulator) ipmiCommand(m *Message) []byte {
 	response := Response(ErrInvalidCommand)
 
-	if commands, ok := s.handlers[m.NetFn()]; ok {
+	s.mu.Lock()
+	if s.device.initializing() {
+		response = ErrInitMode
+	} else if commands, ok := s.handlers[m.NetFn()]; ok {
 		if handler, ok := commands[m.Command]; ok {
-			m.RequestID = s.ids[m.SessionID]
+			if session, ok := s.sessions[m.SessionID]; ok {
+				m.RequestID = session.username
+			}
 			response = handler(m)
 		}
 	}
+	s.mu.Unlock()
 
 	//section 5.1
 	lun := uint8(m.ipmiHeader.NetFnRsLUN & 0x03)
@@ -278,6 +372,7 @@ func (s *Simulator) serve() error {
 				log.Print(err)
 				continue
 			}
+			m.RemoteAddr = addr
 			response = s.ipmiCommand(m)
 		default:
 			log.Print(header.unsupportedClass())
@@ -0,0 +1,158 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"net"
+	"time"
+)
+
+// simulated Alert Immediate state
+type simulatorAlert struct {
+	port     int // UDP port of the trap receivers
+	sequence uint16
+	status   map[uint8]uint8  // Alert Immediate status by destination
+	pending  map[uint16]uint8 // destinations waiting for an acknowledgement, by trap sequence
+}
+
+func newSimulatorAlert() simulatorAlert {
+	return simulatorAlert{
+		port:    petPort,
+		status:  map[uint8]uint8{},
+		pending: map[uint16]uint8{},
+	}
+}
+
+// SetAlertPort sets the UDP port the simulated BMC sends Platform Event Traps to, 162 by default
+func (s *Simulator) SetAlertPort(port int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.alert.port = port
+}
+
+// alertEvent is the trap of an alert to the destination
+func (s *Simulator) alertEvent(event *SELEvent) *PETEvent {
+	var community LANCommunityString
+	_ = community.UnmarshalBinary(s.lan.params[LANParamCommunityString])
+
+	e := &PETEvent{
+		Community:    string(community),
+		AgentAddr:    net.IP(s.lan.params[LANParamIPAddress]),
+		GUID:         s.device.systemGUID,
+		Sequence:     s.alert.sequence,
+		UTCOffset:    PETUTCOffsetUnspecified,
+		Severity:     PEFSeverityUnspecified,
+		SensorDevice: bmcSlaveAddr,
+		Language:     0x19, // English
+	}
+
+	if event != nil {
+		e.SELEvent = *event
+		e.SensorDevice = uint8(event.GeneratorID)
+	} else {
+		e.SELEvent = SELEvent{
+			RecordType:  SELRecordSystemEvent,
+			GeneratorID: bmcSlaveAddr,
+			EvMRev:      SELEvMRev,
+			EventData:   [3]uint8{0xff, 0xff, 0xff},
+		}
+	}
+	e.Timestamp = time.Now().UTC().Truncate(time.Second)
+	return e
+}
+
+// sendAlert sends the trap of an alert to the destination, returning the AlertStatus*
+func (s *Simulator) sendAlert(dest uint8, event *SELEvent) uint8 {
+	destType := &LANAlertDestinationType{}
+	_ = destType.UnmarshalBinary(s.lan.sets[LANParamAlertDestinationType][dest])
+	destAddr := &LANAlertDestinationAddr{}
+	_ = destAddr.UnmarshalBinary(s.lan.sets[LANParamAlertDestinationAddr][dest])
+
+	if destType.Type != LANAlertPET {
+		// OEM destinations are dropped
+		return AlertStatusNormalEnd
+	}
+
+	s.alert.sequence++
+	data, _ := s.alertEvent(event).MarshalBinary()
+
+	conn, err := net.DialUDP("udp", nil, &net.UDPAddr{IP: destAddr.IP, Port: s.alert.port})
+	if err != nil {
+		return AlertStatusCallRetryFailed
+	}
+	defer conn.Close()
+	if _, err = conn.Write(data); err != nil {
+		return AlertStatusCallRetryFailed
+	}
+
+	if destType.AckRequired {
+		s.alert.pending[s.alert.sequence] = dest
+		return AlertStatusInProgress
+	}
+	return AlertStatusNormalEnd
+}
+
+func (s *Simulator) alertImmediate(m *Message) Response {
+	r := &AlertImmediateRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	if !s.lanChannel(r.Channel) {
+		return ErrInvalidPacket
+	}
+	if int(r.Destination) > simulatorAlertDestinations {
+		return ErrParamRange
+	}
+
+	res := &AlertImmediateResponse{CompletionCode: CommandCompleted}
+
+	switch r.Operation {
+	case AlertImmediateInitiate:
+		if s.alert.status[r.Destination] == AlertStatusInProgress {
+			return ErrAlertInProgress
+		}
+		s.alert.status[r.Destination] = s.sendAlert(r.Destination, r.Event)
+	case AlertImmediateGetStatus:
+		res.Status = s.alert.status[r.Destination]
+	case AlertImmediateClearStatus:
+		delete(s.alert.status, r.Destination)
+		for seq, dest := range s.alert.pending {
+			if dest == r.Destination {
+				delete(s.alert.pending, seq)
+			}
+		}
+	default:
+		return ErrInvalidPacket
+	}
+
+	return res
+}
+
+func (s *Simulator) petAcknowledge(m *Message) Response {
+	r := &PETAcknowledgeRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	if dest, ok := s.alert.pending[r.Sequence]; ok {
+		delete(s.alert.pending, r.Sequence)
+		s.alert.status[dest] = AlertStatusNormalEnd
+	}
+
+	return &PETAcknowledgeResponse{CommandCompleted}
+}
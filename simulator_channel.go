@@ -0,0 +1,244 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+// simulated BMC channel
+type simulatorChannel struct {
+	medium   uint8
+	protocol uint8
+	sessions uint8
+	// access and privilege limit bits, per ChannelAccessResponse, non-volatile then volatile
+	access [2]uint8
+	priv   [2]uint8
+}
+
+// the cipher suite records of the simulated LAN channel: suites 0 to 3
+var simulatorCipherSuites = []byte{
+	0xc0, 0x00, CipherAuthNone, 0x40 | CipherIntegrityNone, 0x80 | CipherConfidentialityNone,
+	0xc0, 0x01, CipherAuthHMACSHA1, 0x40 | CipherIntegrityNone, 0x80 | CipherConfidentialityNone,
+	0xc0, 0x02, CipherAuthHMACSHA1, 0x40 | CipherIntegrityHMACSHA1_96, 0x80 | CipherConfidentialityNone,
+	0xc0, 0x03, CipherAuthHMACSHA1, 0x40 | CipherIntegrityHMACSHA1_96, 0x80 | CipherConfidentialityAESCBC128,
+}
+
+func newSimulatorChannels() map[uint8]*simulatorChannel {
+	return map[uint8]*simulatorChannel{
+		ChannelPrimaryIPMB: {
+			medium:   ChannelMediumIPMB,
+			protocol: ChannelProtocolIPMB,
+			sessions: ChannelSessionless,
+		},
+		simulatorLANChannel: {
+			medium:   ChannelMediumLAN,
+			protocol: ChannelProtocolIPMB,
+			sessions: ChannelMultiSession,
+			access:   [2]uint8{ChannelAccessAlways, ChannelAccessAlways},
+			priv:     [2]uint8{PrivLevelAdmin, PrivLevelAdmin},
+		},
+		ChannelSystem: {
+			medium:   ChannelMediumSystem,
+			protocol: ChannelProtocolKCS,
+			sessions: ChannelSessionless,
+		},
+	}
+}
+
+// channel resolves ChannelCurrent, returning false for a channel the simulator
+// does not have or that has no sessions, and thus no users or access settings
+func (s *Simulator) channel(num uint8) (uint8, bool) {
+	num &= 0x0f
+	if num == ChannelCurrent {
+		num = simulatorLANChannel
+	}
+	ch, ok := s.channels[num]
+	return num, ok && ch.sessions != ChannelSessionless
+}
+
+// channelAccessIndex maps a Get/Set Channel Access selector to the access index
+func channelAccessIndex(selector uint8) (int, bool) {
+	switch selector & 0xc0 {
+	case ChannelAccessNonVolatile:
+		return 0, true
+	case ChannelAccessVolatile:
+		return 1, true
+	default:
+		return 0, false
+	}
+}
+
+// sessionPrivLimit returns the highest privilege a session of the user may have on the LAN channel
+func (s *Simulator) sessionPrivLimit(user *simulatorUser) uint8 {
+	ch := s.channels[simulatorLANChannel]
+	if ch.access[1]&0x07 == ChannelAccessDisabled {
+		return PrivLevelNoAccess
+	}
+	limit := user.privLimit()
+	if limit != PrivLevelNoAccess && limit > ch.priv[1] {
+		return ch.priv[1]
+	}
+	return limit
+}
+
+func (s *Simulator) authCapabilities(m *Message) Response {
+	r := &AuthCapabilitiesRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	num := r.ChannelNumber & 0x0f
+	if num == ChannelCurrent {
+		num = simulatorLANChannel
+	}
+	ch, ok := s.channels[num]
+	if !ok || ch.sessions == ChannelSessionless {
+		return ErrInvalidPacket
+	}
+
+	res := &AuthCapabilitiesResponse{
+		CompletionCode:  CommandCompleted,
+		ChannelNumber:   num,
+		AuthTypeSupport: authTypeSupport,
+	}
+	if r.ChannelNumber&0x80 != 0 {
+		// v2.0 extended data, only v1.5 sessions are supported
+		res.AuthTypeSupport |= 0x80
+		res.ExtCapabilities = 0x01
+	}
+	for id, user := range s.users {
+		if user == nil || user.status != UserStatusEnabled {
+			continue
+		}
+		switch {
+		case id == 1 && user.name == "":
+			res.Status |= AuthStatusNullUsers
+			if bytesAllZero(user.password) {
+				res.Status |= AuthStatusAnonymousLogin
+			}
+		case user.name != "":
+			res.Status |= AuthStatusNonNullUsers
+		}
+	}
+	res.Status |= ch.access[1] & (AuthStatusUserLevelOff | AuthStatusPerMessageOff)
+	return res
+}
+
+func bytesAllZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Simulator) getChannelInfo(m *Message) Response {
+	r := &ChannelInfoRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	num := r.Channel & 0x0f
+	if num == ChannelCurrent {
+		num = simulatorLANChannel
+	}
+	ch, ok := s.channels[num]
+	if !ok {
+		return ErrInvalidPacket
+	}
+
+	active := uint8(0)
+	if num == simulatorLANChannel {
+		active = s.activeSessions()
+	}
+	return &ChannelInfoResponse{
+		CompletionCode: CommandCompleted,
+		Channel:        num,
+		MediumType:     ch.medium,
+		ProtocolType:   ch.protocol,
+		SessionSupport: ch.sessions<<6 | active&0x3f,
+		VendorID:       [3]uint8{0xf2, 0x1b, 0x00}, // IPMI forum, 7154
+	}
+}
+
+func (s *Simulator) getChannelAccess(m *Message) Response {
+	r := &ChannelAccessRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	num, ok := s.channel(r.Channel)
+	i, valid := channelAccessIndex(r.Type)
+	if !ok || !valid {
+		return ErrInvalidPacket
+	}
+	ch := s.channels[num]
+	return &ChannelAccessResponse{
+		CompletionCode: CommandCompleted,
+		Access:         ch.access[i],
+		Privilege:      ch.priv[i],
+	}
+}
+
+func (s *Simulator) setChannelAccess(m *Message) Response {
+	r := &SetChannelAccessRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	num, ok := s.channel(r.Channel)
+	if !ok {
+		return ErrInvalidPacket
+	}
+	ch := s.channels[num]
+	if i, set := channelAccessIndex(r.Access); set {
+		ch.access[i] = r.Access & 0x3f
+	}
+	if i, set := channelAccessIndex(r.Privilege); set {
+		priv := r.Privilege & 0x0f
+		if priv < PrivLevelCallback || priv > PrivLevelOEM {
+			return ErrInvalidPacket
+		}
+		ch.priv[i] = priv
+	}
+	return &SetChannelAccessResponse{CommandCompleted}
+}
+
+func (s *Simulator) getChannelCipherSuites(m *Message) Response {
+	r := &ChannelCipherSuitesRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	num, ok := s.channel(r.Channel)
+	if !ok || r.Index&0x80 == 0 {
+		// only listing by cipher suite is simulated
+		return ErrInvalidPacket
+	}
+
+	start := int(r.Index&0x3f) * cipherSuitePageSize
+	if start > len(simulatorCipherSuites) {
+		return ErrParamRange
+	}
+	end := start + cipherSuitePageSize
+	if end > len(simulatorCipherSuites) {
+		end = len(simulatorCipherSuites)
+	}
+	return &ChannelCipherSuitesResponse{
+		CompletionCode: CommandCompleted,
+		Channel:        num,
+		Data:           simulatorCipherSuites[start:end],
+	}
+}
@@ -0,0 +1,251 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import "time"
+
+// simulated chassis state
+type simulatorChassis struct {
+	powerState     uint8 // power on, power faults and restore policy
+	lastPowerEvent uint8
+	state          uint8 // intrusion, front panel lockout, drive and fan faults
+	frontPanel     uint8
+	identify       uint8
+	identifyUntil  time.Time
+	restartCause   uint8
+	cycleInterval  uint8
+	poh            uint32    // power-on minutes counted before poweredOn
+	poweredOn      time.Time // zero when the power is off
+	delays         SimulatorPowerDelays
+	pending        []simulatorPowerStep // scheduled power transitions, oldest first
+}
+
+// SimulatorPowerDelays configures how long the simulated chassis takes to change power state
+type SimulatorPowerDelays struct {
+	PowerOn  time.Duration
+	PowerOff time.Duration
+	// SoftOff is the time the simulated OS takes to shut down after an ACPI soft-off
+	SoftOff time.Duration
+	// IgnoreSoftOff simulates an OS that does not react to ACPI soft-off
+	IgnoreSoftOff bool
+}
+
+// a scheduled power state change of the simulated chassis
+type simulatorPowerStep struct {
+	at    time.Time
+	on    bool
+	cause uint8 // restart cause recorded when powering on
+}
+
+func newSimulatorChassis() simulatorChassis {
+	return simulatorChassis{
+		powerState: SystemPower | PowerRestorePolicyAlwaysOff<<5,
+		frontPanel: SleepButtonDisable | DiagButtonDisable | ResetButtonDisable | PowerButtonDisable,
+		poweredOn:  time.Now(),
+	}
+}
+
+// pohMinutes returns the power-on minutes
+func (c *simulatorChassis) pohMinutes() uint32 {
+	if c.poweredOn.IsZero() {
+		return c.poh
+	}
+	return c.poh + uint32(time.Since(c.poweredOn)/time.Minute)
+}
+
+// SetPowerDelays configures the power state transition delays of the simulated chassis,
+// it must be called before Run
+func (s *Simulator) SetPowerDelays(delays SimulatorPowerDelays) {
+	s.chassis.delays = delays
+}
+
+func (c *simulatorChassis) isPowerOn() bool {
+	return c.powerState&SystemPower != 0
+}
+
+// setPower switches the simulated power, accounting for the power-on hours
+func (c *simulatorChassis) setPower(on bool, cause uint8) {
+	if on == c.isPowerOn() {
+		return
+	}
+	if on {
+		c.powerState |= SystemPower
+		c.poweredOn = time.Now()
+		c.restartCause = cause
+		if cause == RestartCauseChassisControl {
+			c.lastPowerEvent = PowerEventCommand
+		}
+		return
+	}
+	c.poh = c.pohMinutes()
+	c.poweredOn = time.Time{}
+	c.powerState &^= SystemPower
+}
+
+// schedule a power transition after delay, relative to the last pending one
+func (c *simulatorChassis) schedule(delay time.Duration, on bool, cause uint8) {
+	at := time.Now()
+	if n := len(c.pending); n > 0 {
+		at = c.pending[n-1].at
+	}
+	c.pending = append(c.pending, simulatorPowerStep{at.Add(delay), on, cause})
+}
+
+// update applies the power transitions that are due
+func (c *simulatorChassis) update() {
+	now := time.Now()
+	for len(c.pending) > 0 && !now.Before(c.pending[0].at) {
+		c.setPower(c.pending[0].on, c.pending[0].cause)
+		c.pending = c.pending[1:]
+	}
+}
+
+func (s *Simulator) chassisControl(m *Message) Response {
+	r := &ChassisControlRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	c := &s.chassis
+	c.update()
+	if r.ChassisControl == ControlPowerDown {
+		// hard power down overrides any transition in progress
+		c.pending = nil
+	} else if len(c.pending) != 0 {
+		return ErrNodeBusy
+	}
+
+	switch r.ChassisControl {
+	case ControlPowerDown:
+		c.schedule(c.delays.PowerOff, false, 0)
+	case ControlPowerUp:
+		c.schedule(c.delays.PowerOn, true, RestartCauseChassisControl)
+	case ControlPowerCycle:
+		if !c.isPowerOn() {
+			// the BMC may not power up a system that is off on power cycle
+			return ErrInvalidState
+		}
+		c.schedule(c.delays.PowerOff, false, 0)
+		c.schedule(time.Duration(c.cycleInterval)*time.Second+c.delays.PowerOn, true, RestartCauseChassisControl)
+	case ControlPowerHardReset:
+		if c.isPowerOn() {
+			c.restartCause = RestartCauseChassisControl
+		}
+	case ControlPowerPulseDiag:
+	case ControlPowerAcpiSoft:
+		if c.isPowerOn() && !c.delays.IgnoreSoftOff {
+			c.schedule(c.delays.SoftOff, false, 0)
+		}
+	default:
+		return ErrParamRange
+	}
+	c.update()
+	return &ChassisControlResponse{CommandCompleted}
+}
+
+func (s *Simulator) chassisStatus(*Message) Response {
+	c := &s.chassis
+	c.update()
+	if c.identify == IdentifyTemporary && time.Now().After(c.identifyUntil) {
+		c.identify = IdentifyOff
+	}
+	return &ChassisStatusResponse{
+		CompletionCode:    CommandCompleted,
+		PowerState:        c.powerState,
+		LastPowerEvent:    c.lastPowerEvent,
+		State:             c.state | 0x40 | c.identify<<4,
+		FrontControlPanel: c.frontPanel,
+	}
+}
+
+func (s *Simulator) chassisIdentify(m *Message) Response {
+	r := &ChassisIdentifyRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	c := &s.chassis
+	switch {
+	case r.Force&0x01 != 0:
+		c.identify = IdentifyIndefinite
+	case r.Interval == 0:
+		c.identify = IdentifyOff
+	default:
+		c.identify = IdentifyTemporary
+		c.identifyUntil = time.Now().Add(time.Duration(r.Interval) * time.Second)
+	}
+	return &ChassisIdentifyResponse{CommandCompleted}
+}
+
+func (s *Simulator) setPowerRestorePolicy(m *Message) Response {
+	r := &SetPowerRestorePolicyRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	switch r.Policy {
+	case PowerRestorePolicyAlwaysOff, PowerRestorePolicyPrevious, PowerRestorePolicyAlwaysOn:
+		s.chassis.powerState = (s.chassis.powerState &^ 0x60) | r.Policy<<5
+	case PowerRestorePolicyUnknown:
+	default:
+		return ErrParamRange
+	}
+	return &SetPowerRestorePolicyResponse{
+		CompletionCode: CommandCompleted,
+		Supported:      1<<PowerRestorePolicyAlwaysOff | 1<<PowerRestorePolicyPrevious | 1<<PowerRestorePolicyAlwaysOn,
+	}
+}
+
+func (s *Simulator) getSystemRestartCause(*Message) Response {
+	return &SystemRestartCauseResponse{
+		CompletionCode: CommandCompleted,
+		Cause:          s.chassis.restartCause,
+	}
+}
+
+func (s *Simulator) setFrontPanelEnables(m *Message) Response {
+	r := &SetFrontPanelEnablesRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	c := &s.chassis
+	disable := r.Disable & 0x0f
+	// only the buttons allowed to be disabled can be
+	if disable&^(c.frontPanel>>4) != 0 {
+		return ErrInvalidPacket
+	}
+	c.frontPanel = (c.frontPanel & 0xf0) | disable
+	return &SetFrontPanelEnablesResponse{CommandCompleted}
+}
+
+func (s *Simulator) setPowerCycleInterval(m *Message) Response {
+	r := &SetPowerCycleIntervalRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+	s.chassis.cycleInterval = r.Interval
+	return &SetPowerCycleIntervalResponse{CommandCompleted}
+}
+
+func (s *Simulator) getPOHCounter(*Message) Response {
+	return &POHCounterResponse{
+		CompletionCode:  CommandCompleted,
+		MinutesPerCount: 60,
+		Counter:         s.chassis.pohMinutes() / 60,
+	}
+}
@@ -0,0 +1,425 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"encoding"
+	"time"
+)
+
+// the rolling average time periods of the simulated enhanced power statistics
+var simulatorPowerPeriods = []time.Duration{time.Minute, 5 * time.Minute, time.Hour}
+
+// simulatorPowerSamples is the number of power draw changes the simulated statistics keep
+const simulatorPowerSamples = 1024
+
+// simulated power limit ranges
+const (
+	simulatorPowerLimitMax     = 1000 // watts
+	simulatorCorrectionTimeMin = 1000 // milliseconds
+	simulatorCorrectionTimeMax = 60000
+	simulatorSamplingPeriodMax = 3600 // seconds
+)
+
+// a change of the simulated power draw
+type simulatorPowerSample struct {
+	at    time.Time
+	watts uint16
+}
+
+// simulated DCMI state
+type simulatorDCMI struct {
+	since       time.Time              // start of the power statistics
+	samples     []simulatorPowerSample // power draw changes, oldest first
+	limit       *DCMIPowerLimit        // nil until set
+	limitActive bool
+	assetTag    []byte
+	mcID        []byte           // null terminated
+	temps       map[uint8][]int8 // temperatures by entity ID and instance
+}
+
+func newSimulatorDCMI() simulatorDCMI {
+	now := time.Now()
+	return simulatorDCMI{
+		since:   now,
+		samples: []simulatorPowerSample{{now, 150}},
+		mcID:    []byte("simulator\x00"),
+		temps: map[uint8][]int8{
+			DCMIEntityInlet:     {22},
+			DCMIEntityCPU:       {45, 47},
+			DCMIEntityBaseboard: {30},
+		},
+	}
+}
+
+// SetPowerDraw sets the power draw of the simulated system in watts,
+// applying the exception action of the power limit when it is exceeded
+func (s *Simulator) SetPowerDraw(watts uint16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := &s.dcmi
+	d.samples = append(d.samples, simulatorPowerSample{time.Now(), watts})
+	if len(d.samples) > simulatorPowerSamples {
+		d.samples = d.samples[1:]
+	}
+	s.checkPowerLimit()
+}
+
+// checkPowerLimit applies the exception action of the active power limit, when exceeded
+func (s *Simulator) checkPowerLimit() {
+	d := &s.dcmi
+	if !d.limitActive || d.samples[len(d.samples)-1].watts <= d.limit.Limit {
+		return
+	}
+
+	c := &s.chassis
+	c.update()
+	if !c.isPowerOn() {
+		return
+	}
+
+	switch d.limit.ExceptionAction {
+	case DCMIExceptionPowerOff:
+		c.pending = nil
+		c.schedule(c.delays.PowerOff, false, 0)
+	case DCMIExceptionLogSEL:
+	default:
+		return
+	}
+
+	// logged as an upper critical going high current event
+	s.logEvent(&SELEvent{
+		RecordType:  SELRecordSystemEvent,
+		GeneratorID: bmcSlaveAddr,
+		EvMRev:      SELEvMRev,
+		SensorType:  SDR_SENSOR_TYPECODES_CURRENT,
+		EventType:   SENSOR_READTYPE_THREADHOLD,
+		EventData:   [3]uint8{0x09, 0xff, 0xff},
+	})
+}
+
+func (s *Simulator) getDCMICapabilities(m *Message) Response {
+	r := &DCMICapabilitiesRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+	if r.DCMIGroup != DCMIGroupExtension {
+		return ErrInvalidPacket
+	}
+
+	var param encoding.BinaryMarshaler
+	switch r.Param {
+	case DCMICapParamSupported:
+		param = &DCMISupportedCapabilities{PowerManagement: true, SecondaryLAN: true}
+	case DCMICapParamMandatoryAttrs:
+		param = &DCMIPlatformAttributes{
+			SELEntries:           1024,
+			SELEntireFlush:       true,
+			SELRollover:          true,
+			AssetTag:             true,
+			InletTemperature:     true,
+			CPUTemperature:       true,
+			BaseboardTemperature: true,
+			TemperatureSampling:  10,
+		}
+	case DCMICapParamPowerStatsAttrs:
+		param = &DCMIPowerStatisticsAttributes{Periods: simulatorPowerPeriods}
+	default:
+		return ErrParamRange
+	}
+
+	data, _ := param.MarshalBinary()
+	return &DCMICapabilitiesResponse{
+		CompletionCode: CommandCompleted,
+		DCMIGroup:      DCMIGroupExtension,
+		MajorVersion:   0x01,
+		MinorVersion:   0x05,
+		Revision:       0x02,
+		Data:           data,
+	}
+}
+
+func (s *Simulator) getPowerReading(m *Message) Response {
+	r := &PowerReadingRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+	if r.DCMIGroup != DCMIGroupExtension {
+		return ErrInvalidPacket
+	}
+
+	now := time.Now()
+	d := &s.dcmi
+	from := d.since
+
+	switch r.Mode {
+	case DCMIPowerStatistics:
+	case DCMIPowerEnhancedStatistics:
+		period := dcmiPeriodDuration(r.Period)
+		supported := false
+		for _, p := range simulatorPowerPeriods {
+			supported = supported || p == period
+		}
+		if !supported {
+			return ErrParamRange
+		}
+		from = now.Add(-period)
+	default:
+		return ErrParamRange
+	}
+
+	res := &PowerReadingResponse{
+		CompletionCode: CommandCompleted,
+		DCMIGroup:      DCMIGroupExtension,
+		Current:        d.samples[len(d.samples)-1].watts,
+		Minimum:        0xffff,
+		Timestamp:      selTimestamp(now),
+		Period:         uint32(now.Sub(from) / time.Millisecond),
+		State:          0x40, // power measurement active
+	}
+
+	// the draws in effect during the period
+	var sum, n uint32
+	for i, sample := range d.samples {
+		if i+1 < len(d.samples) && d.samples[i+1].at.Before(from) {
+			continue
+		}
+		if sample.watts < res.Minimum {
+			res.Minimum = sample.watts
+		}
+		if sample.watts > res.Maximum {
+			res.Maximum = sample.watts
+		}
+		sum += uint32(sample.watts)
+		n++
+	}
+	res.Average = uint16(sum / n)
+
+	return res
+}
+
+func (s *Simulator) getPowerLimit(m *Message) Response {
+	r := &PowerLimitRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+	if r.DCMIGroup != DCMIGroupExtension {
+		return ErrInvalidPacket
+	}
+
+	if s.dcmi.limit == nil {
+		return ErrDCMINoPowerLimit
+	}
+
+	return &PowerLimitResponse{
+		CompletionCode: CommandCompleted,
+		DCMIGroup:      DCMIGroupExtension,
+		DCMIPowerLimit: *s.dcmi.limit,
+	}
+}
+
+func (s *Simulator) setPowerLimit(m *Message) Response {
+	r := &SetPowerLimitRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+	if r.DCMIGroup != DCMIGroupExtension {
+		return ErrInvalidPacket
+	}
+
+	limit := r.DCMIPowerLimit
+	switch {
+	case limit.ExceptionAction > DCMIExceptionLogSEL:
+		return ErrParamRange
+	case limit.Limit == 0 || limit.Limit > simulatorPowerLimitMax:
+		return ErrDCMIPowerLimitRange
+	case limit.CorrectionTime < simulatorCorrectionTimeMin || limit.CorrectionTime > simulatorCorrectionTimeMax:
+		return ErrDCMICorrectionTimeRange
+	case limit.SamplingPeriod == 0 || limit.SamplingPeriod > simulatorSamplingPeriodMax:
+		return ErrDCMISamplingPeriodRange
+	}
+
+	s.dcmi.limit = &limit
+	s.checkPowerLimit()
+
+	return &DCMIResponse{CommandCompleted, DCMIGroupExtension}
+}
+
+func (s *Simulator) activatePowerLimit(m *Message) Response {
+	r := &ActivatePowerLimitRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+	if r.DCMIGroup != DCMIGroupExtension {
+		return ErrInvalidPacket
+	}
+
+	switch {
+	case r.Activate > 0x01:
+		return ErrParamRange
+	case r.Activate == 0x01 && s.dcmi.limit == nil:
+		return ErrDCMINoPowerLimit
+	}
+
+	s.dcmi.limitActive = r.Activate == 0x01
+	s.checkPowerLimit()
+
+	return &DCMIResponse{CommandCompleted, DCMIGroupExtension}
+}
+
+// dcmiString returns the asset tag or management controller ID string of a DCMI string command
+func (s *Simulator) dcmiString(command Command) *[]byte {
+	if command == CommandGetAssetTag || command == CommandSetAssetTag {
+		return &s.dcmi.assetTag
+	}
+	return &s.dcmi.mcID
+}
+
+func (s *Simulator) getDCMIString(m *Message) Response {
+	r := &DCMIStringRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+	if r.DCMIGroup != DCMIGroupExtension {
+		return ErrInvalidPacket
+	}
+
+	str := *s.dcmiString(m.Command)
+	if r.Length > dcmiStringBlockSize || int(r.Offset) > len(str) {
+		return ErrParamRange
+	}
+	end := int(r.Offset) + int(r.Length)
+	if end > len(str) {
+		end = len(str)
+	}
+
+	return &DCMIStringResponse{
+		CompletionCode: CommandCompleted,
+		DCMIGroup:      DCMIGroupExtension,
+		Total:          uint8(len(str)),
+		Data:           str[r.Offset:end],
+	}
+}
+
+func (s *Simulator) setDCMIString(m *Message) Response {
+	r := &SetDCMIStringRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+	if r.DCMIGroup != DCMIGroupExtension {
+		return ErrInvalidPacket
+	}
+
+	size := dcmiMCIDSize
+	if m.Command == CommandSetAssetTag {
+		size = dcmiAssetTagSize
+	}
+
+	str := s.dcmiString(m.Command)
+	if len(r.Data) > dcmiStringBlockSize || int(r.Offset) > len(*str) || int(r.Offset)+len(r.Data) > size {
+		return ErrParamRange
+	}
+	// a write truncates the string after the data written
+	*str = append((*str)[:r.Offset:r.Offset], r.Data...)
+
+	return &DCMIStringResponse{
+		CompletionCode: CommandCompleted,
+		DCMIGroup:      DCMIGroupExtension,
+		Total:          uint8(len(*str)),
+	}
+}
+
+// dcmiInstances returns the 0-based range of the entity instances of a DCMI sensor request
+func (s *Simulator) dcmiInstances(r *DCMISensorRequest) (int, int, int, CompletionCode) {
+	if r.SensorType != DCMISensorTypeTemperature {
+		return 0, 0, 0, ErrParamRange
+	}
+
+	n := len(s.dcmi.temps[r.EntityID])
+	if r.EntityInstance != 0 {
+		// a single instance
+		if int(r.EntityInstance) > n {
+			return 0, 0, 0, ErrParamRange
+		}
+		return n, int(r.EntityInstance) - 1, int(r.EntityInstance), CommandCompleted
+	}
+
+	start := int(r.InstanceStart)
+	if start > 0 {
+		start--
+	}
+	if start > n {
+		start = n
+	}
+	end := start + dcmiRecordsMax
+	if end > n {
+		end = n
+	}
+	return n, start, end, CommandCompleted
+}
+
+func (s *Simulator) getDCMISensorInfo(m *Message) Response {
+	r := &DCMISensorRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+	if r.DCMIGroup != DCMIGroupExtension {
+		return ErrInvalidPacket
+	}
+
+	n, start, end, err := s.dcmiInstances(r)
+	if err != CommandCompleted {
+		return err
+	}
+
+	res := &DCMISensorInfoResponse{
+		CompletionCode: CommandCompleted,
+		DCMIGroup:      DCMIGroupExtension,
+		Total:          uint8(n),
+	}
+	for i := start; i < end; i++ {
+		// made up SDR record IDs of the temperature sensors
+		res.RecordIDs = append(res.RecordIDs, uint16(r.EntityID)<<8|uint16(i+1))
+	}
+	return res
+}
+
+func (s *Simulator) getTemperatureReadings(m *Message) Response {
+	r := &DCMISensorRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+	if r.DCMIGroup != DCMIGroupExtension {
+		return ErrInvalidPacket
+	}
+
+	n, start, end, err := s.dcmiInstances(r)
+	if err != CommandCompleted {
+		return err
+	}
+
+	res := &TemperatureReadingsResponse{
+		CompletionCode: CommandCompleted,
+		DCMIGroup:      DCMIGroupExtension,
+		Total:          uint8(n),
+	}
+	for i := start; i < end; i++ {
+		res.Readings = append(res.Readings, DCMITemperature{s.dcmi.temps[r.EntityID][i], uint8(i + 1)})
+	}
+	return res
+}
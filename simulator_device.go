@@ -0,0 +1,110 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import "time"
+
+// the optional features the simulated BMC implements
+const simulatorDeviceSupport = DeviceSupportChassis | DeviceSupportFRUInventory |
+	DeviceSupportSEL | DeviceSupportSDRRepository | DeviceSupportSensor | DeviceSupportIPMBEventReceiver
+
+// how long the simulated BMC is initializing after a cold reset
+const simulatorResetTime = 200 * time.Millisecond
+
+// simulated BMC device state
+type simulatorDevice struct {
+	resetUntil  time.Time // the BMC is initializing until then
+	systemGUID  [16]byte
+	deviceGUID  [16]byte
+	acpiSystem  uint8
+	acpiDevice  uint8
+	selfTest    uint8
+	selfTestErr uint8
+}
+
+func newSimulatorDevice() simulatorDevice {
+	return simulatorDevice{
+		// 4c4c4544-0042-3510-8056-b4c04f4d4e31, least significant byte first
+		systemGUID: [16]byte{0x31, 0x4e, 0x4d, 0x4f, 0xc0, 0xb4, 0x56, 0x80, 0x10, 0x35, 0x42, 0x00, 0x44, 0x45, 0x4c, 0x4c},
+		deviceGUID: [16]byte{0xab, 0xa4, 0x27, 0x0a, 0x9e, 0x1b, 0xbd, 0x93, 0x11, 0xe4, 0x6f, 0x1d, 0x88, 0x12, 0x5a, 0x3e},
+		acpiSystem: ACPISystemS0,
+		acpiDevice: ACPIDeviceD0,
+		selfTest:   SelfTestPassed,
+	}
+}
+
+func (d *simulatorDevice) initializing() bool {
+	return time.Now().Before(d.resetUntil)
+}
+
+func (s *Simulator) coldReset(*Message) Response {
+	// sessions and the watchdog timer do not survive a BMC reset
+	s.sessions = map[uint32]*simulatorSession{}
+	s.watchdog.stop()
+	s.watchdog = simulatorWatchdog{}
+	s.device.resetUntil = time.Now().Add(simulatorResetTime)
+	return &ColdResetResponse{CommandCompleted}
+}
+
+func (s *Simulator) warmReset(*Message) Response {
+	return &WarmResetResponse{CommandCompleted}
+}
+
+func (s *Simulator) getSelfTestResults(*Message) Response {
+	return &SelfTestResultsResponse{
+		CompletionCode: CommandCompleted,
+		Result:         s.device.selfTest,
+		Detail:         s.device.selfTestErr,
+	}
+}
+
+func (s *Simulator) getSystemGUID(*Message) Response {
+	return &GUIDResponse{
+		CompletionCode: CommandCompleted,
+		GUID:           s.device.systemGUID,
+	}
+}
+
+func (s *Simulator) getDeviceGUID(*Message) Response {
+	return &GUIDResponse{
+		CompletionCode: CommandCompleted,
+		GUID:           s.device.deviceGUID,
+	}
+}
+
+func (s *Simulator) setACPIPowerState(m *Message) Response {
+	r := &SetACPIPowerStateRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	if r.SystemState&0x80 != 0 {
+		s.device.acpiSystem = r.SystemState & 0x7f
+	}
+	if r.DeviceState&0x80 != 0 {
+		s.device.acpiDevice = r.DeviceState & 0x7f
+	}
+	return &SetACPIPowerStateResponse{CommandCompleted}
+}
+
+func (s *Simulator) getACPIPowerState(*Message) Response {
+	return &ACPIPowerStateResponse{
+		CompletionCode: CommandCompleted,
+		SystemState:    s.device.acpiSystem,
+		DeviceState:    s.device.acpiDevice,
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import "time"
+
+// simulatorEventBufferSize is the number of messages the simulated event message buffer holds
+const simulatorEventBufferSize = 4
+
+// simulated event receiver, SEL and event message buffer
+type simulatorEvents struct {
+	receiver    uint8 // event receiver slave address
+	receiverLUN uint8
+	enables     uint8     // BMC global enables
+	sel         [][]uint8 // SEL records, record ID being the index + 1
+	buffer      [][]uint8 // event message buffer, oldest first
+}
+
+func newSimulatorEvents() simulatorEvents {
+	return simulatorEvents{
+		receiver: bmcSlaveAddr,
+		enables:  BMCGlobalSystemEventLogging,
+	}
+}
+
+func (s *Simulator) setEventReceiver(m *Message) Response {
+	r := &SetEventReceiverRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	s.events.receiver = r.Address
+	s.events.receiverLUN = r.LUN & 0x03
+
+	return &SetEventReceiverResponse{CommandCompleted}
+}
+
+func (s *Simulator) getEventReceiver(*Message) Response {
+	return &EventReceiverResponse{
+		CompletionCode: CommandCompleted,
+		Address:        s.events.receiver,
+		LUN:            s.events.receiverLUN,
+	}
+}
+
+// logEvent logs the event in the SEL and queues it in the event message buffer, when enabled
+func (s *Simulator) logEvent(e *SELEvent) {
+	e.Timestamp = time.Now().UTC().Truncate(time.Second)
+
+	if s.events.enables&BMCGlobalSystemEventLogging != 0 {
+		e.RecordID = uint16(len(s.events.sel) + 1)
+		data, _ := e.MarshalBinary()
+		s.events.sel = append(s.events.sel, data)
+	}
+
+	if s.events.enables&BMCGlobalEventBuffer != 0 && len(s.events.buffer) < simulatorEventBufferSize {
+		data, _ := e.MarshalBinary()
+		s.events.buffer = append(s.events.buffer, data)
+	}
+}
+
+func (s *Simulator) platformEvent(m *Message) Response {
+	r := &PlatformEventRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	// the generator ID is the requester, per section 29.3
+	r.Event.GeneratorID = uint16(m.RqAddr) | uint16(simulatorLANChannel<<4|m.RqSeq&0x03)<<8
+	s.logEvent(r.Event)
+
+	return &PlatformEventResponse{CommandCompleted}
+}
+
+func (s *Simulator) setBMCGlobalEnables(m *Message) Response {
+	r := &SetBMCGlobalEnablesRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	s.events.enables = r.Enables
+	if r.Enables&BMCGlobalEventBuffer == 0 {
+		s.events.buffer = nil
+	}
+
+	return &SetBMCGlobalEnablesResponse{CommandCompleted}
+}
+
+func (s *Simulator) getBMCGlobalEnables(*Message) Response {
+	return &BMCGlobalEnablesResponse{
+		CompletionCode: CommandCompleted,
+		Enables:        s.events.enables,
+	}
+}
+
+func (s *Simulator) readEventMessageBuffer(*Message) Response {
+	if len(s.events.buffer) == 0 {
+		return ErrEventBufferEmpty
+	}
+
+	res := &ReadEventMessageBufferResponse{CompletionCode: CommandCompleted}
+	copy(res.Data[:], s.events.buffer[0])
+	s.events.buffer = s.events.buffer[1:]
+
+	return res
+}
+
+func (s *Simulator) getSELEntry(m *Message) Response {
+	r := &SELEntryRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	n := len(s.events.sel)
+	if n == 0 {
+		return ErrNoObj
+	}
+
+	var i int
+	switch r.RecordID {
+	case SELEntryFirst:
+		i = 0
+	case SELEntryLast:
+		i = n - 1
+	default:
+		i = int(r.RecordID) - 1
+		if i >= n {
+			return ErrNoObj
+		}
+	}
+
+	res := &SELEntryResponse{
+		CompletionCode: CommandCompleted,
+		NextRecordID:   SELEntryLast,
+	}
+	if i+1 < n {
+		res.NextRecordID = uint16(i + 2)
+	}
+
+	data := s.events.sel[i]
+	if int(r.Offset) > len(data) {
+		return ErrParamRange
+	}
+	end := int(r.Offset) + int(r.Length)
+	if r.Length == selReadEntireRecord || end > len(data) {
+		end = len(data)
+	}
+	res.Data = data[r.Offset:end]
+
+	return res
+}
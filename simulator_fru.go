@@ -0,0 +1,92 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+func i2cKey(busID, slaveAddr uint8) uint16 {
+	return uint16(busID)<<8 | uint16(slaveAddr)
+}
+
+func (s *Simulator) fruInventoryAreaInfo(m *Message) Response {
+	r := &FRUInventoryAreaInfoRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	data, ok := s.fru[r.FRUDeviceID]
+	if !ok {
+		return ErrNoObj
+	}
+
+	return &FRUInventoryAreaInfoResponse{
+		CompletionCode: CommandCompleted,
+		AreaSize:       uint16(len(data)),
+	}
+}
+
+func (s *Simulator) readFRUData(m *Message) Response {
+	r := &ReadFRUDataRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	data, ok := s.fru[r.FRUDeviceID]
+	if !ok {
+		return ErrNoObj
+	}
+	if int(r.Offset) >= len(data) {
+		return ErrParamRange
+	}
+
+	end := int(r.Offset) + int(r.CountToRead)
+	if end > len(data) {
+		end = len(data)
+	}
+
+	return &ReadFRUDataResponse{
+		CompletionCode: CommandCompleted,
+		CountReturned:  uint8(end - int(r.Offset)),
+		Data:           data[r.Offset:end],
+	}
+}
+
+// masterWriteRead models a SEEPROM: the first byte written sets the word address to read from
+func (s *Simulator) masterWriteRead(m *Message) Response {
+	r := &MasterWriteReadRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	data, ok := s.i2c[i2cKey(r.BusID, r.SlaveAddr)]
+	if !ok || len(data) == 0 {
+		return CompletionCode(0x83) // NAK on write
+	}
+
+	offset := 0
+	if len(r.Data) > 0 {
+		offset = int(r.Data[0])
+	}
+
+	buf := make([]byte, r.ReadCount)
+	for i := range buf {
+		buf[i] = data[(offset+i)%len(data)]
+	}
+
+	return &MasterWriteReadResponse{
+		CompletionCode: CommandCompleted,
+		Data:           buf,
+	}
+}
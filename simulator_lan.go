@@ -0,0 +1,262 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"net"
+	"time"
+)
+
+// number of IPv6 static and dynamic address sets of the simulated LAN channel
+const simulatorIPv6Addresses = 2
+
+// number of non-volatile alert destinations of the simulated LAN channel
+const simulatorAlertDestinations = 3
+
+// simulated LAN channel configuration, parameter data by selector
+type simulatorLAN struct {
+	params map[uint8][]uint8
+	sets   map[uint8][][]uint8 // parameters with a set selector
+}
+
+// parameters the simulated BMC does not let a client write
+var simulatorLANReadOnly = map[uint8]bool{
+	LANParamAuthTypeSupport:       true,
+	LANParamCipherSuiteEntryCount: true,
+	LANParamCipherSuiteEntries:    true,
+	LANParamAlertDestinationCount: true,
+	LANParamIPv6Support:           true,
+	LANParamIPv6Status:            true,
+	LANParamIPv6DynamicAddress:    true,
+}
+
+func newSimulatorLAN() simulatorLAN {
+	lan := simulatorLAN{
+		params: map[uint8][]uint8{
+			LANParamSetInProgress:         {0x00},
+			LANParamCipherSuiteEntryCount: {uint8(len(simulatorCipherSuites) / 5)},
+		},
+		sets: map[uint8][][]uint8{},
+	}
+
+	params := []LANConfigParam{
+		LANAuthTypeSupport(authTypeSupport),
+		&LANAuthTypeEnables{
+			Callback: authTypeSupport,
+			User:     authTypeSupport,
+			Operator: authTypeSupport,
+			Admin:    authTypeSupport,
+		},
+		LANIPSourceStatic,
+		&LANIPAddress{net.IPv4(192, 168, 1, 120)},
+		&LANSubnetMask{net.IPv4Mask(255, 255, 255, 0)},
+		&LANMACAddress{net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x01}},
+		&LANDefaultGateway{net.IPv4(192, 168, 1, 1)},
+		&LANDefaultGatewayMAC{net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0xfe}},
+		&LANBackupGateway{net.IPv4zero},
+		&LANARPControl{Responses: true},
+		LANCommunityString("public"),
+		LANAlertDestinationCount(simulatorAlertDestinations),
+		&LANVLAN{},
+		LANVLANPriority(0),
+		LANCipherSuiteEntries(make([]uint8, lanCipherSuiteEntriesMax)),
+		&LANCipherSuitePrivLevels{},
+		LANIPv6Support(LANIPv6SupportIPv6Only | LANIPv6SupportDualStack),
+		LANIPv4Only,
+		&LANIPv6Status{
+			StaticAddresses:  simulatorIPv6Addresses,
+			DynamicAddresses: simulatorIPv6Addresses,
+			SLAAC:            true,
+		},
+		&LANIPv6RouterConfig{Static: true},
+		&LANIPv6StaticRouter{net.IPv6zero},
+	}
+	for _, p := range params {
+		lan.params[p.Param()], _ = p.MarshalBinary()
+	}
+
+	// cipher suite entries follow the suites of Get Channel Cipher Suites, all allowed up to admin
+	entries := lan.params[LANParamCipherSuiteEntries]
+	levels := &LANCipherSuitePrivLevels{}
+	for i := 0; i < len(simulatorCipherSuites)/5; i++ {
+		entries[1+i] = simulatorCipherSuites[5*i+1]
+		levels[i] = PrivLevelAdmin
+	}
+	lan.params[LANParamCipherSuitePrivLevels], _ = levels.MarshalBinary()
+
+	for i := uint8(0); i < simulatorIPv6Addresses; i++ {
+		addr := LANIPv6Address{
+			Set:     i,
+			Address: net.IPv6zero,
+			Status:  LANIPv6StatusDisabled,
+		}
+		data, _ := addr.MarshalBinary()
+		lan.sets[LANParamIPv6StaticAddress] = append(lan.sets[LANParamIPv6StaticAddress], data)
+
+		addr.Source = LANIPv6SourceSLAAC
+		data, _ = addr.MarshalBinary()
+		lan.sets[LANParamIPv6DynamicAddress] = append(lan.sets[LANParamIPv6DynamicAddress], data)
+	}
+
+	// alert destination 0 is the volatile destination
+	for i := uint8(0); i <= simulatorAlertDestinations; i++ {
+		dest := &LANAlertDestinationType{
+			Set:     i,
+			Type:    LANAlertPET,
+			Timeout: 3 * time.Second,
+			Retries: 3,
+		}
+		data, _ := dest.MarshalBinary()
+		lan.sets[LANParamAlertDestinationType] = append(lan.sets[LANParamAlertDestinationType], data)
+
+		addr := &LANAlertDestinationAddr{
+			Set: i,
+			IP:  net.IPv4zero,
+		}
+		data, _ = addr.MarshalBinary()
+		lan.sets[LANParamAlertDestinationAddr] = append(lan.sets[LANParamAlertDestinationAddr], data)
+	}
+
+	return lan
+}
+
+// simulatorSetInProgress applies a write of the set-in-progress parameter of the LAN, SOL or PEF configuration
+func simulatorSetInProgress(params map[uint8][]uint8, data uint8) CompletionCode {
+	state := data & 0x03
+	if state == 0x01 && params[0][0] == 0x01 {
+		return ErrLANSetInProgress
+	}
+	if state != 0x02 {
+		// writes take effect right away, there is nothing to commit
+		params[0] = []uint8{state}
+	}
+	return CommandCompleted
+}
+
+// simulatorLANSetData checks the data written to a parameter with a set selector, returning the data to keep
+func simulatorLANSetData(param uint8, data []uint8) ([]uint8, CompletionCode) {
+	size := lanIPv6AddressSize
+	switch param {
+	case LANParamAlertDestinationType:
+		size = 4
+	case LANParamAlertDestinationAddr:
+		size = lanAlertAddrIPv4Size
+		if len(data) > 1 && data[1]>>4 == lanAlertAddrIPv6 {
+			size = lanAlertAddrIPv6Size
+		}
+	}
+	if len(data) < size {
+		return nil, ErrShortPacket
+	}
+	data = append([]uint8(nil), data[:size]...)
+
+	if param == LANParamIPv6StaticAddress {
+		// the status is read only, a static address is active once enabled
+		data[19] = LANIPv6StatusDisabled
+		if data[1]&0x80 != 0 {
+			data[19] = LANIPv6StatusActive
+		}
+	}
+	return data, CommandCompleted
+}
+
+// lanChannel resolves the channel of a LAN configuration command
+func (s *Simulator) lanChannel(channel uint8) bool {
+	num, ok := s.channel(channel)
+	return ok && s.channels[num].medium == ChannelMediumLAN
+}
+
+func (s *Simulator) getLANConfig(m *Message) Response {
+	r := &LANConfigRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	if !s.lanChannel(r.Channel) {
+		return ErrInvalidPacket
+	}
+
+	res := &LANConfigResponse{
+		CompletionCode: CommandCompleted,
+		Revision:       0x11,
+	}
+	if r.Channel&0x80 != 0 {
+		// parameter revision only
+		return res
+	}
+
+	if sets, ok := s.lan.sets[r.Param]; ok {
+		if int(r.Set) >= len(sets) {
+			return ErrParamRange
+		}
+		res.Data = sets[r.Set]
+		return res
+	}
+
+	data, ok := s.lan.params[r.Param]
+	if !ok {
+		return ErrLANParamNotSupported
+	}
+	res.Data = data
+	return res
+}
+
+func (s *Simulator) setLANConfig(m *Message) Response {
+	r := &SetLANConfigRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	if !s.lanChannel(r.Channel) {
+		return ErrInvalidPacket
+	}
+
+	if simulatorLANReadOnly[r.Param] {
+		return ErrLANParamReadOnly
+	}
+
+	if sets, ok := s.lan.sets[r.Param]; ok {
+		data, err := simulatorLANSetData(r.Param, r.Data)
+		if err != CommandCompleted {
+			return err
+		}
+		if int(data[0]) >= len(sets) {
+			return ErrParamRange
+		}
+		sets[data[0]] = data
+		return &SetLANConfigResponse{CommandCompleted}
+	}
+
+	data, ok := s.lan.params[r.Param]
+	if !ok {
+		return ErrLANParamNotSupported
+	}
+	if len(r.Data) < len(data) {
+		return ErrShortPacket
+	}
+
+	switch r.Param {
+	case LANParamSetInProgress:
+		if err := simulatorSetInProgress(s.lan.params, r.Data[0]); err != CommandCompleted {
+			return err
+		}
+	default:
+		s.lan.params[r.Param] = append([]uint8(nil), r.Data[:len(data)]...)
+	}
+
+	return &SetLANConfigResponse{CommandCompleted}
+}
@@ -0,0 +1,242 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+// sizes of the simulated PEF tables
+const (
+	simulatorPEFEventFilters  = 8
+	simulatorPEFAlertPolicies = 8
+	simulatorPEFAlertStrings  = 4
+)
+
+// the PEF actions the simulated BMC implements
+const simulatorPEFActions = PEFActionAlert | PEFActionPowerDown | PEFActionReset | PEFActionPowerCycle
+
+// simulated PEF configuration, parameter data by selector
+type simulatorPEF struct {
+	params   map[uint8][]uint8
+	sets     map[uint8]map[uint8][]uint8 // parameters with a set selector, by parameter and set
+	strings  map[uint8][]uint8           // alert strings by selector
+	postpone uint8
+}
+
+// parameters the simulated BMC does not let a client write
+var simulatorPEFReadOnly = map[uint8]bool{
+	PEFParamEventFilterCount: true,
+	PEFParamAlertPolicyCount: true,
+	PEFParamAlertStringCount: true,
+}
+
+func newSimulatorPEF() simulatorPEF {
+	pef := simulatorPEF{
+		params: map[uint8][]uint8{
+			PEFParamSetInProgress:     {0x00},
+			PEFParamStartupDelay:      {60},
+			PEFParamAlertStartupDelay: {60},
+			PEFParamEventFilterCount:  {simulatorPEFEventFilters},
+			PEFParamAlertPolicyCount:  {simulatorPEFAlertPolicies},
+			PEFParamAlertStringCount:  {simulatorPEFAlertStrings},
+		},
+		sets: map[uint8]map[uint8][]uint8{
+			PEFParamEventFilter:    {},
+			PEFParamAlertPolicy:    {},
+			PEFParamAlertStringKey: {},
+		},
+		strings: map[uint8][]uint8{},
+	}
+
+	params := []PEFConfigParam{
+		&PEFControl{Enabled: true, EventMessages: true},
+		PEFActionControl(simulatorPEFActions),
+		&PEFSystemGUID{},
+	}
+	for _, p := range params {
+		pef.params[p.Param()], _ = p.MarshalBinary()
+	}
+
+	for id := uint8(1); id <= simulatorPEFEventFilters; id++ {
+		filter := &PEFEventFilter{ID: id}
+		if id == 1 {
+			// a manufacturer filter powering off on critical temperatures
+			filter = &PEFEventFilter{
+				ID:           id,
+				Enabled:      true,
+				Type:         PEFFilterManufacturer,
+				Action:       PEFActionPowerDown,
+				Severity:     PEFSeverityCritical,
+				GeneratorID:  0xffff,
+				SensorType:   SDR_SENSOR_TYPECODES_TEMPERATURE,
+				SensorNumber: PEFMatchAny,
+				EventTrigger: SENSOR_READTYPE_THREADHOLD,
+				OffsetMask:   0x0200, // upper critical going high
+			}
+		}
+		pef.sets[PEFParamEventFilter][id], _ = filter.MarshalBinary()
+	}
+	for id := uint8(1); id <= simulatorPEFAlertPolicies; id++ {
+		pef.sets[PEFParamAlertPolicy][id], _ = (&PEFAlertPolicy{ID: id}).MarshalBinary()
+	}
+	for id := uint8(0); id < simulatorPEFAlertStrings; id++ {
+		pef.sets[PEFParamAlertStringKey][id], _ = (&PEFAlertStringKey{ID: id}).MarshalBinary()
+		pef.strings[id] = []uint8{}
+	}
+
+	return pef
+}
+
+func (s *Simulator) getPEFCapabilities(*Message) Response {
+	return &PEFCapabilitiesResponse{
+		CompletionCode:   CommandCompleted,
+		Version:          0x51,
+		ActionSupport:    simulatorPEFActions,
+		EventFilterCount: simulatorPEFEventFilters,
+	}
+}
+
+func (s *Simulator) armPEFPostponeTimer(m *Message) Response {
+	r := &ArmPEFPostponeTimerRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	// the countdown is not simulated, it stays at the armed value
+	if r.Timeout != PEFPostponeGet {
+		s.pef.postpone = r.Timeout
+	}
+	return &ArmPEFPostponeTimerResponse{
+		CompletionCode: CommandCompleted,
+		Countdown:      s.pef.postpone,
+	}
+}
+
+func (s *Simulator) getPEFConfig(m *Message) Response {
+	r := &PEFConfigRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	res := &PEFConfigResponse{
+		CompletionCode: CommandCompleted,
+		Revision:       0x11,
+	}
+	if r.Param&0x80 != 0 {
+		// parameter revision only
+		return res
+	}
+
+	switch r.Param {
+	case PEFParamEventFilterData1:
+		filter, ok := s.pef.sets[PEFParamEventFilter][r.Set]
+		if !ok {
+			return ErrParamRange
+		}
+		res.Data = filter[:2]
+	case PEFParamAlertString:
+		text, ok := s.pef.strings[r.Set]
+		if !ok || r.Block == 0 {
+			return ErrParamRange
+		}
+		start := int(r.Block-1) * pefAlertStringBlockSize
+		if start > len(text) {
+			return ErrParamRange
+		}
+		end := start + pefAlertStringBlockSize
+		if end > len(text) {
+			end = len(text)
+		}
+		res.Data = append([]uint8{r.Set, r.Block}, text[start:end]...)
+	default:
+		if sets, ok := s.pef.sets[r.Param]; ok {
+			data, ok := sets[r.Set]
+			if !ok {
+				return ErrParamRange
+			}
+			res.Data = data
+			return res
+		}
+		data, ok := s.pef.params[r.Param]
+		if !ok {
+			return ErrPEFParamNotSupported
+		}
+		res.Data = data
+	}
+
+	return res
+}
+
+func (s *Simulator) setPEFConfig(m *Message) Response {
+	r := &SetPEFConfigRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	if simulatorPEFReadOnly[r.Param] {
+		return ErrPEFParamReadOnly
+	}
+
+	switch r.Param {
+	case PEFParamSetInProgress:
+		if err := simulatorSetInProgress(s.pef.params, r.Data[0]); err != CommandCompleted {
+			return err
+		}
+	case PEFParamEventFilterData1:
+		filter, ok := s.pef.sets[PEFParamEventFilter][r.Data[0]]
+		if !ok {
+			return ErrParamRange
+		}
+		if len(r.Data) < 2 {
+			return ErrShortPacket
+		}
+		filter[1] = r.Data[1]
+	case PEFParamAlertString:
+		if len(r.Data) < 3 {
+			return ErrShortPacket
+		}
+		text, ok := s.pef.strings[r.Data[0]]
+		block := int(r.Data[1])
+		if !ok || block == 0 || len(r.Data) > 2+pefAlertStringBlockSize {
+			return ErrParamRange
+		}
+		start := (block - 1) * pefAlertStringBlockSize
+		if start > len(text) {
+			return ErrParamRange
+		}
+		s.pef.strings[r.Data[0]] = append(text[:start], r.Data[2:]...)
+	default:
+		if sets, ok := s.pef.sets[r.Param]; ok {
+			data, ok := sets[r.Data[0]]
+			if !ok {
+				return ErrParamRange
+			}
+			if len(r.Data) < len(data) {
+				return ErrShortPacket
+			}
+			sets[r.Data[0]] = append([]uint8(nil), r.Data[:len(data)]...)
+			break
+		}
+		data, ok := s.pef.params[r.Param]
+		if !ok {
+			return ErrPEFParamNotSupported
+		}
+		if len(r.Data) < len(data) {
+			return ErrShortPacket
+		}
+		s.pef.params[r.Param] = append([]uint8(nil), r.Data[:len(data)]...)
+	}
+
+	return &SetPEFConfigResponse{CommandCompleted}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package ipmi
 
 import (
+	"encoding"
 	"fmt"
 	//"fmt"
 	"math"
@@ -59,6 +60,11 @@ func (rep *repo) initRepoData() {
 	r1.SensorNumber = 0x04
 	r1.ReadingType = SENSOR_READTYPE_THREADHOLD
 	r1.SetMBExp(63, 0, 0, 0)
+	r1.SensorCap = SENSOR_ACCESS_SETTABLE<<4 | SENSOR_ACCESS_SETTABLE<<2
+	r1.DiscreteReadingMask = 0x3f3f // all thresholds readable and settable
+	r1.L_NR, r1.L_C, r1.L_NC = 1, 5, 10
+	r1.U_NC, r1.U_C, r1.U_NR = 48, 56, 64
+	r1.PositiveHysteresis, r1.NegativeHysteresis = 2, 2
 	rep.addRecord(&sDRRecordAndValue{
 		SDRRecord: r1,
 		value:     2583.0,
@@ -130,17 +136,8 @@ func (rep *repo) getRecordById(id uint16) ([]byte, uint16) {
 
 	record = rep.sdrRepo[index]
 
-	switch record.RecordType() {
-	case SDR_RECORD_TYPE_FULL_SENSOR:
-		r, _ := record.SDRRecord.(*SDRFullSensor)
-		data, _ = r.MarshalBinary()
-		break
-	case SDR_RECORD_TYPE_MC_DEVICE_LOCATOR:
-		r, _ := record.SDRRecord.(*SDRMcDeviceLocator)
-		data, _ = r.MarshalBinary()
-		break
-	default:
-		panic("Unsupport record type")
+	if m, ok := record.SDRRecord.(encoding.BinaryMarshaler); ok {
+		data, _ = m.MarshalBinary()
 	}
 
 	return data, next
@@ -185,17 +182,39 @@ func (s *Simulator) getSDR(m *Message) Response {
 	}
 	rId := request.ReservationID
 	if rep, ok = defaultRepo[rId]; !ok {
-		//TODO return err
-		panic("rId not found")
+		return ErrInvalidResv
+	}
+	if s.sdrMaxRead != 0 && request.ByteToRead > s.sdrMaxRead {
+		return ErrRequestData
 	}
 
 	data, nid := rep.getRecordById(request.RecordID)
+	if data == nil {
+		return ErrNoObj
+	}
+	readData, cc := sdrRecordSlice(data, request)
+	if cc != CommandCompleted {
+		return cc
+	}
 	response := &GetSDRCommandResponse{}
 	response.CompletionCode = CommandCompleted
 	response.NextRecordID = nid
-	response.ReadData = data[request.OffsetIntoRecord : request.OffsetIntoRecord+request.ByteToRead]
+	response.ReadData = readData
 	return response
 }
+
+// sdrRecordSlice returns the part of the record data asked by a Get SDR request
+func sdrRecordSlice(data []byte, request *GetSDRCommandRequest) ([]byte, CompletionCode) {
+	offset := int(request.OffsetIntoRecord)
+	if offset > len(data) {
+		return nil, ErrParamRange
+	}
+	end := offset + int(request.ByteToRead)
+	if request.ByteToRead == sdrReadEntireRecord || end > len(data) {
+		end = len(data)
+	}
+	return data[offset:end], CommandCompleted
+}
 func (s *Simulator) getSensorReading(m *Message) Response {
 	request := &GetSensorReadingRequest{}
 	if err := m.Request(request); err != nil {
@@ -205,33 +224,57 @@ func (s *Simulator) getSensorReading(m *Message) Response {
 	var rep *sDRRecordAndValue = nil
 	for _, value := range defaultRepo {
 		for _, sdrRepo2 := range value.sdrRepo {
-			sdrFullSensor := (sdrRepo2.SDRRecord).(*SDRFullSensor)
-			if sdrFullSensor.SensorNumber == sensorNum {
+			sdrFullSensor, ok := (sdrRepo2.SDRRecord).(*SDRFullSensor)
+			if ok && sdrFullSensor.SensorNumber == sensorNum {
 				rep = sdrRepo2
 				break
 			}
 		}
 	}
 	if rep == nil {
-		return nil
+		return ErrNoObj
 	} else {
 		response := &GetSensorReadingResponse{}
 		value := rep.value
 		if sdrFullSensor2, ok := (rep.SDRRecord).(*SDRFullSensor); ok {
-			sensorReading2 := sdrFullSensor2.CalValue(value)
+			sensorReading2, err := sdrFullSensor2.CalValue(value)
+			if err != nil {
+				return ErrUnspecified
+			}
 			response.SensorReading = sensorReading2
+			response.ForThresDiscreStat = uint8(s.thresholdStatus(sensorNum, sensorReading2))
 		} else {
 			response.SensorReading = uint8(value)
 		}
 		response.CompletionCode = CommandCompleted
 		fmt.Println("rep.avail==", rep.avail)
 		if rep.avail == true {
-			//response.ReadingAvail & 0x20 != 0    (11 10 01 00) 000000
-			response.ReadingAvail = 0x40
+			// event messages and scanning enabled
+			response.ReadingAvail = 0xc0
+			if _, state := s.sensor(sensorNum); state != nil {
+				response.ReadingAvail = state.flags
+			}
 		} else {
-			//response.ReadingAvail & 0x20 = 0 (11 10 01 00) 100000
-			response.ReadingAvail = 0x20
+			// reading unavailable
+			response.ReadingAvail = 0xe0
 		}
 		return response
 	}
 }
+
+// section 35.5, the simulated sensors are linear, the factors do not depend on the reading
+func (s *Simulator) getSensorReadingFactors(m *Message) Response {
+	request := &GetSensorReadingFactorsRequest{}
+	if err := m.Request(request); err != nil {
+		return err
+	}
+	fullSensor := findFullSensor(request.SensorNumber)
+	if fullSensor == nil {
+		return ErrNoObj
+	}
+	return &GetSensorReadingFactorsResponse{
+		CompletionCode: CommandCompleted,
+		NextReading:    request.ReadingByte + 1,
+		SensorFactors:  *fullSensor.Factors(),
+	}
+}
@@ -150,3 +150,23 @@ func TestSimulatorSDR_L_GetSensorReading(t *testing.T) {
 	assert.Equal(t, CommandCompleted, rec.CompletionCode)
 	assert.Equal(t, uint8(0xcf), rec.SensorReading)
 }
+
+func TestSimulatorSDR_L_GetRecordAnyType(t *testing.T) {
+	rep := NewRepo()
+	r1 := NewSDROEMRecord(1, 343, []byte{0x01})
+	r2 := NewSDREntityAssociation(2)
+	rep.addRecord(&sDRRecordAndValue{SDRRecord: r1})
+	rep.addRecord(&sDRRecordAndValue{SDRRecord: r2})
+
+	d1, next1 := rep.getRecordById(1)
+	assert.Equal(t, uint16(2), next1)
+	rec, err := UnmarshalSDRRecord(d1)
+	assert.NoError(t, err)
+	assert.Equal(t, r1, rec)
+
+	d2, next2 := rep.getRecordById(2)
+	assert.Equal(t, uint16(0xffff), next2)
+	rec, err = UnmarshalSDRRecord(d2)
+	assert.NoError(t, err)
+	assert.Equal(t, r2, rec)
+}
@@ -0,0 +1,233 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+// simulated state of a threshold sensor, initialized from its SDR
+type simulatorSensor struct {
+	thresholds SensorThresholdValues
+	readable   ThresholdStatus
+	settable   ThresholdStatus
+	positive   uint8 // positive going hysteresis
+	negative   uint8 // negative going hysteresis
+	flags      uint8 // event messages and scanning enabled
+	assertion  uint16
+	deassert   uint16
+}
+
+// findFullSensor returns the SDR of a full sensor in the repository
+func findFullSensor(sensorNum uint8) *SDRFullSensor {
+	for _, value := range defaultRepo {
+		for _, rec := range value.sdrRepo {
+			if fullSensor, ok := rec.SDRRecord.(*SDRFullSensor); ok && fullSensor.SensorNumber == sensorNum {
+				return fullSensor
+			}
+		}
+	}
+	return nil
+}
+
+// sensor returns the SDR and the state of a full sensor
+func (s *Simulator) sensor(sensorNum uint8) (*SDRFullSensor, *simulatorSensor) {
+	record := findFullSensor(sensorNum)
+	if record == nil {
+		return nil, nil
+	}
+	if s.sensors == nil {
+		s.sensors = map[uint8]*simulatorSensor{}
+	}
+	state, ok := s.sensors[sensorNum]
+	if !ok {
+		state = &simulatorSensor{
+			thresholds: SensorThresholdValues{
+				LowerNonCritical:    record.L_NC,
+				LowerCritical:       record.L_C,
+				LowerNonRecoverable: record.L_NR,
+				UpperNonCritical:    record.U_NC,
+				UpperCritical:       record.U_C,
+				UpperNonRecoverable: record.U_NR,
+			},
+			readable:  record.ReadableThresholds(),
+			settable:  record.SettableThresholds(),
+			positive:  record.PositiveHysteresis,
+			negative:  record.NegativeHysteresis,
+			flags:     0xc0,
+			assertion: record.AssertionEventMask & 0x7fff,
+			deassert:  record.DeassertionEventMask & 0x7fff,
+		}
+		s.sensors[sensorNum] = state
+	}
+	return record, state
+}
+
+// thresholdStatus evaluates the readable thresholds of a sensor against a raw reading
+func (s *Simulator) thresholdStatus(sensorNum uint8, reading uint8) ThresholdStatus {
+	record, state := s.sensor(sensorNum)
+	if record == nil || record.ReadingType != SENSOR_READTYPE_THREADHOLD {
+		return 0
+	}
+	value, err := record.ConvertReading(reading)
+	if err != nil {
+		return 0
+	}
+
+	var status ThresholdStatus
+	for i, threshold := range state.thresholds.values() {
+		bit := ThresholdStatus(1 << uint(i))
+		if state.readable&bit == 0 {
+			continue
+		}
+		limit, err := record.ConvertReading(*threshold)
+		if err != nil {
+			continue
+		}
+		if (bit.IsUpper() && value >= limit) || (bit.IsLower() && value <= limit) {
+			status |= bit
+		}
+	}
+	return status
+}
+
+func (s *Simulator) getSensorThresholds(m *Message) Response {
+	request := &GetSensorThresholdsRequest{}
+	if err := m.Request(request); err != nil {
+		return err
+	}
+	record, state := s.sensor(request.SensorNumber)
+	if record == nil {
+		return ErrNoObj
+	}
+	if record.ThresholdAccess() == SENSOR_ACCESS_NONE {
+		return ErrInvalidObjCommand
+	}
+
+	response := &GetSensorThresholdsResponse{
+		CompletionCode: CommandCompleted,
+		Readable:       state.readable,
+	}
+	raw := response.SensorThresholdValues.values()
+	for i, threshold := range state.thresholds.values() {
+		if state.readable&(1<<uint(i)) != 0 {
+			*raw[i] = *threshold
+		}
+	}
+	return response
+}
+
+func (s *Simulator) setSensorThresholds(m *Message) Response {
+	request := &SetSensorThresholdsRequest{}
+	if err := m.Request(request); err != nil {
+		return err
+	}
+	record, state := s.sensor(request.SensorNumber)
+	if record == nil {
+		return ErrNoObj
+	}
+	if record.ThresholdAccess() != SENSOR_ACCESS_SETTABLE {
+		return ErrInvalidObjCommand
+	}
+	if request.Mask&^state.settable != 0 {
+		return ErrInvalidPacket
+	}
+
+	raw := request.SensorThresholdValues.values()
+	for i, threshold := range state.thresholds.values() {
+		if request.Mask&(1<<uint(i)) != 0 {
+			*threshold = *raw[i]
+		}
+	}
+	return &SetSensorThresholdsResponse{CommandCompleted}
+}
+
+func (s *Simulator) getSensorHysteresis(m *Message) Response {
+	request := &GetSensorHysteresisRequest{}
+	if err := m.Request(request); err != nil {
+		return err
+	}
+	record, state := s.sensor(request.SensorNumber)
+	if record == nil {
+		return ErrNoObj
+	}
+	switch record.HysteresisAccess() {
+	case SENSOR_ACCESS_READABLE, SENSOR_ACCESS_SETTABLE:
+	default:
+		return ErrInvalidObjCommand
+	}
+	return &GetSensorHysteresisResponse{
+		CompletionCode:     CommandCompleted,
+		PositiveHysteresis: state.positive,
+		NegativeHysteresis: state.negative,
+	}
+}
+
+func (s *Simulator) setSensorHysteresis(m *Message) Response {
+	request := &SetSensorHysteresisRequest{}
+	if err := m.Request(request); err != nil {
+		return err
+	}
+	record, state := s.sensor(request.SensorNumber)
+	if record == nil {
+		return ErrNoObj
+	}
+	if record.HysteresisAccess() != SENSOR_ACCESS_SETTABLE {
+		return ErrInvalidObjCommand
+	}
+	state.positive = request.PositiveHysteresis
+	state.negative = request.NegativeHysteresis
+	return &SetSensorHysteresisResponse{CommandCompleted}
+}
+
+func (s *Simulator) getSensorEventEnable(m *Message) Response {
+	request := &GetSensorEventEnableRequest{}
+	if err := m.Request(request); err != nil {
+		return err
+	}
+	record, state := s.sensor(request.SensorNumber)
+	if record == nil {
+		return ErrNoObj
+	}
+	return &GetSensorEventEnableResponse{
+		CompletionCode:  CommandCompleted,
+		Flags:           state.flags,
+		AssertionMask:   state.assertion,
+		DeassertionMask: state.deassert,
+	}
+}
+
+func (s *Simulator) setSensorEventEnable(m *Message) Response {
+	request := &SetSensorEventEnableRequest{}
+	if err := m.Request(request); err != nil {
+		return err
+	}
+	record, state := s.sensor(request.SensorNumber)
+	if record == nil {
+		return ErrNoObj
+	}
+
+	state.flags = request.Flags & 0xc0
+	switch request.Flags & 0x30 {
+	case SensorEventEnableSelected:
+		state.assertion |= request.AssertionMask & 0x7fff
+		state.deassert |= request.DeassertionMask & 0x7fff
+	case SensorEventDisableSelected:
+		state.assertion &^= request.AssertionMask
+		state.deassert &^= request.DeassertionMask
+	case SensorEventEnableKeep:
+	default:
+		return ErrInvalidPacket
+	}
+	return &SetSensorEventEnableResponse{CommandCompleted}
+}
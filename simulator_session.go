@@ -0,0 +1,264 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"bytes"
+	"hash/adler32"
+	"net"
+	"sort"
+)
+
+// number of sessions the simulated BMC can have active at once
+const simulatorMaxSessions = 4
+
+// simulated session, from Get Session Challenge until Close Session
+type simulatorSession struct {
+	username string
+	userID   uint8
+	handle   uint8 // 0 until the session is activated
+	priv     uint8
+	addr     net.Addr
+}
+
+// activeSessions is the count of activated sessions
+func (s *Simulator) activeSessions() uint8 {
+	n := uint8(0)
+	for _, session := range s.sessions {
+		if session.handle != 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// sessionHandle returns the lowest handle not in use, 0 if all are in use
+func (s *Simulator) sessionHandle() uint8 {
+	used := map[uint8]bool{}
+	for _, session := range s.sessions {
+		used[session.handle] = true
+	}
+	for handle := uint8(1); handle <= simulatorMaxSessions; handle++ {
+		if !used[handle] {
+			return handle
+		}
+	}
+	return 0
+}
+
+// sessionByHandle returns the ID of the active session with the given handle
+func (s *Simulator) sessionByHandle(handle uint8) (uint32, bool) {
+	for id, session := range s.sessions {
+		if handle != 0 && session.handle == handle {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+func (s *Simulator) sessionChallenge(m *Message) Response {
+	// Convert username to a uint32 and use as the SessionID.
+	// The SessionID will be propagated such that all requests
+	// for this session include the ID, which can be used to
+	// dispatch requests. Concurrent sessions of the same user
+	// get the next free ID.
+	username := bytes.TrimRight(m.Data[1:], "\000")
+	userID, user := s.userByName(string(username))
+	if user == nil {
+		if len(username) == 0 {
+			return ErrSessionNullUserDisable
+		}
+		return ErrSessionInvalidUser
+	}
+
+	hash := adler32.New()
+	_, err := hash.Write(username)
+	if err != nil {
+		panic(err)
+	}
+	id := hash.Sum32()
+	for _, ok := s.sessions[id]; ok || id == 0; _, ok = s.sessions[id] {
+		id++
+	}
+
+	s.sessions[id] = &simulatorSession{
+		username: string(username),
+		userID:   userID,
+	}
+
+	return &SessionChallengeResponse{
+		CompletionCode:     CommandCompleted,
+		TemporarySessionID: id,
+	}
+}
+
+func (s *Simulator) sessionActivate(m *Message) Response {
+	r := &ActivateSessionRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	session, ok := s.sessions[m.SessionID]
+	if !ok {
+		return ErrInvalidPacket
+	}
+	_, user := s.userByName(session.username)
+	if user == nil || !user.authenticate(m) {
+		return ErrInvalidPacket
+	}
+	limit := s.sessionPrivLimit(user)
+	if limit == PrivLevelNoAccess || r.PrivLevel > limit {
+		return ErrSessionPrivLimit
+	}
+
+	if session.handle == 0 {
+		session.handle = s.sessionHandle()
+		if session.handle == 0 {
+			return ErrSessionNoSlot
+		}
+	}
+	// sessions start at user level, or below for a callback limit
+	session.priv = PrivLevelUser
+	if limit < PrivLevelUser {
+		session.priv = limit
+	}
+	session.addr = m.RemoteAddr
+
+	return &ActivateSessionResponse{
+		CompletionCode: CommandCompleted,
+		AuthType:       m.AuthType,
+		SessionID:      m.SessionID,
+		InboundSeq:     m.Sequence,
+		MaxPriv:        limit,
+	}
+}
+
+func (s *Simulator) sessionPrivilege(m *Message) Response {
+	r := &SessionPrivilegeLevelRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	session, ok := s.sessions[m.SessionID]
+	if !ok {
+		return ErrInvalidPacket
+	}
+	_, user := s.userByName(session.username)
+	if user == nil {
+		return ErrInvalidPacket
+	}
+	if r.PrivLevel > s.sessionPrivLimit(user) {
+		return ErrSessionPrivExceeded
+	}
+	if r.PrivLevel != 0 {
+		// 0 asks for the present level
+		session.priv = r.PrivLevel
+	}
+
+	return &SessionPrivilegeLevelResponse{
+		CompletionCode:    CommandCompleted,
+		NewPrivilegeLevel: session.priv,
+	}
+}
+
+func (s *Simulator) sessionClose(m *Message) Response {
+	r := &CloseSessionRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	id := r.SessionID
+	if id == 0 {
+		var ok bool
+		if id, ok = s.sessionByHandle(r.Handle); !ok {
+			return ErrSessionInvalidHandle
+		}
+	} else if _, ok := s.sessions[id]; !ok {
+		return ErrSessionInvalidID
+	}
+
+	if id != m.SessionID {
+		// closing another session takes an administrator
+		session, ok := s.sessions[m.SessionID]
+		if !ok || session.priv < PrivLevelAdmin {
+			return ErrPrivLevel
+		}
+	}
+
+	delete(s.sessions, id)
+
+	return &CloseSessionResponse{CommandCompleted}
+}
+
+func (s *Simulator) getSessionInfo(m *Message) Response {
+	r := &SessionInfoRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	res := &SessionInfoResponse{
+		CompletionCode: CommandCompleted,
+		MaxSessions:    simulatorMaxSessions,
+		ActiveSessions: s.activeSessions(),
+	}
+
+	var session *simulatorSession
+	switch r.Index {
+	case SessionIndexCurrent:
+		session = s.sessions[m.SessionID]
+	case SessionIndexByHandle:
+		if id, ok := s.sessionByHandle(r.Handle); ok {
+			session = s.sessions[id]
+		}
+	case SessionIndexByID:
+		session = s.sessions[r.ID]
+	default:
+		if r.Index > simulatorMaxSessions {
+			return ErrInvalidPacket
+		}
+		// the Nth active session, in the order of the handles
+		var active []*simulatorSession
+		for _, session := range s.sessions {
+			if session.handle != 0 {
+				active = append(active, session)
+			}
+		}
+		sort.Slice(active, func(i, j int) bool {
+			return active[i].handle < active[j].handle
+		})
+		if int(r.Index) <= len(active) {
+			session = active[r.Index-1]
+		}
+	}
+
+	if session == nil || session.handle == 0 {
+		return res
+	}
+
+	res.Handle = session.handle
+	res.UserID = session.userID
+	res.Privilege = session.priv
+	res.Protocol = SessionProtocolIPMI15
+	res.Channel = simulatorLANChannel
+	res.RemoteIP = net.IPv4zero
+	if addr, ok := session.addr.(*net.UDPAddr); ok {
+		res.RemoteIP = addr.IP
+		res.RemotePort = uint16(addr.Port)
+	}
+
+	return res
+}
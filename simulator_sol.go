@@ -0,0 +1,109 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import "time"
+
+// simulated SOL configuration of the LAN channel, parameter data by selector
+type simulatorSOL struct {
+	params map[uint8][]uint8
+}
+
+func newSimulatorSOL() simulatorSOL {
+	sol := simulatorSOL{
+		params: map[uint8][]uint8{
+			SOLParamSetInProgress: {0x00},
+		},
+	}
+
+	params := []SOLConfigParam{
+		SOLEnable(true),
+		&SOLAuth{ForceAuthentication: true, Privilege: PrivLevelUser},
+		&SOLAccumulate{Interval: 60 * time.Millisecond, Threshold: 96},
+		&SOLRetry{Count: 7, Interval: 500 * time.Millisecond},
+		&SOLNonVolatileBitRate{SOLBitRate9600},
+		&SOLVolatileBitRate{SOLBitRate9600},
+		SOLPayloadChannel(simulatorLANChannel),
+		SOLPayloadPort(623),
+	}
+	for _, p := range params {
+		sol.params[p.Param()], _ = p.MarshalBinary()
+	}
+
+	return sol
+}
+
+func (s *Simulator) getSOLConfig(m *Message) Response {
+	r := &SOLConfigRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	if !s.lanChannel(r.Channel) {
+		return ErrInvalidPacket
+	}
+
+	res := &SOLConfigResponse{
+		CompletionCode: CommandCompleted,
+		Revision:       0x11,
+	}
+	if r.Channel&0x80 != 0 {
+		// parameter revision only
+		return res
+	}
+
+	data, ok := s.sol.params[r.Param]
+	if !ok {
+		return ErrSOLParamNotSupported
+	}
+	res.Data = data
+	return res
+}
+
+func (s *Simulator) setSOLConfig(m *Message) Response {
+	r := &SetSOLConfigRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	if !s.lanChannel(r.Channel) {
+		return ErrInvalidPacket
+	}
+
+	if r.Param == SOLParamPayloadChannel {
+		return ErrSOLParamReadOnly
+	}
+
+	data, ok := s.sol.params[r.Param]
+	if !ok {
+		return ErrSOLParamNotSupported
+	}
+	if len(r.Data) < len(data) {
+		return ErrShortPacket
+	}
+
+	if r.Param == SOLParamSetInProgress {
+		if err := simulatorSetInProgress(s.sol.params, r.Data[0]); err != CommandCompleted {
+			return err
+		}
+		return &SetSOLConfigResponse{CommandCompleted}
+	}
+
+	s.sol.params[r.Param] = append([]uint8(nil), r.Data[:len(data)]...)
+
+	return &SetSOLConfigResponse{CommandCompleted}
+}
@@ -0,0 +1,298 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"bytes"
+	"crypto/md5"
+)
+
+const (
+	simulatorMaxUsers   = 10
+	simulatorLANChannel = 0x01
+)
+
+// simulated BMC user account
+type simulatorUser struct {
+	name     string
+	password []byte // 16 or 20 bytes, 0 padded
+	status   uint8
+	access   map[uint8]uint8  // access bits by channel, per UserAccessResponse.Access
+	payloads map[uint8]uint32 // enabled payloads by channel
+}
+
+// newSimulatorUsers returns the user table indexed by user ID, with only the null user enabled
+func newSimulatorUsers() []*simulatorUser {
+	users := make([]*simulatorUser, simulatorMaxUsers+1)
+	for id := 1; id <= simulatorMaxUsers; id++ {
+		users[id] = &simulatorUser{
+			password: make([]byte, UserPassword16),
+			status:   UserStatusDisabled,
+			access:   map[uint8]uint8{},
+			payloads: map[uint8]uint32{},
+		}
+	}
+	null := users[1]
+	null.status = UserStatusEnabled
+	null.access[simulatorLANChannel] = 0x10 | PrivLevelAdmin
+	null.payloads[simulatorLANChannel] = payloadBit(PayloadSOL)
+	return users
+}
+
+// SetUser adds an enabled user with IPMI messaging on the LAN channel at the given privilege,
+// ids outside 1 to 10 are ignored
+func (s *Simulator) SetUser(id uint8, name, password string, priv uint8) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id == 0 || int(id) >= len(s.users) {
+		return
+	}
+	user := s.users[id]
+	user.name = name
+	user.password = make([]byte, UserPassword16)
+	if len(password) > UserPassword16 {
+		user.password = make([]byte, UserPassword20)
+	}
+	copy(user.password, password)
+	user.status = UserStatusEnabled
+	user.access[simulatorLANChannel] = 0x10 | priv&0x0f
+}
+
+// user returns the user with the given ID, nil if the ID is out of range
+func (s *Simulator) user(id uint8) *simulatorUser {
+	id &= 0x3f
+	if id == 0 || int(id) >= len(s.users) {
+		return nil
+	}
+	return s.users[id]
+}
+
+// userByName returns the ID of the enabled user with the given name and the user
+func (s *Simulator) userByName(name string) (uint8, *simulatorUser) {
+	for id, user := range s.users[1:] {
+		if user.name == name && user.status == UserStatusEnabled {
+			return uint8(id + 1), user
+		}
+	}
+	return 0, nil
+}
+
+// privLimit returns the privilege limit of the user on the LAN channel
+func (u *simulatorUser) privLimit() uint8 {
+	access, ok := u.access[simulatorLANChannel]
+	if !ok || access&0x10 == 0 {
+		return PrivLevelNoAccess
+	}
+	return access & 0x0f
+}
+
+// authenticate checks the auth code of a session message per section 22.17.1
+func (u *simulatorUser) authenticate(m *Message) bool {
+	if len(u.password) != UserPassword16 {
+		// 20 byte passwords are for RMCP+ sessions only
+		return false
+	}
+
+	var password [UserPassword16]byte
+	copy(password[:], u.password)
+
+	switch m.AuthType {
+	case AuthTypeNone:
+		return password == [UserPassword16]byte{}
+	case AuthTypePassword:
+		return password == m.AuthCode
+	case AuthTypeMD5:
+		// the message is signed from the IPMI header RsAddr to the payload checksum
+		data := []byte{m.RsAddr, m.NetFnRsLUN, m.Checksum, m.RqAddr, m.RqSeq, uint8(m.Command)}
+		data = append(data, m.Data...)
+		data = append(data, m.payloadChecksum(m.Data))
+
+		h := md5.New()
+		binaryWrite(h, password)
+		binaryWrite(h, m.SessionID)
+		binaryWrite(h, data)
+		binaryWrite(h, m.Sequence)
+		binaryWrite(h, password)
+		return bytes.Equal(h.Sum(nil), m.AuthCode[:])
+	default:
+		return false
+	}
+}
+
+func (s *Simulator) getUserAccess(m *Message) Response {
+	r := &UserAccessRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	ch, ok := s.channel(r.Channel)
+	user := s.user(r.UserID)
+	if !ok || user == nil {
+		return ErrParamRange
+	}
+
+	enabled := uint8(0)
+	for _, u := range s.users[1:] {
+		if u.status == UserStatusEnabled {
+			enabled++
+		}
+	}
+	access, ok := user.access[ch]
+	if !ok {
+		access = PrivLevelNoAccess
+	}
+	return &UserAccessResponse{
+		CompletionCode: CommandCompleted,
+		MaxUsers:       simulatorMaxUsers,
+		EnabledUsers:   user.status<<6 | enabled,
+		FixedNames:     1, // the null user
+		Access:         access,
+	}
+}
+
+func (s *Simulator) setUserAccess(m *Message) Response {
+	r := &SetUserAccessRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	ch, ok := s.channel(r.Channel)
+	user := s.user(r.UserID)
+	if !ok || user == nil {
+		return ErrParamRange
+	}
+
+	priv := r.Privilege & 0x0f
+	if priv > PrivLevelOEM && priv != PrivLevelNoAccess {
+		return ErrInvalidPacket
+	}
+	access, ok := user.access[ch]
+	if !ok {
+		access = PrivLevelNoAccess
+	}
+	if r.Channel&0x80 != 0 {
+		access = r.Channel & 0x70
+	}
+	user.access[ch] = access&0x70 | priv
+	return &SetUserAccessResponse{CommandCompleted}
+}
+
+func (s *Simulator) getUserName(m *Message) Response {
+	r := &UserNameRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	user := s.user(r.UserID)
+	if user == nil {
+		return ErrParamRange
+	}
+	res := &UserNameResponse{CompletionCode: CommandCompleted}
+	copy(res.Name[:], user.name)
+	return res
+}
+
+func (s *Simulator) setUserName(m *Message) Response {
+	r := &SetUserNameRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	id := r.UserID & 0x3f
+	user := s.user(id)
+	if user == nil {
+		return ErrParamRange
+	}
+	if id == 1 {
+		// the null user name is fixed
+		return ErrInvalidPacket
+	}
+	name := userName(r.Name)
+	for i, u := range s.users[1:] {
+		if name != "" && u.name == name && uint8(i+1) != id {
+			return ErrInvalidPacket
+		}
+	}
+	user.name = name
+	return &SetUserNameResponse{CommandCompleted}
+}
+
+func (s *Simulator) setUserPassword(m *Message) Response {
+	r := &SetUserPasswordRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	user := s.user(r.ID())
+	if user == nil {
+		return ErrParamRange
+	}
+
+	switch r.Operation {
+	case UserPasswordDisable:
+		user.status = UserStatusDisabled
+	case UserPasswordEnable:
+		user.status = UserStatusEnabled
+	case UserPasswordSet:
+		user.password = append([]byte{}, r.Password...)
+	case UserPasswordTest:
+		if len(r.Password) != len(user.password) {
+			return ErrPasswordSize
+		}
+		if !bytes.Equal(r.Password, user.password) {
+			return ErrPasswordMismatch
+		}
+	}
+	return &SetUserPasswordResponse{CommandCompleted}
+}
+
+func (s *Simulator) getUserPayloadAccess(m *Message) Response {
+	r := &UserPayloadAccessRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	ch, ok := s.channel(r.Channel)
+	user := s.user(r.UserID)
+	if !ok || user == nil {
+		return ErrParamRange
+	}
+	return &UserPayloadAccessResponse{
+		CompletionCode: CommandCompleted,
+		Payloads:       user.payloads[ch],
+	}
+}
+
+func (s *Simulator) setUserPayloadAccess(m *Message) Response {
+	r := &SetUserPayloadAccessRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	ch, ok := s.channel(r.Channel)
+	user := s.user(r.UserID)
+	if !ok || user == nil {
+		return ErrParamRange
+	}
+	if r.UserID&0xc0 == 0 {
+		user.payloads[ch] |= r.Payloads
+	} else {
+		user.payloads[ch] &^= r.Payloads
+	}
+	return &SetUserPayloadAccessResponse{CommandCompleted}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import "time"
+
+// simulated BMC watchdog timer
+type simulatorWatchdog struct {
+	initialized bool
+	timerUse    uint8
+	actions     uint8
+	preTimeout  uint8
+	flags       uint8  // expiration flags
+	initial     uint16 // countdown in 100ms
+	present     uint16 // countdown when stopped
+	expires     time.Time
+	timer       *time.Timer // nil when stopped
+}
+
+func (w *simulatorWatchdog) running() bool {
+	return w.timer != nil
+}
+
+func (w *simulatorWatchdog) stop() {
+	if w.timer != nil {
+		w.present = w.countdown()
+		w.timer.Stop()
+		w.timer = nil
+	}
+}
+
+// countdown returns the present countdown
+func (w *simulatorWatchdog) countdown() uint16 {
+	if !w.running() {
+		return w.present
+	}
+	left := time.Until(w.expires)
+	if left < 0 {
+		return 0
+	}
+	return uint16((left + WatchdogCountdownUnit - 1) / WatchdogCountdownUnit)
+}
+
+// start (re)starts the countdown from the initial countdown
+func (s *Simulator) startWatchdog() {
+	w := &s.watchdog
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	d := time.Duration(w.initial) * WatchdogCountdownUnit
+	w.expires = time.Now().Add(d)
+	var timer *time.Timer
+	timer = time.AfterFunc(d, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		// ignore a timer stopped or restarted while waiting for the lock
+		if w.timer == timer {
+			s.watchdogExpired()
+		}
+	})
+	w.timer = timer
+}
+
+// watchdogExpired takes the timeout action on the simulated chassis, called with s.mu held
+func (s *Simulator) watchdogExpired() {
+	w := &s.watchdog
+	w.timer = nil
+	w.present = 0
+	w.flags |= WatchdogExpirationFlag(WatchdogTimerUse(w.timerUse))
+
+	c := &s.chassis
+	c.update()
+	switch WatchdogAction(w.actions & 0x07) {
+	case WatchdogActionHardReset:
+		if c.isPowerOn() {
+			c.restartCause = RestartCauseWatchdog
+		}
+	case WatchdogActionPowerDown:
+		c.pending = nil
+		c.setPower(false, 0)
+	case WatchdogActionPowerCycle:
+		if c.isPowerOn() {
+			c.pending = nil
+			c.setPower(false, 0)
+			c.schedule(time.Duration(c.cycleInterval)*time.Second+c.delays.PowerOn, true, RestartCauseWatchdog)
+		}
+	}
+}
+
+func (s *Simulator) resetWatchdog(*Message) Response {
+	if !s.watchdog.initialized {
+		return ErrWatchdogNotInitialized
+	}
+	s.startWatchdog()
+	return &ResetWatchdogResponse{CommandCompleted}
+}
+
+func (s *Simulator) setWatchdog(m *Message) Response {
+	r := &SetWatchdogRequest{}
+	if err := m.Request(r); err != nil {
+		return err
+	}
+
+	use := WatchdogTimerUse(r.TimerUse & 0x07)
+	if use == WatchdogUseReserved || use > WatchdogUseOEM || WatchdogAction(r.TimerActions&0x07) > WatchdogActionPowerCycle {
+		return ErrInvalidPacket
+	}
+
+	w := &s.watchdog
+	keep := r.TimerUse&WatchdogDontStop != 0 && w.running()
+	w.stop()
+	w.initialized = true
+	w.timerUse = r.TimerUse &^ WatchdogDontStop
+	w.actions = r.TimerActions
+	w.preTimeout = r.PreTimeoutInterval
+	w.flags &^= r.ExpirationFlags
+	w.initial = r.InitialCountdown
+	w.present = r.InitialCountdown
+	if keep {
+		s.startWatchdog()
+	}
+	return &SetWatchdogResponse{CommandCompleted}
+}
+
+func (s *Simulator) getWatchdog(*Message) Response {
+	w := &s.watchdog
+	use := w.timerUse
+	if w.running() {
+		use |= WatchdogDontStop
+	}
+	return &GetWatchdogResponse{
+		CompletionCode:     CommandCompleted,
+		TimerUse:           use,
+		TimerActions:       w.actions,
+		PreTimeoutInterval: w.preTimeout,
+		ExpirationFlags:    w.flags,
+		InitialCountdown:   w.initial,
+		PresentCountdown:   w.countdown(),
+	}
+}
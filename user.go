@@ -0,0 +1,239 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+// User enable status per section 22.27
+const (
+	UserStatusUnspecified = 0x0
+	UserStatusEnabled     = 0x1
+	UserStatusDisabled    = 0x2
+)
+
+// Set User Password operations per section 22.30
+const (
+	UserPasswordDisable = 0x0
+	UserPasswordEnable  = 0x1
+	UserPasswordSet     = 0x2
+	UserPasswordTest    = 0x3
+)
+
+// Password sizes, 20 byte passwords are only usable by IPMI v2.0 RMCP+ sessions
+const (
+	UserPassword16 = 16
+	UserPassword20 = 20
+)
+
+// UserNameSize is the size of a user name
+const UserNameSize = 16
+
+// Standard payload types per section 13.27.3, used in the payload access bitmasks
+const (
+	PayloadIPMI = 0x00
+	PayloadSOL  = 0x01
+	PayloadOEM  = 0x02
+)
+
+// Command specific completion codes
+const (
+	ErrPasswordMismatch = CompletionCode(0x80) // Set User Password test: wrong password
+	ErrPasswordSize     = CompletionCode(0x81) // Set User Password test: wrong password size
+
+	ErrSessionInvalidUser     = CompletionCode(0x81) // Get Session Challenge: invalid user name
+	ErrSessionNullUserDisable = CompletionCode(0x82) // Get Session Challenge: null user name not enabled
+	ErrSessionPrivLimit       = CompletionCode(0x86) // Activate Session: privilege exceeds the user or channel limit
+	ErrSessionPrivExceeded    = CompletionCode(0x81) // Set Session Privilege Level: privilege exceeds the user or channel limit
+)
+
+// SetUserAccessRequest per section 22.26
+type SetUserAccessRequest struct {
+	Channel   uint8 // [7] change the bits below, [6] callback only, [5] link auth, [4] IPMI messaging, [3:0] channel
+	UserID    uint8
+	Privilege uint8 // [3:0] user privilege limit
+}
+
+// SetUserAccessResponse per section 22.26
+type SetUserAccessResponse struct {
+	CompletionCode
+}
+
+// UserAccessRequest per section 22.27
+type UserAccessRequest struct {
+	Channel uint8
+	UserID  uint8
+}
+
+// UserAccessResponse per section 22.27
+type UserAccessResponse struct {
+	CompletionCode
+	MaxUsers     uint8
+	EnabledUsers uint8 // [7:6] the user enable status, [5:0] count of enabled users
+	FixedNames   uint8
+	Access       uint8 // [6] callback only, [5] link auth, [4] IPMI messaging, [3:0] privilege limit
+}
+
+// SetUserNameRequest per section 22.28
+type SetUserNameRequest struct {
+	UserID uint8
+	Name   [UserNameSize]uint8
+}
+
+// SetUserNameResponse per section 22.28
+type SetUserNameResponse struct {
+	CompletionCode
+}
+
+// UserNameRequest per section 22.29
+type UserNameRequest struct {
+	UserID uint8
+}
+
+// UserNameResponse per section 22.29
+type UserNameResponse struct {
+	CompletionCode
+	Name [UserNameSize]uint8
+}
+
+// SetUserPasswordRequest per section 22.30
+type SetUserPasswordRequest struct {
+	UserID    uint8 // [7] 20 byte password, [5:0] user ID
+	Operation uint8
+	Password  []uint8 // 16 or 20 bytes for the set and test operations
+}
+
+// SetUserPasswordResponse per section 22.30
+type SetUserPasswordResponse struct {
+	CompletionCode
+}
+
+// SetUserPayloadAccessRequest per section 24.6
+type SetUserPayloadAccessRequest struct {
+	Channel  uint8
+	UserID   uint8  // [7:6] 0 enables, 1 disables the payloads, [5:0] user ID
+	Payloads uint32 // [15:0] standard payloads, [23:16] OEM payloads 0x20 to 0x27
+}
+
+// SetUserPayloadAccessResponse per section 24.6
+type SetUserPayloadAccessResponse struct {
+	CompletionCode
+}
+
+// UserPayloadAccessRequest per section 24.7
+type UserPayloadAccessRequest struct {
+	Channel uint8
+	UserID  uint8
+}
+
+// UserPayloadAccessResponse per section 24.7
+type UserPayloadAccessResponse struct {
+	CompletionCode
+	Payloads uint32
+}
+
+// MarshalBinary implementation to handle the variable length password
+func (r *SetUserPasswordRequest) MarshalBinary() ([]byte, error) {
+	return append([]byte{r.UserID, r.Operation}, r.Password...), nil
+}
+
+// UnmarshalBinary implementation to handle the variable length password
+func (r *SetUserPasswordRequest) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 2 {
+		return ErrShortPacket
+	}
+	r.UserID = buf[0]
+	r.Operation = buf[1] & 0x03
+	r.Password = nil
+	if r.Operation == UserPasswordSet || r.Operation == UserPasswordTest {
+		size := UserPassword16
+		if r.UserID&0x80 != 0 {
+			size = UserPassword20
+		}
+		if len(buf) < 2+size {
+			return ErrShortPacket
+		}
+		r.Password = buf[2 : 2+size]
+	}
+	return nil
+}
+
+// ID is the user ID
+func (r *SetUserPasswordRequest) ID() uint8 {
+	return r.UserID & 0x3f
+}
+
+// Status is the user enable status, one of the UserStatus* values
+func (r *UserAccessResponse) Status() uint8 {
+	return r.EnabledUsers >> 6
+}
+
+// Enabled is the count of enabled users
+func (r *UserAccessResponse) Enabled() uint8 {
+	return r.EnabledUsers & 0x3f
+}
+
+// UserAccess is the access of a user to a channel
+type UserAccess struct {
+	Privilege     uint8 // privilege limit, PrivLevelNoAccess for no access
+	IPMIMessaging bool
+	LinkAuth      bool
+	CallbackOnly  bool
+}
+
+// UserAccess decodes the channel access of the user
+func (r *UserAccessResponse) UserAccess() UserAccess {
+	return UserAccess{
+		Privilege:     r.Access & 0x0f,
+		IPMIMessaging: r.Access&0x10 != 0,
+		LinkAuth:      r.Access&0x20 != 0,
+		CallbackOnly:  r.Access&0x40 != 0,
+	}
+}
+
+func (a *UserAccess) bits() uint8 {
+	b := a.Privilege & 0x0f
+	if a.IPMIMessaging {
+		b |= 0x10
+	}
+	if a.LinkAuth {
+		b |= 0x20
+	}
+	if a.CallbackOnly {
+		b |= 0x40
+	}
+	return b
+}
+
+// HasPayload tells whether the payload type is enabled
+func (r *UserPayloadAccessResponse) HasPayload(payload uint8) bool {
+	return r.Payloads&payloadBit(payload) != 0
+}
+
+// payloadBit maps a standard payload type, or an OEM payload type 0x20 to 0x27, to its bit
+func payloadBit(payload uint8) uint32 {
+	if payload >= 0x20 {
+		return 1 << (16 + (payload-0x20)&0x07)
+	}
+	return 1 << (payload & 0x0f)
+}
+
+// userName decodes a 0 padded user name
+func userName(name [UserNameSize]uint8) string {
+	n := 0
+	for n < len(name) && name[n] != 0 {
+		n++
+	}
+	return string(name[:n])
+}
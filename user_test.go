@@ -0,0 +1,68 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetUserPasswordRequest(t *testing.T) {
+	req := &Request{
+		NetworkFunctionApp,
+		CommandSetUserPassword,
+		&SetUserPasswordRequest{
+			UserID:    0x83,
+			Operation: UserPasswordTest,
+			Password:  make([]uint8, UserPassword20),
+		},
+	}
+	raw := requestToStrings(req)
+	assert.Len(t, raw, 2+2+UserPassword20)
+	assert.Equal(t, []string{"0x06", "0x47", "0x83", "0x03"}, raw[:4])
+
+	r := &SetUserPasswordRequest{}
+	assert.NoError(t, r.UnmarshalBinary(append([]byte{0x83, 0x03}, make([]byte, UserPassword20)...)))
+	assert.Equal(t, uint8(3), r.ID())
+	assert.Len(t, r.Password, UserPassword20)
+	assert.Equal(t, ErrShortPacket, r.UnmarshalBinary(append([]byte{0x83, 0x02}, make([]byte, UserPassword16)...)))
+	assert.NoError(t, r.UnmarshalBinary([]byte{0x03, 0x01}))
+	assert.Nil(t, r.Password)
+}
+
+func TestUserAccessParse(t *testing.T) {
+	res := &UserAccessResponse{}
+	err := responseFromString("0a 43 01 34", res)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(10), res.MaxUsers)
+	assert.Equal(t, uint8(UserStatusEnabled), res.Status())
+	assert.Equal(t, uint8(3), res.Enabled())
+	assert.Equal(t, UserAccess{Privilege: PrivLevelAdmin, IPMIMessaging: true, LinkAuth: true}, res.UserAccess())
+
+	access := res.UserAccess()
+	assert.Equal(t, uint8(0x34), access.bits())
+}
+
+func TestUserPayloadAccessParse(t *testing.T) {
+	res := &UserPayloadAccessResponse{}
+	err := responseFromString("02 00 01 00", res)
+	assert.NoError(t, err)
+	assert.True(t, res.HasPayload(PayloadSOL))
+	assert.False(t, res.HasPayload(PayloadOEM))
+	assert.True(t, res.HasPayload(0x20))
+}
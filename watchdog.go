@@ -0,0 +1,185 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"fmt"
+	"time"
+)
+
+// WatchdogTimerUse tells which phase the watchdog timer guards, per section 27.6
+type WatchdogTimerUse uint8
+
+// WatchdogAction is the action taken when the watchdog timer expires
+type WatchdogAction uint8
+
+// WatchdogPreTimeout is the interrupt raised before the watchdog timer expires
+type WatchdogPreTimeout uint8
+
+const (
+	WatchdogUseReserved = WatchdogTimerUse(0x0)
+	WatchdogUseBIOSFRB2 = WatchdogTimerUse(0x1)
+	WatchdogUseBIOSPOST = WatchdogTimerUse(0x2)
+	WatchdogUseOSLoad   = WatchdogTimerUse(0x3)
+	WatchdogUseSMSOS    = WatchdogTimerUse(0x4)
+	WatchdogUseOEM      = WatchdogTimerUse(0x5)
+
+	WatchdogActionNone       = WatchdogAction(0x0)
+	WatchdogActionHardReset  = WatchdogAction(0x1)
+	WatchdogActionPowerDown  = WatchdogAction(0x2)
+	WatchdogActionPowerCycle = WatchdogAction(0x3)
+
+	WatchdogPreTimeoutNone      = WatchdogPreTimeout(0x0)
+	WatchdogPreTimeoutSMI       = WatchdogPreTimeout(0x1)
+	WatchdogPreTimeoutNMI       = WatchdogPreTimeout(0x2)
+	WatchdogPreTimeoutMessaging = WatchdogPreTimeout(0x3)
+
+	// timer use byte flags
+	WatchdogDontLog  = 0x80
+	WatchdogDontStop = 0x40 // on Set, keep a running timer running; on Get, the timer is running
+
+	// WatchdogCountdownUnit is the resolution of the watchdog countdown
+	WatchdogCountdownUnit = 100 * time.Millisecond
+)
+
+// ErrWatchdogNotInitialized is returned by Reset Watchdog Timer before the timer is set
+const ErrWatchdogNotInitialized = CompletionCode(0x80)
+
+var watchdogTimerUseNames = map[WatchdogTimerUse]string{
+	WatchdogUseReserved: "Reserved",
+	WatchdogUseBIOSFRB2: "BIOS FRB2",
+	WatchdogUseBIOSPOST: "BIOS/POST",
+	WatchdogUseOSLoad:   "OS Load",
+	WatchdogUseSMSOS:    "SMS/OS",
+	WatchdogUseOEM:      "OEM",
+}
+
+var watchdogActionNames = map[WatchdogAction]string{
+	WatchdogActionNone:       "No action",
+	WatchdogActionHardReset:  "Hard Reset",
+	WatchdogActionPowerDown:  "Power Down",
+	WatchdogActionPowerCycle: "Power Cycle",
+}
+
+var watchdogPreTimeoutNames = map[WatchdogPreTimeout]string{
+	WatchdogPreTimeoutNone:      "None",
+	WatchdogPreTimeoutSMI:       "SMI",
+	WatchdogPreTimeoutNMI:       "NMI / Diagnostic Interrupt",
+	WatchdogPreTimeoutMessaging: "Messaging Interrupt",
+}
+
+func (u WatchdogTimerUse) String() string {
+	if s, ok := watchdogTimerUseNames[u]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown (0x%02x)", uint8(u))
+}
+
+func (a WatchdogAction) String() string {
+	if s, ok := watchdogActionNames[a]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown (0x%02x)", uint8(a))
+}
+
+func (p WatchdogPreTimeout) String() string {
+	if s, ok := watchdogPreTimeoutNames[p]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown (0x%02x)", uint8(p))
+}
+
+// ResetWatchdogRequest per section 27.5
+type ResetWatchdogRequest struct{}
+
+// ResetWatchdogResponse per section 27.5
+type ResetWatchdogResponse struct {
+	CompletionCode
+}
+
+// SetWatchdogRequest per section 27.6
+type SetWatchdogRequest struct {
+	TimerUse           uint8
+	TimerActions       uint8
+	PreTimeoutInterval uint8 // seconds
+	ExpirationFlags    uint8 // timer use expiration flags to clear, bit per WatchdogTimerUse
+	InitialCountdown   uint16
+}
+
+// SetWatchdogResponse per section 27.6
+type SetWatchdogResponse struct {
+	CompletionCode
+}
+
+// GetWatchdogRequest per section 27.7
+type GetWatchdogRequest struct{}
+
+// GetWatchdogResponse per section 27.7
+type GetWatchdogResponse struct {
+	CompletionCode
+	TimerUse           uint8
+	TimerActions       uint8
+	PreTimeoutInterval uint8
+	ExpirationFlags    uint8
+	InitialCountdown   uint16
+	PresentCountdown   uint16
+}
+
+// WatchdogExpirationFlag is the expiration flag bit of the given timer use
+func WatchdogExpirationFlag(use WatchdogTimerUse) uint8 {
+	return 1 << (use & 0x07)
+}
+
+// Use is the timer use
+func (r *GetWatchdogResponse) Use() WatchdogTimerUse {
+	return WatchdogTimerUse(r.TimerUse & 0x07)
+}
+
+// IsRunning tells whether the timer is counting down
+func (r *GetWatchdogResponse) IsRunning() bool {
+	return r.TimerUse&WatchdogDontStop != 0
+}
+
+// IsLogging tells whether the BMC logs the expiration to the SEL
+func (r *GetWatchdogResponse) IsLogging() bool {
+	return r.TimerUse&WatchdogDontLog == 0
+}
+
+// Action is the timeout action
+func (r *GetWatchdogResponse) Action() WatchdogAction {
+	return WatchdogAction(r.TimerActions & 0x07)
+}
+
+// PreTimeout is the pre-timeout interrupt
+func (r *GetWatchdogResponse) PreTimeout() WatchdogPreTimeout {
+	return WatchdogPreTimeout((r.TimerActions >> 4) & 0x07)
+}
+
+// Expired tells whether the timer has expired while used for the given timer use
+func (r *GetWatchdogResponse) Expired(use WatchdogTimerUse) bool {
+	return r.ExpirationFlags&WatchdogExpirationFlag(use) != 0
+}
+
+// Initial is the initial countdown
+func (r *GetWatchdogResponse) Initial() time.Duration {
+	return time.Duration(r.InitialCountdown) * WatchdogCountdownUnit
+}
+
+// Present is the present countdown
+func (r *GetWatchdogResponse) Present() time.Duration {
+	return time.Duration(r.PresentCountdown) * WatchdogCountdownUnit
+}
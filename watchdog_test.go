@@ -0,0 +1,66 @@
+/*
+Copyright (c) 2014 EOITek, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetWatchdogRequest(t *testing.T) {
+	w := &WatchdogConfig{
+		Use:                WatchdogUseSMSOS,
+		Action:             WatchdogActionPowerCycle,
+		PreTimeout:         WatchdogPreTimeoutNMI,
+		PreTimeoutInterval: 10 * time.Second,
+		Countdown:          5 * time.Minute,
+		DontStop:           true,
+		ClearFlags:         WatchdogExpirationFlag(WatchdogUseSMSOS),
+	}
+	data, err := w.request()
+	assert.NoError(t, err)
+	req := &Request{
+		NetworkFunctionApp,
+		CommandSetWatchdog,
+		data,
+	}
+	raw := requestToStrings(req)
+	assert.Equal(t, []string{"0x06", "0x24", "0x44", "0x23", "0x0a", "0x10", "0xb8", "0x0b"}, raw)
+
+	w.Countdown = 2 * time.Hour
+	_, err = w.request()
+	assert.Equal(t, ErrWatchdogCountdownRange, err)
+}
+
+func TestGetWatchdogParse(t *testing.T) {
+	res := &GetWatchdogResponse{}
+	err := responseFromString("44 01 00 10 58 02 2c 01", res)
+	assert.NoError(t, err)
+	assert.Equal(t, WatchdogUseSMSOS, res.Use())
+	assert.True(t, res.IsRunning())
+	assert.True(t, res.IsLogging())
+	assert.Equal(t, WatchdogActionHardReset, res.Action())
+	assert.Equal(t, WatchdogPreTimeoutNone, res.PreTimeout())
+	assert.True(t, res.Expired(WatchdogUseSMSOS))
+	assert.False(t, res.Expired(WatchdogUseOSLoad))
+	assert.Equal(t, time.Minute, res.Initial())
+	assert.Equal(t, 30*time.Second, res.Present())
+	assert.Equal(t, "SMS/OS", res.Use().String())
+	assert.Equal(t, "Hard Reset", res.Action().String())
+}